@@ -0,0 +1,145 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// AccessMask selects the kinds of access checked by MayAccess.
+type AccessMask uint32
+
+const (
+	// MayExecute checks for execute (search) permission.
+	MayExecute = AccessMask(0x01)
+	// MayWrite checks for write permission.
+	MayWrite = AccessMask(0x02)
+	// MayRead checks for read permission.
+	MayRead = AccessMask(0x04)
+)
+
+// MayAccess checks whether the user with the given uid, primary gid and
+// supplementary gids is allowed the access in mask to the file at path. It
+// returns nil if the access is allowed and ErrAccessDenied if it is not.
+// This allows a privileged client to check permissions on behalf of its
+// own users before acting for them.
+//
+// The path is resolved with the user's credentials, so search permission
+// on the directories leading to the file is checked by libcephfs. The
+// access to the file itself is then checked against its mode and, if the
+// mount supports them, its POSIX ACL.
+//
+// Implements:
+//  int ceph_ll_walk(struct ceph_mount_info *cmount, const char* name, Inode **i,
+//                   struct ceph_statx *stx, unsigned int want, unsigned int flags,
+//                   const UserPerm *perms);
+func (mount *MountInfo) MayAccess(path string, uid, gid int, gids []int, mask AccessMask) error {
+	perm := NewUserPerm(uid, gid, gids)
+	defer perm.Destroy()
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	var (
+		inode *C.struct_Inode
+		stx   C.struct_ceph_statx
+	)
+	want := C.uint(StatxMode | StatxUid | StatxGid)
+	ret := C.ceph_ll_walk(mount.mount, cPath, &inode, &stx, want, 0, perm.userPerm)
+	if ret != 0 {
+		return getError(ret)
+	}
+	C.ceph_ll_put(mount.mount, inode)
+	st := cStructToCephStatx(stx)
+
+	acl, err := mount.GetACL(path, ACLTypeAccess)
+	if err != nil && err != CephFSError(-C.EOPNOTSUPP) {
+		return err
+	}
+	if !checkAccess(st, acl, uint32(uid), append([]int{gid}, gids...), mask) {
+		return ErrAccessDenied
+	}
+	return nil
+}
+
+// checkAccess implements the POSIX permission check of the requested
+// access for a user, optionally using an access ACL.
+func checkAccess(st *CephStatx, acl ACL, uid uint32, gids []int, mask AccessMask) bool {
+	mode := uint32(st.Mode)
+	if uid == 0 {
+		// root may read and write anything, but may only execute files
+		// that are executable by someone
+		if mask&MayExecute == 0 || mode&syscall.S_IFMT == syscall.S_IFDIR {
+			return true
+		}
+		return mode&0111 != 0
+	}
+
+	granted := func(perm uint32) bool {
+		return AccessMask(perm)&mask == mask
+	}
+	inGroup := func(g uint32) bool {
+		for _, id := range gids {
+			if uint32(id) == g {
+				return true
+			}
+		}
+		return false
+	}
+
+	if uid == st.Uid {
+		return granted(mode >> 6 & 7)
+	}
+	if len(acl) == 0 {
+		if inGroup(st.Gid) {
+			return granted(mode >> 3 & 7)
+		}
+		return granted(mode & 7)
+	}
+
+	aclMask := uint32(7)
+	for _, e := range acl {
+		if e.Tag == ACLMask {
+			aclMask = uint32(e.Perm)
+		}
+	}
+	for _, e := range acl {
+		if e.Tag == ACLUser && e.ID == uid {
+			return granted(uint32(e.Perm) & aclMask)
+		}
+	}
+	groupMatched := false
+	for _, e := range acl {
+		var match bool
+		switch e.Tag {
+		case ACLGroupObj:
+			match = inGroup(st.Gid)
+		case ACLGroup:
+			match = inGroup(e.ID)
+		}
+		if !match {
+			continue
+		}
+		groupMatched = true
+		if granted(uint32(e.Perm) & aclMask) {
+			return true
+		}
+	}
+	if groupMatched {
+		return false
+	}
+	for _, e := range acl {
+		if e.Tag == ACLOther {
+			return granted(uint32(e.Perm))
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAccess(t *testing.T) {
+	st := &CephStatx{Mode: 0100640, Uid: 1010, Gid: 2000}
+
+	// owner
+	assert.True(t, checkAccess(st, nil, 1010, []int{1010}, MayRead|MayWrite))
+	assert.False(t, checkAccess(st, nil, 1010, []int{1010}, MayExecute))
+	// group
+	assert.True(t, checkAccess(st, nil, 1020, []int{1020, 2000}, MayRead))
+	assert.False(t, checkAccess(st, nil, 1020, []int{1020, 2000}, MayWrite))
+	// other
+	assert.False(t, checkAccess(st, nil, 1020, []int{1020}, MayRead))
+	// root
+	assert.True(t, checkAccess(st, nil, 0, []int{0}, MayRead|MayWrite))
+	assert.False(t, checkAccess(st, nil, 0, []int{0}, MayExecute))
+
+	acl := ACL{
+		{Tag: ACLUserObj, Perm: ACLRead | ACLWrite, ID: ACLUndefinedID},
+		{Tag: ACLUser, Perm: ACLRead | ACLWrite, ID: 1030},
+		{Tag: ACLGroupObj, Perm: ACLRead, ID: ACLUndefinedID},
+		{Tag: ACLGroup, Perm: ACLRead | ACLWrite, ID: 3000},
+		{Tag: ACLMask, Perm: ACLRead, ID: ACLUndefinedID},
+		{Tag: ACLOther, Perm: 0, ID: ACLUndefinedID},
+	}
+	// named user, limited by the mask
+	assert.True(t, checkAccess(st, acl, 1030, []int{1030}, MayRead))
+	assert.False(t, checkAccess(st, acl, 1030, []int{1030}, MayWrite))
+	// named group, limited by the mask
+	assert.True(t, checkAccess(st, acl, 1040, []int{3000}, MayRead))
+	assert.False(t, checkAccess(st, acl, 1040, []int{3000}, MayWrite))
+	// other
+	assert.False(t, checkAccess(st, acl, 1040, []int{1040}, MayRead))
+}
+
+func TestMayAccess(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dname := "/mayaccess"
+	require.NoError(t, mount.MakeDir(dname, 0755))
+	defer func() { assert.NoError(t, mount.RemoveAll(dname)) }()
+	fname := dname + "/file"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0640)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	require.NoError(t, mount.Chown(fname, 1010, 1010))
+
+	// bob (uid 1010) owns the file
+	assert.NoError(t, mount.MayAccess(fname, 1010, 1010, nil, MayRead|MayWrite))
+	assert.Equal(t, ErrAccessDenied, mount.MayAccess(fname, 1010, 1010, nil, MayExecute))
+	// members of bob's group may read
+	assert.NoError(t, mount.MayAccess(fname, 2000, 2000, []int{1010}, MayRead))
+	assert.Equal(t, ErrAccessDenied, mount.MayAccess(fname, 2000, 2000, []int{1010}, MayWrite))
+	// others may not
+	assert.Equal(t, ErrAccessDenied, mount.MayAccess(fname, 2000, 2000, nil, MayRead))
+
+	// search permission on the parent directory is needed
+	require.NoError(t, mount.Chmod(dname, 0700))
+	assert.Equal(t, ErrAccessDenied, mount.MayAccess(fname, 1010, 1010, nil, MayRead))
+
+	assert.Equal(t, ErrNotExist, mount.MayAccess(dname+"/none", 0, 0, nil, MayRead))
+}
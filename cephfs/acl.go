@@ -0,0 +1,139 @@
+package cephfs
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// ACLType selects which POSIX ACL of a file to operate on.
+type ACLType string
+
+const (
+	// ACLTypeAccess is the ACL checked when accessing the file.
+	ACLTypeAccess = ACLType("system.posix_acl_access")
+	// ACLTypeDefault is the ACL inherited by the new files of a
+	// directory.
+	ACLTypeDefault = ACLType("system.posix_acl_default")
+)
+
+// ACLTag identifies whom an ACLEntry applies to.
+type ACLTag uint16
+
+const (
+	// ACLUserObj applies to the owner of the file.
+	ACLUserObj = ACLTag(0x01)
+	// ACLUser applies to the user identified by the entry's ID.
+	ACLUser = ACLTag(0x02)
+	// ACLGroupObj applies to the group of the file.
+	ACLGroupObj = ACLTag(0x04)
+	// ACLGroup applies to the group identified by the entry's ID.
+	ACLGroup = ACLTag(0x08)
+	// ACLMask limits the permissions granted by the ACLUser, ACLGroupObj
+	// and ACLGroup entries.
+	ACLMask = ACLTag(0x10)
+	// ACLOther applies to everyone else.
+	ACLOther = ACLTag(0x20)
+)
+
+// ACLPerm holds the permissions granted by an ACLEntry.
+type ACLPerm uint16
+
+const (
+	// ACLExecute grants execute (search) permission.
+	ACLExecute = ACLPerm(0x01)
+	// ACLWrite grants write permission.
+	ACLWrite = ACLPerm(0x02)
+	// ACLRead grants read permission.
+	ACLRead = ACLPerm(0x04)
+)
+
+// ACLUndefinedID is the ID of the entries whose tag does not need one.
+const ACLUndefinedID = ^uint32(0)
+
+// ACLEntry is a single entry of a POSIX ACL.
+type ACLEntry struct {
+	Tag  ACLTag
+	Perm ACLPerm
+	// ID is the uid or gid of ACLUser and ACLGroup entries. It should be
+	// ACLUndefinedID for the other tags.
+	ID uint32
+}
+
+// ACL is a POSIX access control list.
+type ACL []ACLEntry
+
+const (
+	aclXattrVersion   = 2
+	aclXattrHeaderLen = 4
+	aclXattrEntryLen  = 8
+)
+
+// Encode returns the ACL in the binary format used by the POSIX ACL
+// extended attributes. The entries are sorted as the format requires.
+func (acl ACL) Encode() []byte {
+	entries := make(ACL, len(acl))
+	copy(entries, acl)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Tag != entries[j].Tag {
+			return entries[i].Tag < entries[j].Tag
+		}
+		return entries[i].ID < entries[j].ID
+	})
+
+	buf := make([]byte, aclXattrHeaderLen+aclXattrEntryLen*len(entries))
+	binary.LittleEndian.PutUint32(buf, aclXattrVersion)
+	for i, e := range entries {
+		b := buf[aclXattrHeaderLen+aclXattrEntryLen*i:]
+		binary.LittleEndian.PutUint16(b[0:], uint16(e.Tag))
+		binary.LittleEndian.PutUint16(b[2:], uint16(e.Perm))
+		binary.LittleEndian.PutUint32(b[4:], e.ID)
+	}
+	return buf
+}
+
+// DecodeACL parses a POSIX ACL extended attribute value.
+func DecodeACL(buf []byte) (ACL, error) {
+	if len(buf) < aclXattrHeaderLen ||
+		(len(buf)-aclXattrHeaderLen)%aclXattrEntryLen != 0 ||
+		binary.LittleEndian.Uint32(buf) != aclXattrVersion {
+		return nil, ErrInvalid
+	}
+	count := (len(buf) - aclXattrHeaderLen) / aclXattrEntryLen
+	acl := make(ACL, count)
+	for i := range acl {
+		b := buf[aclXattrHeaderLen+aclXattrEntryLen*i:]
+		acl[i] = ACLEntry{
+			Tag:  ACLTag(binary.LittleEndian.Uint16(b[0:])),
+			Perm: ACLPerm(binary.LittleEndian.Uint16(b[2:])),
+			ID:   binary.LittleEndian.Uint32(b[4:]),
+		}
+	}
+	return acl, nil
+}
+
+// GetACL returns the ACL of the given type for the file at path. If the
+// file has no such ACL nil is returned, in which case only the mode of the
+// file controls access.
+//
+// ACLs are only supported by mounts configured with the client_acl_type
+// option set to "posix_acl".
+func (mount *MountInfo) GetACL(path string, aclType ACLType) (ACL, error) {
+	buf, err := mount.GetXattr(path, string(aclType))
+	if err == errNoData {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return DecodeACL(buf)
+}
+
+// SetACL sets the ACL of the given type for the file at path. The file
+// system updates the mode of the file to match an access ACL.
+func (mount *MountInfo) SetACL(path string, aclType ACLType, acl ACL) error {
+	return mount.SetXattr(path, string(aclType), acl.Encode(), XattrDefault)
+}
+
+// RemoveACL removes the ACL of the given type from the file at path.
+func (mount *MountInfo) RemoveACL(path string, aclType ACLType) error {
+	return mount.RemoveXattr(path, string(aclType))
+}
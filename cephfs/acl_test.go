@@ -0,0 +1,100 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLEncoding(t *testing.T) {
+	acl := ACL{
+		{Tag: ACLOther, Perm: ACLRead, ID: ACLUndefinedID},
+		{Tag: ACLUser, Perm: ACLRead | ACLWrite, ID: 1010},
+		{Tag: ACLUserObj, Perm: ACLRead | ACLWrite | ACLExecute, ID: ACLUndefinedID},
+		{Tag: ACLGroupObj, Perm: ACLRead, ID: ACLUndefinedID},
+		{Tag: ACLMask, Perm: ACLRead | ACLWrite, ID: ACLUndefinedID},
+	}
+	buf := acl.Encode()
+	assert.Len(t, buf, 4+8*5)
+	assert.Equal(t, []byte{2, 0, 0, 0}, buf[:4])
+	// user_obj entry sorts first
+	assert.Equal(t, []byte{1, 0, 7, 0, 0xff, 0xff, 0xff, 0xff}, buf[4:12])
+
+	decoded, err := DecodeACL(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, ACL{
+		{Tag: ACLUserObj, Perm: ACLRead | ACLWrite | ACLExecute, ID: ACLUndefinedID},
+		{Tag: ACLUser, Perm: ACLRead | ACLWrite, ID: 1010},
+		{Tag: ACLGroupObj, Perm: ACLRead, ID: ACLUndefinedID},
+		{Tag: ACLMask, Perm: ACLRead | ACLWrite, ID: ACLUndefinedID},
+		{Tag: ACLOther, Perm: ACLRead, ID: ACLUndefinedID},
+	}, decoded)
+	// the input is left unsorted
+	assert.Equal(t, ACLOther, acl[0].Tag)
+
+	_, err = DecodeACL(nil)
+	assert.Equal(t, ErrInvalid, err)
+	_, err = DecodeACL(buf[:7])
+	assert.Equal(t, ErrInvalid, err)
+	_, err = DecodeACL([]byte{1, 0, 0, 0})
+	assert.Equal(t, ErrInvalid, err)
+}
+
+func fsConnectWithACLs(t *testing.T) *MountInfo {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	require.NoError(t, mount.ReadDefaultConfigFile())
+	require.NoError(t, mount.SetConfigOption("client_acl_type", "posix_acl"))
+	require.NoError(t, mount.Mount())
+	return mount
+}
+
+func TestGetSetACL(t *testing.T) {
+	mount := fsConnectWithACLs(t)
+	defer mount.Unmount()
+
+	dname := "/acls"
+	require.NoError(t, mount.MakeDir(dname, 0750))
+	defer func() { assert.NoError(t, mount.RemoveAll(dname)) }()
+	fname := dname + "/file"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0600)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	acl, err := mount.GetACL(fname, ACLTypeAccess)
+	assert.NoError(t, err)
+	assert.Nil(t, acl)
+
+	acl = ACL{
+		{Tag: ACLUserObj, Perm: ACLRead | ACLWrite, ID: ACLUndefinedID},
+		{Tag: ACLUser, Perm: ACLRead, ID: 1010},
+		{Tag: ACLGroupObj, Perm: 0, ID: ACLUndefinedID},
+		{Tag: ACLMask, Perm: ACLRead, ID: ACLUndefinedID},
+		{Tag: ACLOther, Perm: 0, ID: ACLUndefinedID},
+	}
+	require.NoError(t, mount.SetACL(fname, ACLTypeAccess, acl))
+	got, err := mount.GetACL(fname, ACLTypeAccess)
+	assert.NoError(t, err)
+	assert.Equal(t, acl, got)
+
+	// the group bits of the mode reflect the mask entry
+	st, err := mount.Statx(fname, StatxMode, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0640, st.Mode&0777)
+
+	// default ACLs are inherited by new files
+	require.NoError(t, mount.SetACL(dname, ACLTypeDefault, acl))
+	f, err = mount.Open(dname+"/inherits", os.O_WRONLY|os.O_CREATE, 0600)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	got, err = mount.GetACL(dname+"/inherits", ACLTypeAccess)
+	assert.NoError(t, err)
+	assert.Contains(t, got, ACLEntry{Tag: ACLUser, Perm: ACLRead, ID: 1010})
+
+	assert.NoError(t, mount.RemoveACL(fname, ACLTypeAccess))
+	acl, err = mount.GetACL(fname, ACLTypeAccess)
+	assert.NoError(t, err)
+	assert.Nil(t, acl)
+}
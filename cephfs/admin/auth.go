@@ -0,0 +1,93 @@
+package admin
+
+import (
+	"strings"
+)
+
+// AccessLevel is the level of access granted to a cephx id for a
+// subvolume.
+type AccessLevel string
+
+const (
+	// AccessReadOnly grants read only access to the subvolume.
+	AccessReadOnly = AccessLevel("r")
+	// AccessReadWrite grants read and write access to the subvolume.
+	AccessReadWrite = AccessLevel("rw")
+)
+
+// SubVolumeAuthOptions are used to specify optional values when
+// authorizing a cephx id for a subvolume.
+type SubVolumeAuthOptions struct {
+	// AccessLevel is the access granted. By default read-write access is
+	// granted.
+	AccessLevel AccessLevel
+	// TenantID identifies the tenant the id belongs to. Ids created for
+	// one tenant can not be used by another.
+	TenantID string
+	// AllowExistingID permits authorizing an id that already exists and
+	// was not created by the volumes module.
+	AllowExistingID bool
+}
+
+// AuthorizeSubVolume grants the cephx id access to the subvolume,
+// creating the id if needed. The secret key of the id is returned.
+//
+// Similar To:
+//  ceph fs subvolume authorize <volume> <subvolume> <id> --group_name=<group> ...
+func (fsa *FSAdmin) AuthorizeSubVolume(
+	volume, group, subvolume, id string, o *SubVolumeAuthOptions) (string, error) {
+
+	args := withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume authorize",
+		"vol_name": volume,
+		"sub_name": subvolume,
+		"auth_id":  id,
+	}, group)
+	if o != nil {
+		if o.AccessLevel != "" {
+			args["access_level"] = string(o.AccessLevel)
+		}
+		if o.TenantID != "" {
+			args["tenant_id"] = o.TenantID
+		}
+		if o.AllowExistingID {
+			args["allow_existing_id"] = true
+		}
+	}
+	buf, err := fsa.mgrCommand(args)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// DeauthorizeSubVolume revokes the access of the cephx id to the
+// subvolume. Clients already using the id keep their access until they
+// are evicted with EvictSubVolumeClients.
+//
+// Similar To:
+//  ceph fs subvolume deauthorize <volume> <subvolume> <id> --group_name=<group>
+func (fsa *FSAdmin) DeauthorizeSubVolume(volume, group, subvolume, id string) error {
+	_, err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume deauthorize",
+		"vol_name": volume,
+		"sub_name": subvolume,
+		"auth_id":  id,
+	}, group))
+	return err
+}
+
+// EvictSubVolumeClients evicts the clients using the cephx id that have
+// mounted the subvolume.
+//
+// Similar To:
+//  ceph fs subvolume evict <volume> <subvolume> <id> --group_name=<group>
+func (fsa *FSAdmin) EvictSubVolumeClients(volume, group, subvolume, id string) error {
+	_, err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume evict",
+		"vol_name": volume,
+		"sub_name": subvolume,
+		"auth_id":  id,
+	}, group))
+	return err
+}
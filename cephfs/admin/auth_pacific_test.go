@@ -0,0 +1,35 @@
+// +build !luminous,!mimic,!nautilus,!octopus
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizeSubVolume(t *testing.T) {
+	fsa := getFSAdmin(t)
+	subvolume := "sv-auth"
+	id := "sv-auth-user"
+
+	require.NoError(t, fsa.CreateSubVolume(testVolume, NoGroup, subvolume, nil))
+	defer func() {
+		assert.NoError(t, fsa.RemoveSubVolume(testVolume, NoGroup, subvolume))
+	}()
+
+	key, err := fsa.AuthorizeSubVolume(testVolume, NoGroup, subvolume, id, nil)
+	require.NoError(t, err)
+	assert.NotEmpty(t, key)
+
+	// authorizing again returns the same key
+	key2, err := fsa.AuthorizeSubVolume(testVolume, NoGroup, subvolume, id,
+		&SubVolumeAuthOptions{AccessLevel: AccessReadOnly})
+	assert.NoError(t, err)
+	assert.Equal(t, key, key2)
+
+	assert.NoError(t, fsa.EvictSubVolumeClients(testVolume, NoGroup, subvolume, id))
+	assert.NoError(t, fsa.DeauthorizeSubVolume(testVolume, NoGroup, subvolume, id))
+	assert.Error(t, fsa.DeauthorizeSubVolume(testVolume, NoGroup, subvolume, id))
+}
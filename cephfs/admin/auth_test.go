@@ -0,0 +1,28 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizeSubVolumeCommand(t *testing.T) {
+	fake := &fakeCommander{response: []byte("AQBFc1RfAAAAABAAy1Ld8Q3Ocd1NEtE4E4vpMw==\n")}
+	fsa := NewFromConn(fake)
+
+	key, err := fsa.AuthorizeSubVolume("cephfs", NoGroup, "sv1", "alice",
+		&SubVolumeAuthOptions{AccessLevel: AccessReadOnly, TenantID: "t1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "AQBFc1RfAAAAABAAy1Ld8Q3Ocd1NEtE4E4vpMw==", key)
+	require.Len(t, fake.cmds, 1)
+	assert.Equal(t, map[string]interface{}{
+		"prefix":       "fs subvolume authorize",
+		"vol_name":     "cephfs",
+		"sub_name":     "sv1",
+		"auth_id":      "alice",
+		"access_level": "r",
+		"tenant_id":    "t1",
+		"format":       "json",
+	}, fake.cmds[0])
+}
@@ -0,0 +1,7 @@
+/*
+Package admin contains a set of APIs for administering CephFS file systems,
+such as managing volumes and subvolumes. The APIs are implemented using the
+commands of the ceph-mgr volumes module and so require a cluster running
+Ceph Nautilus or newer.
+*/
+package admin
@@ -0,0 +1,134 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ceph/go-ceph/rados"
+)
+
+// RadosCommander provides an interface to the functions of a rados.Conn
+// used to send commands to the cluster. It exists so that FSAdmin can be
+// used with connections created elsewhere.
+type RadosCommander interface {
+	MgrCommand(args [][]byte) ([]byte, string, error)
+	MonCommand(args []byte) ([]byte, string, error)
+}
+
+// FSAdmin is used to administer CephFS file systems.
+type FSAdmin struct {
+	conn RadosCommander
+}
+
+// CommandError is returned when the cluster fails to execute a command.
+type CommandError struct {
+	// Err is the error returned for the command, usually a
+	// rados.RadosError.
+	Err error
+	// Status is the status message returned by the cluster, which
+	// typically describes the problem.
+	Status string
+}
+
+// Error returns the error string for the CommandError type.
+func (e *CommandError) Error() string {
+	if e.Status == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Err, e.Status)
+}
+
+// New creates an FSAdmin using a new connection to the cluster configured
+// by the default configuration file.
+func New() (*FSAdmin, error) {
+	conn, err := rados.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.ReadDefaultConfigFile(); err != nil {
+		return nil, err
+	}
+	if err := conn.Connect(); err != nil {
+		return nil, err
+	}
+	return NewFromConn(conn), nil
+}
+
+// NewFromConn creates an FSAdmin using an existing connection to the
+// cluster. The connection must remain open for as long as the FSAdmin is
+// in use.
+func NewFromConn(conn RadosCommander) *FSAdmin {
+	return &FSAdmin{conn: conn}
+}
+
+// marshalCommand converts the command arguments to JSON, requesting JSON
+// formatted output.
+func marshalCommand(args map[string]interface{}) ([]byte, error) {
+	cmd := map[string]interface{}{"format": "json"}
+	for k, v := range args {
+		cmd[k] = v
+	}
+	return json.Marshal(cmd)
+}
+
+// mgrCommand sends a command to the ceph-mgr and returns the output.
+func (fsa *FSAdmin) mgrCommand(args map[string]interface{}) ([]byte, error) {
+	cmd, err := marshalCommand(args)
+	if err != nil {
+		return nil, err
+	}
+	buf, status, err := fsa.conn.MgrCommand([][]byte{cmd})
+	if err != nil {
+		return nil, &CommandError{Err: err, Status: status}
+	}
+	return buf, nil
+}
+
+// monCommand sends a command to the monitors and returns the output.
+func (fsa *FSAdmin) monCommand(args map[string]interface{}) ([]byte, error) {
+	cmd, err := marshalCommand(args)
+	if err != nil {
+		return nil, err
+	}
+	buf, status, err := fsa.conn.MonCommand(cmd)
+	if err != nil {
+		return nil, &CommandError{Err: err, Status: status}
+	}
+	return buf, nil
+}
+
+// mgrCommandJSON sends a command to the ceph-mgr and decodes the JSON
+// output into v.
+func (fsa *FSAdmin) mgrCommandJSON(args map[string]interface{}, v interface{}) error {
+	buf, err := fsa.mgrCommand(args)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, v)
+}
+
+// monCommandJSON sends a command to the monitors and decodes the JSON
+// output into v.
+func (fsa *FSAdmin) monCommandJSON(args map[string]interface{}, v interface{}) error {
+	buf, err := fsa.monCommand(args)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, v)
+}
+
+// listNamedJSON sends a command to the ceph-mgr that returns a list of
+// objects with a name field, and returns the names.
+func (fsa *FSAdmin) listNamedJSON(args map[string]interface{}) ([]string, error) {
+	var entries []struct {
+		Name string `json:"name"`
+	}
+	if err := fsa.mgrCommandJSON(args, &entries); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name
+	}
+	return names, nil
+}
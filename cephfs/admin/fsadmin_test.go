@@ -0,0 +1,85 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cachedFSAdmin *FSAdmin
+
+// getFSAdmin returns an FSAdmin connected to the test cluster, reusing
+// the connection between tests.
+func getFSAdmin(t *testing.T) *FSAdmin {
+	if cachedFSAdmin != nil {
+		return cachedFSAdmin
+	}
+	fsa, err := New()
+	require.NoError(t, err)
+	require.NotNil(t, fsa)
+	cachedFSAdmin = fsa
+	return fsa
+}
+
+// fakeCommander records the commands it is sent and replies with canned
+// responses, allowing the commands to be checked without a cluster.
+type fakeCommander struct {
+	cmds     []map[string]interface{}
+	response []byte
+	status   string
+	err      error
+}
+
+func (f *fakeCommander) record(buf []byte) {
+	cmd := map[string]interface{}{}
+	if err := json.Unmarshal(buf, &cmd); err != nil {
+		panic(err)
+	}
+	f.cmds = append(f.cmds, cmd)
+}
+
+func (f *fakeCommander) MgrCommand(args [][]byte) ([]byte, string, error) {
+	for _, arg := range args {
+		f.record(arg)
+	}
+	return f.response, f.status, f.err
+}
+
+func (f *fakeCommander) MonCommand(args []byte) ([]byte, string, error) {
+	f.record(args)
+	return f.response, f.status, f.err
+}
+
+func TestCommandError(t *testing.T) {
+	fake := &fakeCommander{
+		err:    errors.New("rados: ret=-2"),
+		status: "volume 'x' not found",
+	}
+	fsa := NewFromConn(fake)
+	_, err := fsa.mgrCommand(map[string]interface{}{"prefix": "fs volume ls"})
+	require.Error(t, err)
+	cerr, ok := err.(*CommandError)
+	require.True(t, ok)
+	assert.Equal(t, fake.err, cerr.Err)
+	assert.Equal(t, "rados: ret=-2: volume 'x' not found", err.Error())
+
+	fake.status = ""
+	_, err = fsa.monCommand(map[string]interface{}{"prefix": "fs ls"})
+	assert.Equal(t, "rados: ret=-2", err.Error())
+}
+
+func TestMarshalCommand(t *testing.T) {
+	buf, err := marshalCommand(map[string]interface{}{
+		"prefix": "fs volume ls",
+	})
+	assert.NoError(t, err)
+	cmd := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf, &cmd))
+	assert.Equal(t, map[string]interface{}{
+		"prefix": "fs volume ls",
+		"format": "json",
+	}, cmd)
+}
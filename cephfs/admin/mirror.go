@@ -0,0 +1,242 @@
+package admin
+
+// The snapshot mirroring functions require the mirroring mgr module,
+// available in Ceph Pacific or newer, to be enabled:
+//  ceph mgr module enable mirroring
+
+// EnableSnapshotMirror enables snapshot mirroring for the file system.
+//
+// Similar To:
+//  ceph fs snapshot mirror enable <fs_name>
+func (fsa *FSAdmin) EnableSnapshotMirror(fsName string) error {
+	_, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":  "fs snapshot mirror enable",
+		"fs_name": fsName,
+	})
+	return err
+}
+
+// DisableSnapshotMirror disables snapshot mirroring for the file system.
+//
+// Similar To:
+//  ceph fs snapshot mirror disable <fs_name>
+func (fsa *FSAdmin) DisableSnapshotMirror(fsName string) error {
+	_, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":  "fs snapshot mirror disable",
+		"fs_name": fsName,
+	})
+	return err
+}
+
+// MirrorPeerOptions are used to specify optional values when adding a
+// mirror peer.
+type MirrorPeerOptions struct {
+	// RemoteFSName is the name of the file system on the remote cluster.
+	// By default the name of the local file system is used.
+	RemoteFSName string
+	// RemoteMonHost and CephxKey allow connecting to the remote cluster
+	// without a local configuration file for it.
+	RemoteMonHost string
+	CephxKey      string
+}
+
+// AddMirrorPeer adds a peer file system to which the snapshots of the file
+// system are mirrored. The remote cluster is given as a spec of the form
+// client.<id>@<cluster>.
+//
+// Similar To:
+//  ceph fs snapshot mirror peer_add <fs_name> <remote_cluster_spec> ...
+func (fsa *FSAdmin) AddMirrorPeer(fsName, remoteClusterSpec string, o *MirrorPeerOptions) error {
+	args := map[string]interface{}{
+		"prefix":              "fs snapshot mirror peer_add",
+		"fs_name":             fsName,
+		"remote_cluster_spec": remoteClusterSpec,
+	}
+	if o != nil {
+		if o.RemoteFSName != "" {
+			args["remote_fs_name"] = o.RemoteFSName
+		}
+		if o.RemoteMonHost != "" {
+			args["remote_mon_host"] = o.RemoteMonHost
+		}
+		if o.CephxKey != "" {
+			args["cephx_key"] = o.CephxKey
+		}
+	}
+	_, err := fsa.mgrCommand(args)
+	return err
+}
+
+// RemoveMirrorPeer removes the peer, identified by its UUID, from the file
+// system.
+//
+// Similar To:
+//  ceph fs snapshot mirror peer_remove <fs_name> <peer_uuid>
+func (fsa *FSAdmin) RemoveMirrorPeer(fsName, uuid string) error {
+	_, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":    "fs snapshot mirror peer_remove",
+		"fs_name":   fsName,
+		"peer_uuid": uuid,
+	})
+	return err
+}
+
+// MirrorPeer describes a peer of a mirrored file system.
+type MirrorPeer struct {
+	ClientName string `json:"client_name"`
+	SiteName   string `json:"site_name"`
+	FSName     string `json:"fs_name"`
+	MonHost    string `json:"mon_host"`
+}
+
+// ListMirrorPeers returns the peers of the file system, keyed by the UUID
+// of the peer.
+//
+// Similar To:
+//  ceph fs snapshot mirror peer_list <fs_name>
+func (fsa *FSAdmin) ListMirrorPeers(fsName string) (map[string]MirrorPeer, error) {
+	peers := map[string]MirrorPeer{}
+	err := fsa.mgrCommandJSON(map[string]interface{}{
+		"prefix":  "fs snapshot mirror peer_list",
+		"fs_name": fsName,
+	}, &peers)
+	if err != nil {
+		return nil, err
+	}
+	return peers, nil
+}
+
+// CreateMirrorPeerToken creates a bootstrap token on the cluster of the
+// peer file system. The token is imported on the primary cluster with
+// ImportMirrorPeerToken to add the peer, avoiding the need to share
+// configuration files between the clusters.
+//
+// Similar To:
+//  ceph fs snapshot mirror peer_bootstrap create <fs_name> <client_entity> <site_name>
+func (fsa *FSAdmin) CreateMirrorPeerToken(fsName, clientEntity, siteName string) (string, error) {
+	var out struct {
+		Token string `json:"token"`
+	}
+	err := fsa.mgrCommandJSON(map[string]interface{}{
+		"prefix":      "fs snapshot mirror peer_bootstrap create",
+		"fs_name":     fsName,
+		"client_name": clientEntity,
+		"site_name":   siteName,
+	}, &out)
+	if err != nil {
+		return "", err
+	}
+	return out.Token, nil
+}
+
+// ImportMirrorPeerToken adds the peer described by a bootstrap token
+// created by CreateMirrorPeerToken.
+//
+// Similar To:
+//  ceph fs snapshot mirror peer_bootstrap import <fs_name> <token>
+func (fsa *FSAdmin) ImportMirrorPeerToken(fsName, token string) error {
+	_, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":  "fs snapshot mirror peer_bootstrap import",
+		"fs_name": fsName,
+		"token":   token,
+	})
+	return err
+}
+
+// AddMirrorDir adds a directory, whose snapshots are to be mirrored, to the
+// file system.
+//
+// Similar To:
+//  ceph fs snapshot mirror add <fs_name> <path>
+func (fsa *FSAdmin) AddMirrorDir(fsName, path string) error {
+	_, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":  "fs snapshot mirror add",
+		"fs_name": fsName,
+		"path":    path,
+	})
+	return err
+}
+
+// RemoveMirrorDir stops mirroring the snapshots of the directory.
+//
+// Similar To:
+//  ceph fs snapshot mirror remove <fs_name> <path>
+func (fsa *FSAdmin) RemoveMirrorDir(fsName, path string) error {
+	_, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":  "fs snapshot mirror remove",
+		"fs_name": fsName,
+		"path":    path,
+	})
+	return err
+}
+
+// MirrorDirMap reports which mirror daemon is responsible for a directory.
+type MirrorDirMap struct {
+	InstanceID   string  `json:"instance_id"`
+	LastShuffled float64 `json:"last_shuffled"`
+	State        string  `json:"state"`
+	Reason       string  `json:"reason"`
+}
+
+// MirrorDirMap returns the mapping of a mirrored directory to the mirror
+// daemon instance synchronizing it.
+//
+// Similar To:
+//  ceph fs snapshot mirror dirmap <fs_name> <path>
+func (fsa *FSAdmin) MirrorDirMap(fsName, path string) (*MirrorDirMap, error) {
+	var dm MirrorDirMap
+	err := fsa.mgrCommandJSON(map[string]interface{}{
+		"prefix":  "fs snapshot mirror dirmap",
+		"fs_name": fsName,
+		"path":    path,
+	}, &dm)
+	if err != nil {
+		return nil, err
+	}
+	return &dm, nil
+}
+
+// MirrorPeerStatus reports the synchronization status of a peer.
+type MirrorPeerStatus struct {
+	UUID   string `json:"uuid"`
+	Remote struct {
+		ClientName  string `json:"client_name"`
+		ClusterName string `json:"cluster_name"`
+		FSName      string `json:"fs_name"`
+	} `json:"remote"`
+	Stats struct {
+		FailureCount  uint64 `json:"failure_count"`
+		RecoveryCount uint64 `json:"recovery_count"`
+	} `json:"stats"`
+}
+
+// MirrorFSStatus reports the status of a file system being mirrored.
+type MirrorFSStatus struct {
+	FilesystemID   int64              `json:"filesystem_id"`
+	Name           string             `json:"name"`
+	DirectoryCount uint64             `json:"directory_count"`
+	Peers          []MirrorPeerStatus `json:"peers"`
+}
+
+// MirrorDaemonStatus reports the status of a mirror daemon.
+type MirrorDaemonStatus struct {
+	DaemonID    int64            `json:"daemon_id"`
+	Filesystems []MirrorFSStatus `json:"filesystems"`
+}
+
+// MirrorDaemonStatus returns the status of the mirror daemons of the
+// cluster, including the number of failed and recovered synchronizations
+// for each peer.
+//
+// Similar To:
+//  ceph fs snapshot mirror daemon status
+func (fsa *FSAdmin) MirrorDaemonStatus() ([]MirrorDaemonStatus, error) {
+	var status []MirrorDaemonStatus
+	err := fsa.mgrCommandJSON(map[string]interface{}{
+		"prefix": "fs snapshot mirror daemon status",
+	}, &status)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,46 @@
+// +build !luminous,!mimic,!nautilus,!octopus
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotMirror(t *testing.T) {
+	fsa := getFSAdmin(t)
+
+	_, err := fsa.monCommand(map[string]interface{}{
+		"prefix": "mgr module enable",
+		"module": "mirroring",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, fsa.EnableSnapshotMirror(testVolume))
+	defer func() {
+		assert.NoError(t, fsa.DisableSnapshotMirror(testVolume))
+	}()
+
+	peers, err := fsa.ListMirrorPeers(testVolume)
+	assert.NoError(t, err)
+	assert.Len(t, peers, 0)
+
+	// the directory does not need to exist to be added
+	require.NoError(t, fsa.AddMirrorDir(testVolume, "/mirrored"))
+	assert.Error(t, fsa.AddMirrorDir(testVolume, "/mirrored"))
+
+	dm, err := fsa.MirrorDirMap(testVolume, "/mirrored")
+	assert.NoError(t, err)
+	if assert.NotNil(t, dm) {
+		assert.NotEmpty(t, dm.State)
+	}
+
+	assert.NoError(t, fsa.RemoveMirrorDir(testVolume, "/mirrored"))
+	_, err = fsa.MirrorDirMap(testVolume, "/mirrored")
+	assert.Error(t, err)
+
+	_, err = fsa.MirrorDaemonStatus()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMirrorResults(t *testing.T) {
+	fake := &fakeCommander{}
+	fsa := NewFromConn(fake)
+
+	t.Run("peerList", func(t *testing.T) {
+		fake.response = []byte(`{"a2dc7784-e7a1-4723-b103-03ee8d8768f8": {
+			"client_name": "client.mirror_remote",
+			"site_name": "remote-site",
+			"fs_name": "backup_fs",
+			"mon_host": "[v2:10.0.0.1:3300,v1:10.0.0.1:6789]"
+		}}`)
+		peers, err := fsa.ListMirrorPeers("cephfs")
+		assert.NoError(t, err)
+		require.Contains(t, peers, "a2dc7784-e7a1-4723-b103-03ee8d8768f8")
+		peer := peers["a2dc7784-e7a1-4723-b103-03ee8d8768f8"]
+		assert.Equal(t, "client.mirror_remote", peer.ClientName)
+		assert.Equal(t, "backup_fs", peer.FSName)
+	})
+
+	t.Run("dirMap", func(t *testing.T) {
+		fake.response = []byte(`{"instance_id": "404148", "last_shuffled": 1601284516.10986, "state": "mapped"}`)
+		dm, err := fsa.MirrorDirMap("cephfs", "/d0")
+		assert.NoError(t, err)
+		require.NotNil(t, dm)
+		assert.Equal(t, "404148", dm.InstanceID)
+		assert.Equal(t, "mapped", dm.State)
+	})
+
+	t.Run("daemonStatus", func(t *testing.T) {
+		fake.response = []byte(`[{"daemon_id": 4115, "filesystems": [{
+			"filesystem_id": 1,
+			"name": "cephfs",
+			"directory_count": 2,
+			"peers": [{
+				"uuid": "a2dc7784-e7a1-4723-b103-03ee8d8768f8",
+				"remote": {"client_name": "client.mirror_remote", "cluster_name": "ceph", "fs_name": "backup_fs"},
+				"stats": {"failure_count": 1, "recovery_count": 0}
+			}]
+		}]}]`)
+		status, err := fsa.MirrorDaemonStatus()
+		assert.NoError(t, err)
+		require.Len(t, status, 1)
+		assert.EqualValues(t, 4115, status[0].DaemonID)
+		require.Len(t, status[0].Filesystems, 1)
+		fs := status[0].Filesystems[0]
+		assert.EqualValues(t, 2, fs.DirectoryCount)
+		require.Len(t, fs.Peers, 1)
+		assert.Equal(t, "backup_fs", fs.Peers[0].Remote.FSName)
+		assert.EqualValues(t, 1, fs.Peers[0].Stats.FailureCount)
+	})
+}
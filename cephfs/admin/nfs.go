@@ -0,0 +1,212 @@
+package admin
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// The NFS functions require the nfs mgr module and an orchestrator able
+// to deploy the NFS-Ganesha daemons. They use the command syntax of Ceph
+// Pacific 16.2.5 or newer.
+
+// CreateNFSCluster creates a new NFS cluster, a group of NFS-Ganesha
+// daemons sharing a configuration, with the given id.
+//
+// Similar To:
+//  ceph nfs cluster create <cluster_id> [<placement>]
+func (fsa *FSAdmin) CreateNFSCluster(clusterID, placement string) error {
+	args := map[string]interface{}{
+		"prefix":     "nfs cluster create",
+		"cluster_id": clusterID,
+	}
+	if placement != "" {
+		args["placement"] = placement
+	}
+	_, err := fsa.mgrCommand(args)
+	return err
+}
+
+// RemoveNFSCluster removes the NFS cluster along with all of its exports.
+//
+// Similar To:
+//  ceph nfs cluster rm <cluster_id>
+func (fsa *FSAdmin) RemoveNFSCluster(clusterID string) error {
+	_, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":     "nfs cluster rm",
+		"cluster_id": clusterID,
+	})
+	return err
+}
+
+// ListNFSClusters returns the ids of the NFS clusters.
+//
+// Similar To:
+//  ceph nfs cluster ls
+func (fsa *FSAdmin) ListNFSClusters() ([]string, error) {
+	buf, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix": "nfs cluster ls",
+	})
+	if err != nil {
+		return nil, err
+	}
+	// some releases ignore the requested format and return one id per line
+	if strings.HasPrefix(strings.TrimSpace(string(buf)), "[") {
+		var ids []string
+		if err := json.Unmarshal(buf, &ids); err != nil {
+			return nil, err
+		}
+		return ids, nil
+	}
+	return strings.Fields(string(buf)), nil
+}
+
+// SquashMode indicates the kind of user id squashing performed on an
+// export.
+type SquashMode string
+
+const (
+	// NoRootSquash performs no id squashing.
+	NoRootSquash = SquashMode("no_root_squash")
+	// RootSquash squashes the root user.
+	RootSquash = SquashMode("root_squash")
+	// AllSquash squashes all users.
+	AllSquash = SquashMode("all_squash")
+)
+
+// CephFSExportSpec describes an NFS export of a path in a CephFS file
+// system.
+type CephFSExportSpec struct {
+	// FileSystemName is the name of the CephFS file system to export.
+	FileSystemName string
+	// ClusterID is the id of the NFS cluster serving the export.
+	ClusterID string
+	// PseudoPath is the path at which NFS clients mount the export.
+	PseudoPath string
+	// Path is the path within the file system that is exported. By
+	// default the root of the file system is exported.
+	Path string
+	// ReadOnly makes the export read only.
+	ReadOnly bool
+	// ClientAddr restricts the export to the given client addresses.
+	ClientAddr []string
+	// Squash sets the squash mode of the export.
+	Squash SquashMode
+}
+
+// ExportResult is returned when an export is created.
+type ExportResult struct {
+	Bind    string `json:"bind"`
+	FSName  string `json:"fs"`
+	Path    string `json:"path"`
+	Cluster string `json:"cluster"`
+	Mode    string `json:"mode"`
+}
+
+// CreateCephFSExport creates an NFS export of a path in a CephFS file
+// system.
+//
+// Similar To:
+//  ceph nfs export create cephfs <cluster_id> <pseudo_path> <fsname> [<path>] ...
+func (fsa *FSAdmin) CreateCephFSExport(spec CephFSExportSpec) (*ExportResult, error) {
+	args := map[string]interface{}{
+		"prefix":      "nfs export create cephfs",
+		"fsname":      spec.FileSystemName,
+		"cluster_id":  spec.ClusterID,
+		"pseudo_path": spec.PseudoPath,
+	}
+	if spec.Path != "" {
+		args["path"] = spec.Path
+	}
+	if spec.ReadOnly {
+		args["readonly"] = true
+	}
+	if len(spec.ClientAddr) > 0 {
+		args["client_addr"] = spec.ClientAddr
+	}
+	if spec.Squash != "" {
+		args["squash"] = string(spec.Squash)
+	}
+	var res ExportResult
+	if err := fsa.mgrCommandJSON(args, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RemoveExport removes the export, identified by its pseudo path, from the
+// NFS cluster.
+//
+// Similar To:
+//  ceph nfs export rm <cluster_id> <pseudo_path>
+func (fsa *FSAdmin) RemoveExport(clusterID, pseudoPath string) error {
+	_, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":      "nfs export rm",
+		"cluster_id":  clusterID,
+		"pseudo_path": pseudoPath,
+	})
+	return err
+}
+
+// FSALInfo describes the backing store of an export.
+type FSALInfo struct {
+	Name   string `json:"name"`
+	UserID string `json:"user_id"`
+	FSName string `json:"fs_name"`
+}
+
+// ClientInfo describes the access granted to a group of NFS clients.
+type ClientInfo struct {
+	Addresses  []string `json:"addresses"`
+	AccessType string   `json:"access_type"`
+	Squash     string   `json:"squash"`
+}
+
+// ExportInfo describes an NFS export.
+type ExportInfo struct {
+	ExportID      int64        `json:"export_id"`
+	Path          string       `json:"path"`
+	ClusterID     string       `json:"cluster_id"`
+	PseudoPath    string       `json:"pseudo"`
+	AccessType    string       `json:"access_type"`
+	Squash        string       `json:"squash"`
+	SecurityLabel bool         `json:"security_label"`
+	Protocols     []int        `json:"protocols"`
+	Transports    []string     `json:"transports"`
+	FSAL          FSALInfo     `json:"fsal"`
+	Clients       []ClientInfo `json:"clients"`
+}
+
+// ListExports returns the exports of the NFS cluster.
+//
+// Similar To:
+//  ceph nfs export ls <cluster_id> --detailed
+func (fsa *FSAdmin) ListExports(clusterID string) ([]ExportInfo, error) {
+	var exports []ExportInfo
+	err := fsa.mgrCommandJSON(map[string]interface{}{
+		"prefix":     "nfs export ls",
+		"cluster_id": clusterID,
+		"detailed":   true,
+	}, &exports)
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// ExportInfo returns information about the export, identified by its
+// pseudo path.
+//
+// Similar To:
+//  ceph nfs export info <cluster_id> <pseudo_path>
+func (fsa *FSAdmin) ExportInfo(clusterID, pseudoPath string) (*ExportInfo, error) {
+	var info ExportInfo
+	err := fsa.mgrCommandJSON(map[string]interface{}{
+		"prefix":      "nfs export info",
+		"cluster_id":  clusterID,
+		"pseudo_path": pseudoPath,
+	}, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListNFSClusters(t *testing.T) {
+	fake := &fakeCommander{response: []byte(`["foo", "bar"]`)}
+	fsa := NewFromConn(fake)
+
+	ids, err := fsa.ListNFSClusters()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, ids)
+
+	fake.response = []byte("foo\nbar\n")
+	ids, err = fsa.ListNFSClusters()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, ids)
+
+	fake.response = []byte("")
+	ids, err = fsa.ListNFSClusters()
+	assert.NoError(t, err)
+	assert.Len(t, ids, 0)
+}
+
+func TestCephFSExportCommands(t *testing.T) {
+	fake := &fakeCommander{response: []byte(`{
+		"bind": "/cephfs",
+		"fs": "cephfs",
+		"path": "/volumes",
+		"cluster": "foo",
+		"mode": "RO"
+	}`)}
+	fsa := NewFromConn(fake)
+
+	res, err := fsa.CreateCephFSExport(CephFSExportSpec{
+		FileSystemName: "cephfs",
+		ClusterID:      "foo",
+		PseudoPath:     "/cephfs",
+		Path:           "/volumes",
+		ReadOnly:       true,
+		ClientAddr:     []string{"10.0.0.0/24"},
+		Squash:         RootSquash,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &ExportResult{
+		Bind:    "/cephfs",
+		FSName:  "cephfs",
+		Path:    "/volumes",
+		Cluster: "foo",
+		Mode:    "RO",
+	}, res)
+	require.Len(t, fake.cmds, 1)
+	assert.Equal(t, map[string]interface{}{
+		"prefix":      "nfs export create cephfs",
+		"fsname":      "cephfs",
+		"cluster_id":  "foo",
+		"pseudo_path": "/cephfs",
+		"path":        "/volumes",
+		"readonly":    true,
+		"client_addr": []interface{}{"10.0.0.0/24"},
+		"squash":      "root_squash",
+		"format":      "json",
+	}, fake.cmds[0])
+
+	fake.response = []byte(`{
+		"export_id": 1,
+		"path": "/volumes",
+		"cluster_id": "foo",
+		"pseudo": "/cephfs",
+		"access_type": "RO",
+		"squash": "root_squash",
+		"security_label": true,
+		"protocols": [4],
+		"transports": ["TCP"],
+		"fsal": {"name": "CEPH", "user_id": "nfs.foo.1", "fs_name": "cephfs"},
+		"clients": [{"addresses": ["10.0.0.0/24"], "access_type": "RO", "squash": "root_squash"}]
+	}`)
+	info, err := fsa.ExportInfo("foo", "/cephfs")
+	assert.NoError(t, err)
+	require.NotNil(t, info)
+	assert.EqualValues(t, 1, info.ExportID)
+	assert.Equal(t, "/cephfs", info.PseudoPath)
+	assert.Equal(t, []int{4}, info.Protocols)
+	assert.Equal(t, "nfs.foo.1", info.FSAL.UserID)
+	require.Len(t, info.Clients, 1)
+	assert.Equal(t, []string{"10.0.0.0/24"}, info.Clients[0].Addresses)
+
+	fake.response = []byte(`[{"export_id": 1, "pseudo": "/cephfs"}, {"export_id": 2, "pseudo": "/other"}]`)
+	exports, err := fsa.ListExports("foo")
+	assert.NoError(t, err)
+	require.Len(t, exports, 2)
+	assert.Equal(t, "/other", exports[1].PseudoPath)
+}
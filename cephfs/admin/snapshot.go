@@ -0,0 +1,189 @@
+package admin
+
+// CreateSubVolumeSnapshot creates a snapshot of the subvolume.
+//
+// Similar To:
+//  ceph fs subvolume snapshot create <volume> --group-name=<group> <subvolume> <name>
+func (fsa *FSAdmin) CreateSubVolumeSnapshot(volume, group, subvolume, name string) error {
+	_, err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":    "fs subvolume snapshot create",
+		"vol_name":  volume,
+		"sub_name":  subvolume,
+		"snap_name": name,
+	}, group))
+	return err
+}
+
+// RemoveSubVolumeSnapshot removes a snapshot of the subvolume.
+//
+// Similar To:
+//  ceph fs subvolume snapshot rm <volume> --group-name=<group> <subvolume> <name>
+func (fsa *FSAdmin) RemoveSubVolumeSnapshot(volume, group, subvolume, name string) error {
+	_, err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":    "fs subvolume snapshot rm",
+		"vol_name":  volume,
+		"sub_name":  subvolume,
+		"snap_name": name,
+	}, group))
+	return err
+}
+
+// ListSubVolumeSnapshots returns the names of the snapshots of the
+// subvolume.
+//
+// Similar To:
+//  ceph fs subvolume snapshot ls <volume> --group-name=<group> <subvolume>
+func (fsa *FSAdmin) ListSubVolumeSnapshots(volume, group, subvolume string) ([]string, error) {
+	return fsa.listNamedJSON(withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume snapshot ls",
+		"vol_name": volume,
+		"sub_name": subvolume,
+	}, group))
+}
+
+// ProtectSubVolumeSnapshot protects the snapshot from being removed. Ceph
+// Nautilus releases without the snapshot-autoprotect feature require a
+// snapshot to be protected before it can be cloned. Later releases protect
+// the snapshots being cloned automatically and deprecate this call.
+//
+// Similar To:
+//  ceph fs subvolume snapshot protect <volume> --group-name=<group> <subvolume> <name>
+func (fsa *FSAdmin) ProtectSubVolumeSnapshot(volume, group, subvolume, name string) error {
+	_, err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":    "fs subvolume snapshot protect",
+		"vol_name":  volume,
+		"sub_name":  subvolume,
+		"snap_name": name,
+	}, group))
+	return err
+}
+
+// UnprotectSubVolumeSnapshot removes the protection set on the snapshot
+// by ProtectSubVolumeSnapshot, once no clone is using it.
+//
+// Similar To:
+//  ceph fs subvolume snapshot unprotect <volume> --group-name=<group> <subvolume> <name>
+func (fsa *FSAdmin) UnprotectSubVolumeSnapshot(volume, group, subvolume, name string) error {
+	_, err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":    "fs subvolume snapshot unprotect",
+		"vol_name":  volume,
+		"sub_name":  subvolume,
+		"snap_name": name,
+	}, group))
+	return err
+}
+
+// CloneOptions are used to specify optional values when cloning a
+// subvolume snapshot.
+type CloneOptions struct {
+	// TargetGroup is the group in which the clone is created. By default
+	// the clone is created in the default group.
+	TargetGroup string
+	// PoolLayout is the data pool of the clone. By default the pool of the
+	// source subvolume is used.
+	PoolLayout string
+}
+
+// CloneSubVolumeSnapshot creates a new subvolume, named clone, holding a
+// copy of the data of the snapshot. The clone is populated asynchronously;
+// use CloneStatus to check the progress of the operation.
+//
+// Ceph Nautilus releases without the snapshot-autoprotect feature require
+// the snapshot to be protected, with ProtectSubVolumeSnapshot, before it
+// can be cloned.
+//
+// Similar To:
+//  ceph fs subvolume snapshot clone <volume> --group-name=<group> <subvolume> <name> <clone> ...
+func (fsa *FSAdmin) CloneSubVolumeSnapshot(
+	volume, group, subvolume, name, clone string, o *CloneOptions) error {
+
+	args := withGroup(map[string]interface{}{
+		"prefix":          "fs subvolume snapshot clone",
+		"vol_name":        volume,
+		"sub_name":        subvolume,
+		"snap_name":       name,
+		"target_sub_name": clone,
+	}, group)
+	if o != nil {
+		if o.TargetGroup != NoGroup {
+			args["target_group_name"] = o.TargetGroup
+		}
+		if o.PoolLayout != "" {
+			args["pool_layout"] = o.PoolLayout
+		}
+	}
+	_, err := fsa.mgrCommand(args)
+	return err
+}
+
+// CloneState is the state of a clone operation.
+type CloneState string
+
+const (
+	// ClonePending indicates the clone has been queued.
+	ClonePending = CloneState("pending")
+	// CloneInProgress indicates the data is being copied to the clone.
+	CloneInProgress = CloneState("in-progress")
+	// CloneComplete indicates the clone is ready to be used.
+	CloneComplete = CloneState("complete")
+	// CloneFailed indicates the clone operation failed.
+	CloneFailed = CloneState("failed")
+	// CloneCanceled indicates the clone operation was canceled.
+	CloneCanceled = CloneState("canceled")
+)
+
+// CloneSource describes the snapshot a clone was made from.
+type CloneSource struct {
+	Volume    string `json:"volume"`
+	Group     string `json:"group"`
+	SubVolume string `json:"subvolume"`
+	Snapshot  string `json:"snapshot"`
+}
+
+// CloneFailure describes why a clone operation failed.
+type CloneFailure struct {
+	Errno    string `json:"errno"`
+	ErrorMsg string `json:"error_msg"`
+}
+
+// CloneStatus reports the progress of a clone operation.
+type CloneStatus struct {
+	State   CloneState    `json:"state"`
+	Source  CloneSource   `json:"source"`
+	Failure *CloneFailure `json:"failure"`
+}
+
+// CloneStatus returns the status of the clone operation that is
+// populating the named clone.
+//
+// Similar To:
+//  ceph fs clone status <volume> --group-name=<group> <clone>
+func (fsa *FSAdmin) CloneStatus(volume, group, clone string) (*CloneStatus, error) {
+	var out struct {
+		Status CloneStatus `json:"status"`
+	}
+	err := fsa.mgrCommandJSON(withGroup(map[string]interface{}{
+		"prefix":     "fs clone status",
+		"vol_name":   volume,
+		"clone_name": clone,
+	}, group), &out)
+	if err != nil {
+		return nil, err
+	}
+	return &out.Status, nil
+}
+
+// CancelClone stops a pending or in-progress clone operation. The clone
+// is left in the CloneCanceled state and must be removed with
+// RemoveSubVolumeWithFlags, setting the Force flag.
+//
+// Similar To:
+//  ceph fs clone cancel <volume> --group-name=<group> <clone>
+func (fsa *FSAdmin) CancelClone(volume, group, clone string) error {
+	_, err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":     "fs clone cancel",
+		"vol_name":   volume,
+		"clone_name": clone,
+	}, group))
+	return err
+}
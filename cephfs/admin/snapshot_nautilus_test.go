@@ -0,0 +1,36 @@
+// +build !luminous,!mimic
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubVolumeSnapshots(t *testing.T) {
+	fsa := getFSAdmin(t)
+	subvolume := "sv-snaps"
+
+	require.NoError(t, fsa.CreateSubVolume(testVolume, NoGroup, subvolume, nil))
+	defer func() {
+		assert.NoError(t, fsa.RemoveSubVolume(testVolume, NoGroup, subvolume))
+	}()
+
+	for _, snap := range []string{"snap1", "snap2"} {
+		err := fsa.CreateSubVolumeSnapshot(testVolume, NoGroup, subvolume, snap)
+		assert.NoError(t, err)
+	}
+	names, err := fsa.ListSubVolumeSnapshots(testVolume, NoGroup, subvolume)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"snap1", "snap2"}, names)
+
+	for _, snap := range []string{"snap1", "snap2"} {
+		err := fsa.RemoveSubVolumeSnapshot(testVolume, NoGroup, subvolume, snap)
+		assert.NoError(t, err)
+	}
+	names, err = fsa.ListSubVolumeSnapshots(testVolume, NoGroup, subvolume)
+	assert.NoError(t, err)
+	assert.Len(t, names, 0)
+}
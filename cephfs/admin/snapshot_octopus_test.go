@@ -0,0 +1,95 @@
+// +build !luminous,!mimic,!nautilus
+
+package admin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneSubVolumeSnapshot(t *testing.T) {
+	fsa := getFSAdmin(t)
+	subvolume := "sv-clone-src"
+	clone := "sv-clone"
+
+	require.NoError(t, fsa.CreateSubVolume(testVolume, NoGroup, subvolume, nil))
+	defer func() {
+		assert.NoError(t, fsa.RemoveSubVolume(testVolume, NoGroup, subvolume))
+	}()
+	require.NoError(t, fsa.CreateSubVolumeSnapshot(testVolume, NoGroup, subvolume, "snap1"))
+	defer func() {
+		assert.NoError(t, fsa.RemoveSubVolumeSnapshot(testVolume, NoGroup, subvolume, "snap1"))
+	}()
+
+	err := fsa.CloneSubVolumeSnapshot(testVolume, NoGroup, subvolume, "snap1", clone, nil)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, fsa.RemoveSubVolume(testVolume, NoGroup, clone))
+	}()
+
+	var status *CloneStatus
+	for i := 0; i < 30; i++ {
+		status, err = fsa.CloneStatus(testVolume, NoGroup, clone)
+		require.NoError(t, err)
+		if status.State == CloneComplete || status.State == CloneFailed {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	assert.Equal(t, CloneComplete, status.State)
+	assert.Equal(t, subvolume, status.Source.SubVolume)
+	assert.Equal(t, "snap1", status.Source.Snapshot)
+
+	// a completed clone can not be canceled
+	assert.Error(t, fsa.CancelClone(testVolume, NoGroup, clone))
+}
+
+// cephMountTest is where the CI mounts the file system of the test cluster.
+const cephMountTest = "/tmp/ceph/mds/mnt"
+
+func TestCancelClone(t *testing.T) {
+	fsa := getFSAdmin(t)
+	subvolume := "sv-cancel-src"
+	clone := "sv-cancel"
+
+	require.NoError(t, fsa.CreateSubVolume(testVolume, NoGroup, subvolume, nil))
+	defer func() {
+		assert.NoError(t, fsa.RemoveSubVolume(testVolume, NoGroup, subvolume))
+	}()
+	// give the clone enough data to copy that it can be canceled
+	svPath, err := fsa.SubVolumePath(testVolume, NoGroup, subvolume)
+	require.NoError(t, err)
+	data := make([]byte, 1024*1024)
+	for i := 0; i < 64; i++ {
+		fname := path.Join(cephMountTest, svPath, fmt.Sprintf("file%d", i))
+		require.NoError(t, ioutil.WriteFile(fname, data, 0644))
+	}
+	require.NoError(t, fsa.CreateSubVolumeSnapshot(testVolume, NoGroup, subvolume, "snap1"))
+	defer func() {
+		assert.NoError(t, fsa.RemoveSubVolumeSnapshot(testVolume, NoGroup, subvolume, "snap1"))
+	}()
+
+	err = fsa.CloneSubVolumeSnapshot(testVolume, NoGroup, subvolume, "snap1", clone, nil)
+	require.NoError(t, err)
+	if err := fsa.CancelClone(testVolume, NoGroup, clone); err != nil {
+		assert.NoError(t, fsa.RemoveSubVolume(testVolume, NoGroup, clone))
+		t.Skip("the clone completed before it could be canceled")
+	}
+	status, err := fsa.CloneStatus(testVolume, NoGroup, clone)
+	require.NoError(t, err)
+	assert.Equal(t, CloneCanceled, status.State)
+
+	// a canceled clone is only removed when forced
+	assert.Error(t, fsa.RemoveSubVolume(testVolume, NoGroup, clone))
+	err = fsa.RemoveSubVolumeWithFlags(testVolume, NoGroup, clone, SubVolRmFlags{Force: true})
+	assert.NoError(t, err)
+	names, err := fsa.ListSubVolumes(testVolume, NoGroup)
+	assert.NoError(t, err)
+	assert.NotContains(t, names, clone)
+}
@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneCommands(t *testing.T) {
+	fake := &fakeCommander{}
+	fsa := NewFromConn(fake)
+
+	assert.NoError(t, fsa.CloneSubVolumeSnapshot(
+		"cephfs", "grp", "sv1", "snap1", "clone1",
+		&CloneOptions{TargetGroup: "other"}))
+	require.Len(t, fake.cmds, 1)
+	assert.Equal(t, map[string]interface{}{
+		"prefix":            "fs subvolume snapshot clone",
+		"vol_name":          "cephfs",
+		"group_name":        "grp",
+		"sub_name":          "sv1",
+		"snap_name":         "snap1",
+		"target_sub_name":   "clone1",
+		"target_group_name": "other",
+		"format":            "json",
+	}, fake.cmds[0])
+
+	fake.response = []byte(`{"status": {
+		"state": "in-progress",
+		"source": {"volume": "cephfs", "subvolume": "sv1", "snapshot": "snap1"}
+	}}`)
+	status, err := fsa.CloneStatus("cephfs", "other", "clone1")
+	assert.NoError(t, err)
+	require.NotNil(t, status)
+	assert.Equal(t, CloneInProgress, status.State)
+	assert.Equal(t, "sv1", status.Source.SubVolume)
+	assert.Nil(t, status.Failure)
+
+	fake.response = []byte(`{"status": {
+		"state": "failed",
+		"source": {"volume": "cephfs", "subvolume": "sv1", "snapshot": "snap1"},
+		"failure": {"errno": "122", "error_msg": "Disk quota exceeded"}
+	}}`)
+	status, err = fsa.CloneStatus("cephfs", "other", "clone1")
+	assert.NoError(t, err)
+	require.NotNil(t, status)
+	assert.Equal(t, CloneFailed, status.State)
+	require.NotNil(t, status.Failure)
+	assert.Equal(t, "122", status.Failure.Errno)
+}
+
+func TestSnapshotProtectCommands(t *testing.T) {
+	fake := &fakeCommander{}
+	fsa := NewFromConn(fake)
+
+	assert.NoError(t, fsa.ProtectSubVolumeSnapshot("cephfs", NoGroup, "sv1", "snap1"))
+	assert.NoError(t, fsa.UnprotectSubVolumeSnapshot("cephfs", "grp", "sv1", "snap1"))
+	require.Len(t, fake.cmds, 2)
+	assert.Equal(t, map[string]interface{}{
+		"prefix":    "fs subvolume snapshot protect",
+		"vol_name":  "cephfs",
+		"sub_name":  "sv1",
+		"snap_name": "snap1",
+		"format":    "json",
+	}, fake.cmds[0])
+	assert.Equal(t, map[string]interface{}{
+		"prefix":     "fs subvolume snapshot unprotect",
+		"vol_name":   "cephfs",
+		"group_name": "grp",
+		"sub_name":   "sv1",
+		"snap_name":  "snap1",
+		"format":     "json",
+	}, fake.cmds[1])
+}
@@ -0,0 +1,133 @@
+package admin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MDSRankStatus describes an MDS daemon holding a rank of a file system.
+type MDSRankStatus struct {
+	Rank  int64
+	Name  string
+	State string
+	Addr  string
+}
+
+// FSPool describes a pool used by a file system.
+type FSPool struct {
+	ID   int64
+	Name string
+}
+
+// FSStatus reports the state of a CephFS file system.
+type FSStatus struct {
+	Name   string
+	ID     int64
+	MaxMDS int64
+	// Ranks lists the MDS daemons holding ranks, ordered by rank.
+	Ranks []MDSRankStatus
+	// StandbyCount is the number of standby MDS daemons of the cluster
+	// that may take over a rank of any file system.
+	StandbyCount int
+	// StandbyReplayCount is the number of standby-replay MDS daemons
+	// following the ranks of the file system.
+	StandbyReplayCount int
+	MetadataPool       FSPool
+	DataPools          []FSPool
+}
+
+type mdsInfoJSON struct {
+	Gid   int64  `json:"gid"`
+	Name  string `json:"name"`
+	Rank  int64  `json:"rank"`
+	State string `json:"state"`
+	Addr  string `json:"addr"`
+}
+
+type fsDumpJSON struct {
+	Standbys    []mdsInfoJSON `json:"standbys"`
+	Filesystems []struct {
+		ID     int64 `json:"id"`
+		MDSMap struct {
+			FSName       string                 `json:"fs_name"`
+			MaxMDS       int64                  `json:"max_mds"`
+			Info         map[string]mdsInfoJSON `json:"info"`
+			DataPools    []int64                `json:"data_pools"`
+			MetadataPool int64                  `json:"metadata_pool"`
+		} `json:"mdsmap"`
+	} `json:"filesystems"`
+}
+
+// poolNames returns a map of pool ids to pool names.
+func (fsa *FSAdmin) poolNames() (map[int64]string, error) {
+	var pools []struct {
+		Num  int64  `json:"poolnum"`
+		Name string `json:"poolname"`
+	}
+	if err := fsa.monCommandJSON(map[string]interface{}{
+		"prefix": "osd lspools",
+	}, &pools); err != nil {
+		return nil, err
+	}
+	names := make(map[int64]string, len(pools))
+	for _, p := range pools {
+		names[p.Num] = p.Name
+	}
+	return names, nil
+}
+
+// FSStatus returns the MDS ranks, standby daemons and pools of the named
+// file system. Clients that only have a mount of the file system can use
+// the FSStatus method of cephfs.MountInfo instead, which reports less.
+//
+// Similar To:
+//  ceph fs dump
+func (fsa *FSAdmin) FSStatus(fsName string) (*FSStatus, error) {
+	var dump fsDumpJSON
+	if err := fsa.monCommandJSON(map[string]interface{}{
+		"prefix": "fs dump",
+	}, &dump); err != nil {
+		return nil, err
+	}
+	pools, err := fsa.poolNames()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fs := range dump.Filesystems {
+		m := fs.MDSMap
+		if m.FSName != fsName {
+			continue
+		}
+		status := &FSStatus{
+			Name:         m.FSName,
+			ID:           fs.ID,
+			MaxMDS:       m.MaxMDS,
+			Ranks:        []MDSRankStatus{},
+			StandbyCount: len(dump.Standbys),
+			MetadataPool: FSPool{ID: m.MetadataPool, Name: pools[m.MetadataPool]},
+			DataPools:    make([]FSPool, len(m.DataPools)),
+		}
+		for i, id := range m.DataPools {
+			status.DataPools[i] = FSPool{ID: id, Name: pools[id]}
+		}
+		for _, info := range m.Info {
+			if strings.HasSuffix(info.State, "standby-replay") {
+				status.StandbyReplayCount++
+				continue
+			}
+			status.Ranks = append(status.Ranks, MDSRankStatus{
+				Rank:  info.Rank,
+				Name:  info.Name,
+				State: info.State,
+				Addr:  info.Addr,
+			})
+		}
+		sort.Slice(status.Ranks, func(i, j int) bool {
+			return status.Ranks[i].Rank < status.Ranks[j].Rank
+		})
+		return status, nil
+	}
+	return nil, fmt.Errorf("file system %q not found", fsName)
+}
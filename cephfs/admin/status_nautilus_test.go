@@ -0,0 +1,27 @@
+// +build !luminous,!mimic
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSStatus(t *testing.T) {
+	fsa := getFSAdmin(t)
+
+	status, err := fsa.FSStatus(testVolume)
+	require.NoError(t, err)
+	assert.Equal(t, testVolume, status.Name)
+	require.NotEmpty(t, status.Ranks)
+	assert.EqualValues(t, 0, status.Ranks[0].Rank)
+	assert.Equal(t, "up:active", status.Ranks[0].State)
+	assert.NotEmpty(t, status.MetadataPool.Name)
+	require.NotEmpty(t, status.DataPools)
+	assert.NotEmpty(t, status.DataPools[0].Name)
+
+	_, err = fsa.FSStatus("no-such-fs")
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replyCommander replies to each command with the next canned response.
+type replyCommander struct {
+	fakeCommander
+	responses [][]byte
+}
+
+func (r *replyCommander) MonCommand(args []byte) ([]byte, string, error) {
+	r.record(args)
+	buf := r.responses[0]
+	r.responses = r.responses[1:]
+	return buf, "", nil
+}
+
+func TestFSStatusParse(t *testing.T) {
+	fake := &replyCommander{responses: [][]byte{
+		[]byte(`{
+			"epoch": 12,
+			"standbys": [{"gid": 4200, "name": "c", "rank": -1, "state": "up:standby"}],
+			"filesystems": [{"id": 1, "mdsmap": {
+				"fs_name": "cephfs",
+				"max_mds": 2,
+				"info": {
+					"gid_4100": {"gid": 4100, "name": "b", "rank": 1, "state": "up:active", "addr": "10.0.0.2:6800/2"},
+					"gid_4000": {"gid": 4000, "name": "a", "rank": 0, "state": "up:active", "addr": "10.0.0.1:6800/1"},
+					"gid_4300": {"gid": 4300, "name": "d", "rank": 0, "state": "up:standby-replay"}
+				},
+				"data_pools": [3],
+				"metadata_pool": 2
+			}}]
+		}`),
+		[]byte(`[{"poolnum": 2, "poolname": "cephfs_metadata"}, {"poolnum": 3, "poolname": "cephfs_data"}]`),
+		[]byte(`{"standbys": [], "filesystems": []}`),
+		[]byte(`[]`),
+	}}
+	fsa := NewFromConn(fake)
+
+	status, err := fsa.FSStatus("cephfs")
+	require.NoError(t, err)
+	assert.Equal(t, &FSStatus{
+		Name:   "cephfs",
+		ID:     1,
+		MaxMDS: 2,
+		Ranks: []MDSRankStatus{
+			{Rank: 0, Name: "a", State: "up:active", Addr: "10.0.0.1:6800/1"},
+			{Rank: 1, Name: "b", State: "up:active", Addr: "10.0.0.2:6800/2"},
+		},
+		StandbyCount:       1,
+		StandbyReplayCount: 1,
+		MetadataPool:       FSPool{ID: 2, Name: "cephfs_metadata"},
+		DataPools:          []FSPool{{ID: 3, Name: "cephfs_data"}},
+	}, status)
+
+	_, err = fsa.FSStatus("cephfs")
+	assert.Error(t, err)
+}
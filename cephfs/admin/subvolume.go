@@ -0,0 +1,332 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NoGroup should be used as the group name for subvolumes that are not in
+// a subvolume group, i.e. that belong to the default group.
+const NoGroup = ""
+
+// ByteCount represents a size in bytes.
+type ByteCount uint64
+
+// QuotaSize is the size limit applied to a subvolume. It is either a
+// ByteCount or Infinite.
+type QuotaSize interface {
+	quotaValue() string
+}
+
+type specialSize string
+
+// Infinite is a QuotaSize that removes the size limit of a subvolume.
+const Infinite = specialSize("infinite")
+
+func (b ByteCount) quotaValue() string {
+	return strconv.FormatUint(uint64(b), 10)
+}
+
+func (s specialSize) quotaValue() string {
+	return string(s)
+}
+
+// parseQuotaSize converts the JSON value used by the mgr for a size
+// limit to a QuotaSize.
+func parseQuotaSize(v interface{}) (QuotaSize, error) {
+	switch x := v.(type) {
+	case float64:
+		return ByteCount(x), nil
+	case string:
+		if x == string(Infinite) {
+			return Infinite, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected quota size value: %v", v)
+}
+
+// TimeStamp is a time value as reported by the mgr volumes module.
+type TimeStamp struct {
+	time.Time
+}
+
+const timeStampLayout = "2006-01-02 15:04:05"
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (ts *TimeStamp) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	t, err := time.Parse(timeStampLayout, s)
+	if err != nil {
+		return err
+	}
+	ts.Time = t
+	return nil
+}
+
+// withGroup adds the group name to the command arguments, unless the
+// group is NoGroup.
+func withGroup(args map[string]interface{}, group string) map[string]interface{} {
+	if group != NoGroup {
+		args["group_name"] = group
+	}
+	return args
+}
+
+// SubVolumeOptions are used to specify optional values when creating a
+// subvolume. Zero values are not sent to the mgr, leaving the defaults in
+// place.
+type SubVolumeOptions struct {
+	Size              ByteCount
+	Uid               int
+	Gid               int
+	Mode              int
+	PoolLayout        string
+	NamespaceIsolated bool
+}
+
+func (o *SubVolumeOptions) toFields(args map[string]interface{}) {
+	if o == nil {
+		return
+	}
+	if o.Size != 0 {
+		args["size"] = o.Size
+	}
+	if o.Uid != 0 {
+		args["uid"] = o.Uid
+	}
+	if o.Gid != 0 {
+		args["gid"] = o.Gid
+	}
+	if o.Mode != 0 {
+		args["mode"] = fmt.Sprintf("%o", o.Mode)
+	}
+	if o.PoolLayout != "" {
+		args["pool_layout"] = o.PoolLayout
+	}
+	if o.NamespaceIsolated {
+		args["namespace_isolated"] = true
+	}
+}
+
+// CreateSubVolume creates a new subvolume in the given volume and group.
+// Creating a subvolume that already exists is not an error.
+//
+// Similar To:
+//  ceph fs subvolume create <volume> --group-name=<group> <name> ...
+func (fsa *FSAdmin) CreateSubVolume(volume, group, name string, o *SubVolumeOptions) error {
+	args := withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume create",
+		"vol_name": volume,
+		"sub_name": name,
+	}, group)
+	o.toFields(args)
+	_, err := fsa.mgrCommand(args)
+	return err
+}
+
+// ListSubVolumes returns the names of the subvolumes in the given volume
+// and group.
+//
+// Similar To:
+//  ceph fs subvolume ls <volume> --group-name=<group>
+func (fsa *FSAdmin) ListSubVolumes(volume, group string) ([]string, error) {
+	return fsa.listNamedJSON(withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume ls",
+		"vol_name": volume,
+	}, group))
+}
+
+// RemoveSubVolume removes the subvolume, and all of its data, from the
+// given volume and group.
+//
+// Similar To:
+//  ceph fs subvolume rm <volume> --group-name=<group> <name>
+func (fsa *FSAdmin) RemoveSubVolume(volume, group, name string) error {
+	return fsa.RemoveSubVolumeWithFlags(volume, group, name, SubVolRmFlags{})
+}
+
+// SubVolRmFlags may be used to specify optional values when removing a
+// subvolume.
+type SubVolRmFlags struct {
+	// Force removes the subvolume even if it is not in a state that
+	// normally allows it, such as a clone that failed or was canceled.
+	Force bool
+	// RetainSnapshots removes the data of the subvolume but keeps its
+	// snapshots. It requires Ceph Octopus or later.
+	RetainSnapshots bool
+}
+
+// RemoveSubVolumeWithFlags removes the subvolume, like RemoveSubVolume,
+// with the behavior adjusted by the given flags.
+//
+// Similar To:
+//  ceph fs subvolume rm <volume> --group-name=<group> <name> [--force] [--retain-snapshots]
+func (fsa *FSAdmin) RemoveSubVolumeWithFlags(volume, group, name string, o SubVolRmFlags) error {
+	args := withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume rm",
+		"vol_name": volume,
+		"sub_name": name,
+	}, group)
+	if o.Force {
+		args["force"] = true
+	}
+	if o.RetainSnapshots {
+		args["retain_snapshots"] = true
+	}
+	_, err := fsa.mgrCommand(args)
+	return err
+}
+
+// SubVolumeResizeResult reports the usage of a subvolume after it has been
+// resized.
+type SubVolumeResizeResult struct {
+	BytesUsed    ByteCount
+	BytesQuota   QuotaSize
+	BytesPercent string
+}
+
+// ResizeSubVolume changes the size limit of the subvolume. Pass Infinite
+// to remove the limit. If noShrink is true the call fails rather than
+// setting a limit below the current usage of the subvolume.
+//
+// Similar To:
+//  ceph fs subvolume resize <volume> --group-name=<group> <name> <size> [--no_shrink]
+func (fsa *FSAdmin) ResizeSubVolume(
+	volume, group, name string,
+	size QuotaSize, noShrink bool) (*SubVolumeResizeResult, error) {
+
+	args := withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume resize",
+		"vol_name": volume,
+		"sub_name": name,
+		"new_size": size.quotaValue(),
+	}, group)
+	if noShrink {
+		args["no_shrink"] = true
+	}
+	// the result is a list of single entry objects
+	var out []map[string]interface{}
+	if err := fsa.mgrCommandJSON(args, &out); err != nil {
+		return nil, err
+	}
+	result := &SubVolumeResizeResult{}
+	for _, entry := range out {
+		for k, v := range entry {
+			switch k {
+			case "bytes_used":
+				if n, ok := v.(float64); ok {
+					result.BytesUsed = ByteCount(n)
+				}
+			case "bytes_quota":
+				q, err := parseQuotaSize(v)
+				if err != nil {
+					return nil, err
+				}
+				result.BytesQuota = q
+			case "bytes_pcent":
+				result.BytesPercent = fmt.Sprint(v)
+			}
+		}
+	}
+	return result, nil
+}
+
+// SubVolumePath returns the path of the subvolume within the CephFS file
+// system. The path can be used to mount the subvolume.
+//
+// Similar To:
+//  ceph fs subvolume getpath <volume> --group-name=<group> <name>
+func (fsa *FSAdmin) SubVolumePath(volume, group, name string) (string, error) {
+	buf, err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume getpath",
+		"vol_name": volume,
+		"sub_name": name,
+	}, group))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// SubVolumeInfo reports various informational values about a subvolume.
+type SubVolumeInfo struct {
+	Type          string
+	Path          string
+	Uid           int
+	Gid           int
+	Mode          int
+	BytesPercent  string
+	BytesUsed     ByteCount
+	BytesQuota    QuotaSize
+	DataPool      string
+	PoolNamespace string
+	Atime         TimeStamp
+	Mtime         TimeStamp
+	Ctime         TimeStamp
+	CreatedAt     TimeStamp
+	Features      []string
+	MonAddrs      []string
+}
+
+type subVolumeInfoJSON struct {
+	Type          string      `json:"type"`
+	Path          string      `json:"path"`
+	Uid           int         `json:"uid"`
+	Gid           int         `json:"gid"`
+	Mode          int         `json:"mode"`
+	BytesPercent  string      `json:"bytes_pcent"`
+	BytesUsed     ByteCount   `json:"bytes_used"`
+	BytesQuota    interface{} `json:"bytes_quota"`
+	DataPool      string      `json:"data_pool"`
+	PoolNamespace string      `json:"pool_namespace"`
+	Atime         TimeStamp   `json:"atime"`
+	Mtime         TimeStamp   `json:"mtime"`
+	Ctime         TimeStamp   `json:"ctime"`
+	CreatedAt     TimeStamp   `json:"created_at"`
+	Features      []string    `json:"features"`
+	MonAddrs      []string    `json:"mon_addrs"`
+}
+
+// SubVolumeInfo returns information about the subvolume.
+//
+// Similar To:
+//  ceph fs subvolume info <volume> --group-name=<group> <name>
+func (fsa *FSAdmin) SubVolumeInfo(volume, group, name string) (*SubVolumeInfo, error) {
+	var info subVolumeInfoJSON
+	err := fsa.mgrCommandJSON(withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume info",
+		"vol_name": volume,
+		"sub_name": name,
+	}, group), &info)
+	if err != nil {
+		return nil, err
+	}
+	quota, err := parseQuotaSize(info.BytesQuota)
+	if err != nil {
+		return nil, err
+	}
+	return &SubVolumeInfo{
+		Type:          info.Type,
+		Path:          info.Path,
+		Uid:           info.Uid,
+		Gid:           info.Gid,
+		Mode:          info.Mode,
+		BytesPercent:  info.BytesPercent,
+		BytesUsed:     info.BytesUsed,
+		BytesQuota:    quota,
+		DataPool:      info.DataPool,
+		PoolNamespace: info.PoolNamespace,
+		Atime:         info.Atime,
+		Mtime:         info.Mtime,
+		Ctime:         info.Ctime,
+		CreatedAt:     info.CreatedAt,
+		Features:      info.Features,
+		MonAddrs:      info.MonAddrs,
+	}, nil
+}
@@ -0,0 +1,54 @@
+// +build !luminous,!mimic
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testVolume = "cephfs"
+
+func TestSubVolumeLifecycle(t *testing.T) {
+	fsa := getFSAdmin(t)
+	name := "sv-lifecycle"
+
+	err := fsa.CreateSubVolume(testVolume, NoGroup, name, &SubVolumeOptions{
+		Size: 20 * 1024 * 1024,
+		Mode: 0750,
+	})
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, fsa.RemoveSubVolume(testVolume, NoGroup, name))
+		names, err := fsa.ListSubVolumes(testVolume, NoGroup)
+		assert.NoError(t, err)
+		assert.NotContains(t, names, name)
+	}()
+
+	names, err := fsa.ListSubVolumes(testVolume, NoGroup)
+	assert.NoError(t, err)
+	assert.Contains(t, names, name)
+
+	p, err := fsa.SubVolumePath(testVolume, NoGroup, name)
+	assert.NoError(t, err)
+	assert.Contains(t, p, "/volumes/_nogroup/"+name)
+
+	info, err := fsa.SubVolumeInfo(testVolume, NoGroup, name)
+	require.NoError(t, err)
+	assert.Equal(t, p, info.Path)
+	assert.Equal(t, ByteCount(20*1024*1024), info.BytesQuota)
+	assert.Equal(t, 0750, info.Mode&0777)
+
+	r, err := fsa.ResizeSubVolume(testVolume, NoGroup, name, ByteCount(40*1024*1024), true)
+	assert.NoError(t, err)
+	if assert.NotNil(t, r) {
+		assert.Equal(t, ByteCount(40*1024*1024), r.BytesQuota)
+	}
+	r, err = fsa.ResizeSubVolume(testVolume, NoGroup, name, Infinite, false)
+	assert.NoError(t, err)
+	if assert.NotNil(t, r) {
+		assert.Equal(t, Infinite, r.BytesQuota)
+	}
+}
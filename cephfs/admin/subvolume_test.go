@@ -0,0 +1,129 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubVolumeOptions(t *testing.T) {
+	fake := &fakeCommander{}
+	fsa := NewFromConn(fake)
+
+	assert.NoError(t, fsa.CreateSubVolume("cephfs", NoGroup, "sv1", nil))
+	assert.NoError(t, fsa.CreateSubVolume("cephfs", "grp", "sv2", &SubVolumeOptions{
+		Size:              1024,
+		Uid:               1010,
+		Mode:              0750,
+		NamespaceIsolated: true,
+	}))
+	require.Len(t, fake.cmds, 2)
+	assert.Equal(t, map[string]interface{}{
+		"prefix":   "fs subvolume create",
+		"vol_name": "cephfs",
+		"sub_name": "sv1",
+		"format":   "json",
+	}, fake.cmds[0])
+	assert.Equal(t, map[string]interface{}{
+		"prefix":             "fs subvolume create",
+		"vol_name":           "cephfs",
+		"group_name":         "grp",
+		"sub_name":           "sv2",
+		"size":               float64(1024),
+		"uid":                float64(1010),
+		"mode":               "750",
+		"namespace_isolated": true,
+		"format":             "json",
+	}, fake.cmds[1])
+}
+
+func TestRemoveSubVolumeWithFlags(t *testing.T) {
+	fake := &fakeCommander{}
+	fsa := NewFromConn(fake)
+
+	assert.NoError(t, fsa.RemoveSubVolume("cephfs", NoGroup, "sv1"))
+	assert.NoError(t, fsa.RemoveSubVolumeWithFlags("cephfs", "grp", "sv2",
+		SubVolRmFlags{Force: true, RetainSnapshots: true}))
+	require.Len(t, fake.cmds, 2)
+	assert.Equal(t, map[string]interface{}{
+		"prefix":   "fs subvolume rm",
+		"vol_name": "cephfs",
+		"sub_name": "sv1",
+		"format":   "json",
+	}, fake.cmds[0])
+	assert.Equal(t, map[string]interface{}{
+		"prefix":           "fs subvolume rm",
+		"vol_name":         "cephfs",
+		"group_name":       "grp",
+		"sub_name":         "sv2",
+		"force":            true,
+		"retain_snapshots": true,
+		"format":           "json",
+	}, fake.cmds[1])
+}
+
+func TestParseSubVolumeResults(t *testing.T) {
+	fake := &fakeCommander{}
+	fsa := NewFromConn(fake)
+
+	t.Run("resize", func(t *testing.T) {
+		fake.response = []byte(`[{"bytes_used": 10}, {"bytes_quota": 2048}, {"bytes_pcent": "0.49"}]`)
+		r, err := fsa.ResizeSubVolume("cephfs", NoGroup, "sv1", ByteCount(2048), true)
+		assert.NoError(t, err)
+		assert.Equal(t, &SubVolumeResizeResult{
+			BytesUsed:    10,
+			BytesQuota:   ByteCount(2048),
+			BytesPercent: "0.49",
+		}, r)
+		assert.Equal(t, true, fake.cmds[len(fake.cmds)-1]["no_shrink"])
+
+		fake.response = []byte(`[{"bytes_used": 10}, {"bytes_quota": "infinite"}, {"bytes_pcent": "undefined"}]`)
+		r, err = fsa.ResizeSubVolume("cephfs", NoGroup, "sv1", Infinite, false)
+		assert.NoError(t, err)
+		assert.Equal(t, Infinite, r.BytesQuota)
+		assert.Equal(t, "infinite", fake.cmds[len(fake.cmds)-1]["new_size"])
+	})
+
+	t.Run("info", func(t *testing.T) {
+		fake.response = []byte(`{
+			"atime": "2020-08-31 19:53:43",
+			"bytes_pcent": "undefined",
+			"bytes_quota": "infinite",
+			"bytes_used": 0,
+			"created_at": "2020-08-31 19:53:43",
+			"ctime": "2020-08-31 19:57:15",
+			"data_pool": "cephfs_data",
+			"features": ["snapshot-clone", "snapshot-autoprotect"],
+			"gid": 0,
+			"mode": 16877,
+			"mon_addrs": ["127.0.0.1:6789"],
+			"mtime": "2020-08-31 19:53:43",
+			"path": "/volumes/_nogroup/sv1/5f4b6d5e",
+			"pool_namespace": "",
+			"type": "subvolume",
+			"uid": 0
+		}`)
+		info, err := fsa.SubVolumeInfo("cephfs", NoGroup, "sv1")
+		assert.NoError(t, err)
+		require.NotNil(t, info)
+		assert.Equal(t, "subvolume", info.Type)
+		assert.Equal(t, Infinite, info.BytesQuota)
+		assert.Equal(t, 16877, info.Mode)
+		assert.Equal(t, "cephfs_data", info.DataPool)
+		assert.Equal(t, 2020, info.CreatedAt.Year())
+		assert.Equal(t, 57, info.Ctime.Minute())
+		assert.Len(t, info.Features, 2)
+
+		fake.response = []byte(`{"atime": "bad"}`)
+		_, err = fsa.SubVolumeInfo("cephfs", NoGroup, "sv1")
+		assert.Error(t, err)
+	})
+
+	t.Run("getpath", func(t *testing.T) {
+		fake.response = []byte("/volumes/_nogroup/sv1/5f4b6d5e\n")
+		p, err := fsa.SubVolumePath("cephfs", NoGroup, "sv1")
+		assert.NoError(t, err)
+		assert.Equal(t, "/volumes/_nogroup/sv1/5f4b6d5e", p)
+	})
+}
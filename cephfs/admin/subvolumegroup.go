@@ -0,0 +1,93 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SubVolumeGroupOptions are used to specify optional values when creating
+// a subvolume group. Zero values are not sent to the mgr, leaving the
+// defaults in place.
+type SubVolumeGroupOptions struct {
+	Uid        int
+	Gid        int
+	Mode       int
+	PoolLayout string
+}
+
+func (o *SubVolumeGroupOptions) toFields(args map[string]interface{}) {
+	if o == nil {
+		return
+	}
+	if o.Uid != 0 {
+		args["uid"] = o.Uid
+	}
+	if o.Gid != 0 {
+		args["gid"] = o.Gid
+	}
+	if o.Mode != 0 {
+		args["mode"] = fmt.Sprintf("%o", o.Mode)
+	}
+	if o.PoolLayout != "" {
+		args["pool_layout"] = o.PoolLayout
+	}
+}
+
+// CreateSubVolumeGroup creates a new subvolume group in the given volume.
+// Creating a group that already exists is not an error.
+//
+// Similar To:
+//  ceph fs subvolumegroup create <volume> <group> ...
+func (fsa *FSAdmin) CreateSubVolumeGroup(volume, name string, o *SubVolumeGroupOptions) error {
+	args := map[string]interface{}{
+		"prefix":     "fs subvolumegroup create",
+		"vol_name":   volume,
+		"group_name": name,
+	}
+	o.toFields(args)
+	_, err := fsa.mgrCommand(args)
+	return err
+}
+
+// ListSubVolumeGroups returns the names of the subvolume groups in the
+// given volume.
+//
+// Similar To:
+//  ceph fs subvolumegroup ls <volume>
+func (fsa *FSAdmin) ListSubVolumeGroups(volume string) ([]string, error) {
+	return fsa.listNamedJSON(map[string]interface{}{
+		"prefix":   "fs subvolumegroup ls",
+		"vol_name": volume,
+	})
+}
+
+// RemoveSubVolumeGroup removes the subvolume group from the given volume.
+// The group must not contain any subvolumes.
+//
+// Similar To:
+//  ceph fs subvolumegroup rm <volume> <group>
+func (fsa *FSAdmin) RemoveSubVolumeGroup(volume, name string) error {
+	_, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":     "fs subvolumegroup rm",
+		"vol_name":   volume,
+		"group_name": name,
+	})
+	return err
+}
+
+// SubVolumeGroupPath returns the path of the subvolume group within the
+// CephFS file system.
+//
+// Similar To:
+//  ceph fs subvolumegroup getpath <volume> <group>
+func (fsa *FSAdmin) SubVolumeGroupPath(volume, name string) (string, error) {
+	buf, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":     "fs subvolumegroup getpath",
+		"vol_name":   volume,
+		"group_name": name,
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf)), nil
+}
@@ -0,0 +1,45 @@
+// +build !luminous,!mimic
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubVolumeGroupLifecycle(t *testing.T) {
+	fsa := getFSAdmin(t)
+	group := "grp-lifecycle"
+
+	err := fsa.CreateSubVolumeGroup(testVolume, group, nil)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, fsa.RemoveSubVolumeGroup(testVolume, group))
+		names, err := fsa.ListSubVolumeGroups(testVolume)
+		assert.NoError(t, err)
+		assert.NotContains(t, names, group)
+	}()
+
+	names, err := fsa.ListSubVolumeGroups(testVolume)
+	assert.NoError(t, err)
+	assert.Contains(t, names, group)
+
+	p, err := fsa.SubVolumeGroupPath(testVolume, group)
+	assert.NoError(t, err)
+	assert.Equal(t, "/volumes/"+group, p)
+
+	// subvolumes can be placed in the group
+	require.NoError(t, fsa.CreateSubVolume(testVolume, group, "sv1", nil))
+	svNames, err := fsa.ListSubVolumes(testVolume, group)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sv1"}, svNames)
+	svPath, err := fsa.SubVolumePath(testVolume, group, "sv1")
+	assert.NoError(t, err)
+	assert.Contains(t, svPath, p+"/sv1")
+
+	// a group containing subvolumes can not be removed
+	assert.Error(t, fsa.RemoveSubVolumeGroup(testVolume, group))
+	assert.NoError(t, fsa.RemoveSubVolume(testVolume, group, "sv1"))
+}
@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubVolumeGroupCommands(t *testing.T) {
+	fake := &fakeCommander{}
+	fsa := NewFromConn(fake)
+
+	assert.NoError(t, fsa.CreateSubVolumeGroup("cephfs", "grp", &SubVolumeGroupOptions{
+		Gid:        1010,
+		Mode:       0770,
+		PoolLayout: "cephfs_data",
+	}))
+	assert.NoError(t, fsa.RemoveSubVolumeGroup("cephfs", "grp"))
+	require.Len(t, fake.cmds, 2)
+	assert.Equal(t, map[string]interface{}{
+		"prefix":      "fs subvolumegroup create",
+		"vol_name":    "cephfs",
+		"group_name":  "grp",
+		"gid":         float64(1010),
+		"mode":        "770",
+		"pool_layout": "cephfs_data",
+		"format":      "json",
+	}, fake.cmds[0])
+	assert.Equal(t, map[string]interface{}{
+		"prefix":     "fs subvolumegroup rm",
+		"vol_name":   "cephfs",
+		"group_name": "grp",
+		"format":     "json",
+	}, fake.cmds[1])
+
+	fake.response = []byte(`[{"name": "grp"}, {"name": "other"}]`)
+	names, err := fsa.ListSubVolumeGroups("cephfs")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"grp", "other"}, names)
+}
@@ -0,0 +1,39 @@
+package admin
+
+// ListVolumes returns the names of the CephFS volumes of the cluster.
+//
+// Similar To:
+//  ceph fs volume ls
+func (fsa *FSAdmin) ListVolumes() ([]string, error) {
+	return fsa.listNamedJSON(map[string]interface{}{
+		"prefix": "fs volume ls",
+	})
+}
+
+// CreateVolume creates a new CephFS volume, along with the pools and MDS
+// daemons it needs.
+//
+// Similar To:
+//  ceph fs volume create <name>
+func (fsa *FSAdmin) CreateVolume(name string) error {
+	_, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix": "fs volume create",
+		"name":   name,
+	})
+	return err
+}
+
+// RemoveVolume removes a CephFS volume, deleting all of its data and its
+// pools. The cluster must allow pool deletion (mon_allow_pool_delete) for
+// the call to succeed.
+//
+// Similar To:
+//  ceph fs volume rm <name> --yes-i-really-mean-it
+func (fsa *FSAdmin) RemoveVolume(name string) error {
+	_, err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":               "fs volume rm",
+		"vol_name":             name,
+		"yes-i-really-mean-it": "--yes-i-really-mean-it",
+	})
+	return err
+}
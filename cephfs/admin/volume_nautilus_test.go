@@ -0,0 +1,17 @@
+// +build !luminous,!mimic
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListVolumes(t *testing.T) {
+	fsa := getFSAdmin(t)
+
+	names, err := fsa.ListVolumes()
+	assert.NoError(t, err)
+	assert.Contains(t, names, "cephfs")
+}
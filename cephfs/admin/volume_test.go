@@ -0,0 +1,30 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeCommands(t *testing.T) {
+	fake := &fakeCommander{response: []byte(`[{"name": "a"}, {"name": "b"}]`)}
+	fsa := NewFromConn(fake)
+
+	names, err := fsa.ListVolumes()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	fake.response = nil
+	assert.NoError(t, fsa.CreateVolume("c"))
+	assert.NoError(t, fsa.RemoveVolume("c"))
+	assert.Equal(t, []map[string]interface{}{
+		{"prefix": "fs volume ls", "format": "json"},
+		{"prefix": "fs volume create", "name": "c", "format": "json"},
+		{
+			"prefix":               "fs volume rm",
+			"vol_name":             "c",
+			"yes-i-really-mean-it": "--yes-i-really-mean-it",
+			"format":               "json",
+		},
+	}, fake.cmds)
+}
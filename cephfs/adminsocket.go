@@ -0,0 +1,78 @@
+package cephfs
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"time"
+)
+
+const adminSocketTimeout = 30 * time.Second
+
+var errNoAdminSocket = errors.New(
+	"cephfs: admin socket not configured, set the admin_socket option before mounting")
+
+// adminSocketCommand sends a command to the Ceph admin socket at the given
+// path and returns the response. The request is the command followed by a
+// NUL byte and the response is a 32 bit big-endian length followed by that
+// many bytes of data.
+func adminSocketCommand(path string, cmd []byte) ([]byte, error) {
+	conn, err := net.DialTimeout("unix", path, adminSocketTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(adminSocketTimeout)); err != nil {
+		return nil, err
+	}
+
+	req := make([]byte, len(cmd)+1)
+	copy(req, cmd)
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	var size uint32
+	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// AdminSocketPath returns the path of the admin socket of the client, as
+// set by the admin_socket configuration option. An empty string is
+// returned if the client has no admin socket.
+func (mount *MountInfo) AdminSocketPath() (string, error) {
+	return mount.GetConfigOption("admin_socket")
+}
+
+// AdminSocketCommand sends a command, such as {"prefix": "status"}, to the
+// admin socket of the client and returns the response. The admin socket is
+// only available if the admin_socket configuration option was set before
+// the file system was mounted.
+func (mount *MountInfo) AdminSocketCommand(cmd []byte) ([]byte, error) {
+	path, err := mount.AdminSocketPath()
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return nil, errNoAdminSocket
+	}
+	return adminSocketCommand(path, cmd)
+}
+
+// adminSocketPrefixCommand sends a command with no arguments other than
+// the prefix to the admin socket of the client.
+func (mount *MountInfo) adminSocketPrefixCommand(prefix string) ([]byte, error) {
+	cmd, err := json.Marshal(map[string]string{"prefix": prefix})
+	if err != nil {
+		return nil, err
+	}
+	return mount.AdminSocketCommand(cmd)
+}
@@ -0,0 +1,104 @@
+package cephfs
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fsConnectWithAdminSocket returns a mounted MountInfo with the admin
+// socket of the client enabled.
+func fsConnectWithAdminSocket(t *testing.T) *MountInfo {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	require.NotNil(t, mount)
+
+	err = mount.ReadDefaultConfigFile()
+	require.NoError(t, err)
+	asok := fmt.Sprintf("/tmp/go-ceph-client.%d.%d.asok", os.Getpid(), time.Now().UnixNano())
+	err = mount.SetConfigOption("admin_socket", asok)
+	require.NoError(t, err)
+
+	timeout := time.After(time.Second * 5)
+	ch := make(chan error)
+	go func(mount *MountInfo) {
+		ch <- mount.Mount()
+	}(mount)
+	select {
+	case err = <-ch:
+	case <-timeout:
+		err = fmt.Errorf("timed out waiting for connect")
+	}
+	require.NoError(t, err)
+	return mount
+}
+
+func TestAdminSocketProtocol(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-ceph-asok")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.asok")
+
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer l.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 1024)
+		n, _ := conn.Read(buf)
+		received <- buf[:n]
+		resp := []byte(`{"ok": true}`)
+		binary.Write(conn, binary.BigEndian, uint32(len(resp)))
+		conn.Write(resp)
+	}()
+
+	resp, err := adminSocketCommand(path, []byte(`{"prefix": "status"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ok": true}`, string(resp))
+	assert.Equal(t, []byte("{\"prefix\": \"status\"}\x00"), <-received)
+
+	_, err = adminSocketCommand(filepath.Join(dir, "missing.asok"), []byte("status"))
+	assert.Error(t, err)
+}
+
+func TestAdminSocketCommand(t *testing.T) {
+	t.Run("configured", func(t *testing.T) {
+		mount := fsConnectWithAdminSocket(t)
+		defer mount.Unmount()
+
+		path, err := mount.AdminSocketPath()
+		assert.NoError(t, err)
+		assert.NotEqual(t, "", path)
+
+		buf, err := mount.AdminSocketCommand([]byte(`{"prefix": "status"}`))
+		assert.NoError(t, err)
+		var status map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buf, &status))
+		assert.Contains(t, status, "inode_count")
+	})
+
+	t.Run("notConfigured", func(t *testing.T) {
+		mount, err := CreateMount()
+		require.NoError(t, err)
+		defer mount.Release()
+		require.NoError(t, mount.SetConfigOption("admin_socket", ""))
+
+		_, err = mount.AdminSocketCommand([]byte(`{"prefix": "status"}`))
+		assert.Equal(t, errNoAdminSocket, err)
+	})
+}
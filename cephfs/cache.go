@@ -0,0 +1,38 @@
+package cephfs
+
+import (
+	"encoding/json"
+)
+
+// CacheStatus reports the amount of metadata held in the client's cache.
+type CacheStatus struct {
+	// DentryCount is the number of directory entries in the cache.
+	DentryCount uint64 `json:"dentry_count"`
+	// DentryPinnedCount is the number of cached directory entries that
+	// can not be trimmed because they are in use.
+	DentryPinnedCount uint64 `json:"dentry_pinned_count"`
+	// InodeCount is the number of inodes in the cache.
+	InodeCount uint64 `json:"inode_count"`
+}
+
+// GetCacheStatus returns the size of the client's metadata cache, using
+// the "status" command of the client's admin socket.
+func (mount *MountInfo) GetCacheStatus() (*CacheStatus, error) {
+	buf, err := mount.adminSocketPrefixCommand("status")
+	if err != nil {
+		return nil, err
+	}
+	status := &CacheStatus{}
+	if err := json.Unmarshal(buf, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// DumpCache returns a JSON description of every inode and dentry in the
+// client's metadata cache, using the "dump_cache" command of the client's
+// admin socket. The format of the data is defined by Ceph and may change
+// between releases.
+func (mount *MountInfo) DumpCache() ([]byte, error) {
+	return mount.adminSocketPrefixCommand("dump_cache")
+}
@@ -0,0 +1,21 @@
+// +build !luminous,!mimic
+//
+// Ceph Nautilus is the first release that includes the MDS "cache drop"
+// command.
+
+package cephfs
+
+// DropMDSCache asks the MDS identified by mdsSpec to trim its cache and to
+// recall capabilities from its clients, causing the clients to drop the
+// cached metadata they do not need. The MDS waits up to timeout seconds for
+// clients to release their capabilities; zero means no timeout. The
+// returned data is the JSON report of the MDS.
+func (mount *MountInfo) DropMDSCache(mdsSpec string, timeout int) ([]byte, error) {
+	args := map[string]interface{}{
+		"prefix": "cache drop",
+	}
+	if timeout > 0 {
+		args["timeout"] = timeout
+	}
+	return mount.mdsJSONCommand(mdsSpec, args)
+}
@@ -0,0 +1,21 @@
+// +build !luminous,!mimic
+
+package cephfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDropMDSCache(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	buf, err := mount.DropMDSCache(testMdsName, 10)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, buf)
+
+	_, err = mount.DropMDSCache("no-such-mds", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package cephfs
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheStatus(t *testing.T) {
+	mount := fsConnectWithAdminSocket(t)
+	defer mount.Unmount()
+
+	dname := "TestCacheStatus"
+	require.NoError(t, mount.MakeDir(dname, 0755))
+	defer mount.RemoveDir(dname)
+	for _, name := range []string{"a", "b", "c"} {
+		f, err := mount.Open(dname+"/"+name, os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		assert.NoError(t, f.Close())
+		defer mount.Unlink(dname + "/" + name)
+	}
+
+	status, err := mount.GetCacheStatus()
+	assert.NoError(t, err)
+	require.NotNil(t, status)
+	// root, the directory and the three files
+	assert.True(t, status.InodeCount >= 5)
+	assert.True(t, status.DentryCount >= 4)
+
+	buf, err := mount.DumpCache()
+	assert.NoError(t, err)
+	var dump interface{}
+	assert.NoError(t, json.Unmarshal(buf, &dump))
+}
@@ -3,6 +3,7 @@ package cephfs
 /*
 #cgo LDFLAGS: -lcephfs
 #cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
 #include <stdlib.h>
 #include <cephfs/libcephfs.h>
 */
@@ -10,6 +11,8 @@ import "C"
 
 import (
 	"fmt"
+	"sync/atomic"
+	"syscall"
 	"unsafe"
 
 	"github.com/ceph/go-ceph/errutil"
@@ -38,6 +41,15 @@ func getError(e C.int) error {
 // MountInfo exports ceph's ceph_mount_info from libcephfs.cc
 type MountInfo struct {
 	mount *C.struct_ceph_mount_info
+	// abandoned is set, atomically, when a context aware call gives up
+	// waiting on the mount
+	abandoned int32
+	// initialized is set, atomically, once Init created the client
+	initialized int32
+	// handles tracks the files and directories opened with the mount
+	handles handleRegistry
+	// dirWatches tracks the directories watched with WatchDir
+	dirWatches dirWatchRegistry
 }
 
 func createMount(id *C.char) (*MountInfo, error) {
@@ -82,6 +94,37 @@ func (mount *MountInfo) ReadDefaultConfigFile() error {
 	return getError(ret)
 }
 
+// SelectFilesystem selects a file system to be mounted. If the ceph cluster
+// supports multiple cephfs file systems this call informs the client which
+// fs to mount. This call must be made before calling Mount, otherwise the
+// cluster's default file system is mounted.
+//
+// Implements:
+//  int ceph_select_filesystem(struct ceph_mount_info *cmount, const char *fs_name);
+func (mount *MountInfo) SelectFilesystem(name string) error {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	ret := C.ceph_select_filesystem(mount.mount, cName)
+	return getError(ret)
+}
+
+// Init the mount handle, creating the client and connecting to the cluster
+// without mounting the file system. Mount calls this implicitly when
+// needed. Calling it explicitly is only necessary to change settings, such
+// as the credentials set by SetMountPerms, that require an initialized but
+// not yet mounted client.
+//
+// Implements:
+//  int ceph_init(struct ceph_mount_info *cmount);
+func (mount *MountInfo) Init() error {
+	ret := C.ceph_init(mount.mount)
+	if ret == 0 {
+		atomic.StoreInt32(&mount.initialized, 1)
+	}
+	return getError(ret)
+}
+
 // Mount the file system, establishing a connection capable of I/O.
 //
 // Implements:
@@ -91,20 +134,54 @@ func (mount *MountInfo) Mount() error {
 	return getError(ret)
 }
 
+// MountWithRoot mounts the file system using the path provided for the root
+// of the mount. This establishes a connection capable of I/O that is
+// confined to the subtree below root; paths used with the mount are resolved
+// relative to that directory.
+//
+// Implements:
+//  int ceph_mount(struct ceph_mount_info *cmount, const char *root);
+func (mount *MountInfo) MountWithRoot(root string) error {
+	cRoot := C.CString(root)
+	defer C.free(unsafe.Pointer(cRoot))
+
+	ret := C.ceph_mount(mount.mount, cRoot)
+	return getError(ret)
+}
+
 // Unmount the file system.
 //
 // Implements:
 //  int ceph_unmount(struct ceph_mount_info *cmount);
 func (mount *MountInfo) Unmount() error {
+	if mount.Abandoned() {
+		return ErrMountAbandoned
+	}
 	ret := C.ceph_unmount(mount.mount)
 	return getError(ret)
 }
 
+// AbortConn forcibly tears down the connection to the file system without
+// flushing any dirty data or metadata and without cleanly closing the MDS
+// session. It is intended for clients that must stop issuing I/O right
+// away, for example when they are being fenced. The mount handle must still
+// be released with Release afterwards.
+//
+// Implements:
+//  int ceph_abort_conn(struct ceph_mount_info *cmount);
+func (mount *MountInfo) AbortConn() error {
+	ret := C.ceph_abort_conn(mount.mount)
+	return getError(ret)
+}
+
 // Release destroys the mount handle.
 //
 // Implements:
 //  int ceph_release(struct ceph_mount_info *cmount);
 func (mount *MountInfo) Release() error {
+	if mount.Abandoned() {
+		return ErrMountAbandoned
+	}
 	ret := C.ceph_release(mount.mount)
 	return getError(ret)
 }
@@ -139,6 +216,29 @@ func (mount *MountInfo) MakeDir(path string, mode uint32) error {
 	return getError(ret)
 }
 
+// MakeDirs creates a directory along with any missing parent directories.
+// Like os.MkdirAll, it is not an error if the directory already exists.
+//
+// Implements:
+//  int ceph_mkdirs(struct ceph_mount_info *cmount, const char *path, mode_t mode);
+func (mount *MountInfo) MakeDirs(path string, mode uint32) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	ret := C.ceph_mkdirs(mount.mount, cPath, C.mode_t(mode))
+	if ret == -C.EEXIST {
+		// only acceptable if the existing file is a directory
+		st, err := mount.Statx(path, StatxMode, 0)
+		if err != nil {
+			return err
+		}
+		if st.Mode&syscall.S_IFMT == syscall.S_IFDIR {
+			return nil
+		}
+	}
+	return getError(ret)
+}
+
 // RemoveDir removes a directory.
 func (mount *MountInfo) RemoveDir(path string) error {
 	cPath := C.CString(path)
@@ -166,6 +266,45 @@ func (mount *MountInfo) Chown(path string, user uint32, group uint32) error {
 	return getError(ret)
 }
 
+// Lchown changes the ownership of a file/directory. If the path refers to a
+// symbolic link the ownership of the link itself is changed, rather than
+// that of the file it points to.
+//
+// Implements:
+//  int ceph_lchown(struct ceph_mount_info *cmount, const char *path, int uid, int gid);
+func (mount *MountInfo) Lchown(path string, user uint32, group uint32) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	ret := C.ceph_lchown(mount.mount, cPath, C.int(user), C.int(group))
+	return getError(ret)
+}
+
+// Lchmod changes the mode bits (permissions) of a file/directory. If the
+// path refers to a symbolic link the mode of the link itself is changed,
+// rather than that of the file it points to.
+//
+// The ceph_lchmod call is not available in all supported versions of
+// libcephfs so this is implemented using ceph_setattrx.
+//
+// Implements:
+//  int ceph_setattrx(struct ceph_mount_info *cmount, const char *relpath,
+//                    struct ceph_statx *stx, int mask, int flags);
+func (mount *MountInfo) Lchmod(path string, mode uint32) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	var stx C.struct_ceph_statx
+	stx.stx_mode = C.uint16_t(mode)
+	ret := C.ceph_setattrx(
+		mount.mount,
+		cPath,
+		&stx,
+		C.CEPH_SETATTR_MODE,
+		C.int(AtSymlinkNofollow))
+	return getError(ret)
+}
+
 // IsMounted checks mount status.
 func (mount *MountInfo) IsMounted() bool {
 	ret := C.ceph_is_mounted(mount.mount)
@@ -51,6 +51,92 @@ func TestMountRoot(t *testing.T) {
 	fsConnect(t)
 }
 
+func TestMountWithRoot(t *testing.T) {
+	bMount := fsConnect(t)
+	defer bMount.Unmount()
+
+	dir1 := "/test-mount-with-root"
+	err := bMount.MakeDir(dir1, 0755)
+	require.NoError(t, err)
+	defer bMount.RemoveDir(dir1)
+
+	sub1 := "/i.was.here"
+	dir2 := dir1 + sub1
+	err = bMount.MakeDir(dir2, 0755)
+	require.NoError(t, err)
+	defer bMount.RemoveDir(dir2)
+
+	t.Run("withRoot", func(t *testing.T) {
+		mount, err := CreateMount()
+		require.NoError(t, err)
+		require.NotNil(t, mount)
+		defer mount.Release()
+
+		err = mount.ReadDefaultConfigFile()
+		require.NoError(t, err)
+
+		err = mount.MountWithRoot(dir1)
+		require.NoError(t, err)
+		defer mount.Unmount()
+
+		// the subdirectory is visible relative to the new root
+		err = mount.ChangeDir(sub1)
+		assert.NoError(t, err)
+		assert.Equal(t, sub1, mount.CurrentDir())
+	})
+
+	t.Run("badRoot", func(t *testing.T) {
+		mount, err := CreateMount()
+		require.NoError(t, err)
+		require.NotNil(t, mount)
+		defer mount.Release()
+
+		err = mount.ReadDefaultConfigFile()
+		require.NoError(t, err)
+
+		err = mount.MountWithRoot("/i-yam-what-i-yam")
+		assert.Error(t, err)
+	})
+}
+
+func TestSelectFilesystem(t *testing.T) {
+	t.Run("existing", func(t *testing.T) {
+		mount, err := CreateMount()
+		require.NoError(t, err)
+		require.NotNil(t, mount)
+		defer mount.Release()
+
+		err = mount.ReadDefaultConfigFile()
+		require.NoError(t, err)
+
+		// the file system created by the ci container script
+		err = mount.SelectFilesystem("cephfs")
+		assert.NoError(t, err)
+
+		err = mount.Mount()
+		assert.NoError(t, err)
+		assert.True(t, mount.IsMounted())
+		assert.NoError(t, mount.Unmount())
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		mount, err := CreateMount()
+		require.NoError(t, err)
+		require.NotNil(t, mount)
+		defer mount.Release()
+
+		err = mount.ReadDefaultConfigFile()
+		require.NoError(t, err)
+
+		err = mount.SelectFilesystem("cephfs-no-such-fs")
+		assert.NoError(t, err)
+
+		err = mount.Mount()
+		assert.Error(t, err)
+		assert.False(t, mount.IsMounted())
+	})
+}
+
 func TestSyncFs(t *testing.T) {
 	mount := fsConnect(t)
 
@@ -100,6 +186,41 @@ func TestRemoveDir(t *testing.T) {
 		fmt.Sprintf("stat %s: no such file or directory", CephMountTest+dirname))
 }
 
+func TestMakeDirs(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dirs := "TestMakeDirs/a/b/c"
+	err := mount.MakeDirs(dirs, 0755)
+	assert.NoError(t, err)
+	defer func() {
+		for _, d := range []string{dirs, "TestMakeDirs/a/b", "TestMakeDirs/a", "TestMakeDirs"} {
+			assert.NoError(t, mount.RemoveDir(d))
+		}
+	}()
+
+	st, err := mount.Statx(dirs, StatxMode, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(syscall.S_IFDIR), st.Mode&syscall.S_IFMT)
+
+	// existing directories are not an error
+	err = mount.MakeDirs(dirs, 0755)
+	assert.NoError(t, err)
+	err = mount.MakeDirs("TestMakeDirs/a", 0755)
+	assert.NoError(t, err)
+
+	// but a file in the way is
+	fname := "TestMakeDirs/a/file"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+	err = mount.MakeDirs(fname, 0755)
+	assert.Error(t, err)
+	err = mount.MakeDirs(fname+"/sub", 0755)
+	assert.Error(t, err)
+}
+
 func TestUnmountMount(t *testing.T) {
 	t.Run("neverMounted", func(t *testing.T) {
 		mount, err := CreateMount()
@@ -117,6 +238,27 @@ func TestUnmountMount(t *testing.T) {
 	})
 }
 
+func TestAbortConn(t *testing.T) {
+	t.Run("mounted", func(t *testing.T) {
+		mount := fsConnect(t)
+		assert.True(t, mount.IsMounted())
+
+		err := mount.AbortConn()
+		assert.NoError(t, err)
+		assert.False(t, mount.IsMounted())
+		assert.NoError(t, mount.Release())
+	})
+	t.Run("neverMounted", func(t *testing.T) {
+		mount, err := CreateMount()
+		require.NoError(t, err)
+		require.NotNil(t, mount)
+		defer mount.Release()
+
+		err = mount.AbortConn()
+		assert.Error(t, err)
+	})
+}
+
 func TestReleaseMount(t *testing.T) {
 	mount, err := CreateMount()
 	assert.NoError(t, err)
@@ -183,6 +325,71 @@ func TestChown(t *testing.T) {
 
 }
 
+func TestLchown(t *testing.T) {
+	fname := "lchown.target"
+	lname := "lchown.link"
+	// dockerfile creates bob user account
+	var bob uint32 = 1010
+
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+	require.NoError(t, mount.Symlink(fname, lname))
+	defer mount.Unlink(lname)
+
+	err = mount.Lchown(lname, bob, bob)
+	assert.NoError(t, err)
+
+	st, err := mount.Statx(lname, StatxUid|StatxGid, AtSymlinkNofollow)
+	require.NoError(t, err)
+	assert.Equal(t, bob, st.Uid)
+	assert.Equal(t, bob, st.Gid)
+
+	// the target of the link is not changed
+	st, err = mount.Statx(fname, StatxUid|StatxGid, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), st.Uid)
+	assert.Equal(t, uint32(0), st.Gid)
+
+	err = mount.Lchown("lchown.missing", bob, bob)
+	assert.Error(t, err)
+}
+
+func TestLchmod(t *testing.T) {
+	fname := "lchmod.target"
+	lname := "lchmod.link"
+
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+	require.NoError(t, mount.Symlink(fname, lname))
+	defer mount.Unlink(lname)
+
+	// on a regular file Lchmod behaves like Chmod
+	err = mount.Lchmod(fname, 0600)
+	assert.NoError(t, err)
+	st, err := mount.Statx(fname, StatxMode, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(0600), st.Mode&0777)
+
+	// the link is not followed so the target is not changed
+	mount.Lchmod(lname, 0640)
+	st, err = mount.Statx(fname, StatxMode, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(0600), st.Mode&0777)
+
+	err = mount.Lchmod("lchmod.missing", 0600)
+	assert.Error(t, err)
+}
+
 func TestCephFSError(t *testing.T) {
 	err := getError(0)
 	assert.NoError(t, err)
@@ -0,0 +1,79 @@
+package cephfs
+
+import (
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimespecAfter(t *testing.T) {
+	assert.True(t, Timespec{Sec: 2}.after(Timespec{Sec: 1, Nsec: 999}))
+	assert.True(t, Timespec{Sec: 1, Nsec: 2}.after(Timespec{Sec: 1, Nsec: 1}))
+	assert.False(t, Timespec{Sec: 1, Nsec: 1}.after(Timespec{Sec: 1, Nsec: 1}))
+	assert.False(t, Timespec{Sec: 1}.after(Timespec{Sec: 2}))
+}
+
+func TestChangedSince(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	root := "/TestChangedSince"
+	require.NoError(t, mount.MakeDirs(root+"/old/deep", 0755))
+	require.NoError(t, mount.MakeDir(root+"/new", 0755))
+	defer mount.RemoveAll(root)
+	create := func(fname string) {
+		f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		assert.NoError(t, f.Close())
+	}
+	create(root + "/old/deep/f1")
+	create(root + "/new/f2")
+	require.NoError(t, mount.SyncFs())
+
+	// rctime has a resolution that may be coarser than the clock
+	time.Sleep(2 * time.Second)
+	now := time.Now()
+	since := Timespec{Sec: now.Unix(), Nsec: int64(now.Nanosecond())}
+	time.Sleep(1 * time.Second)
+
+	create(root + "/new/f3")
+	require.NoError(t, mount.SyncFs())
+
+	var mu sync.Mutex
+	changed := []string{}
+	err := mount.ChangedSince(root, since, 4,
+		func(path string, entry *DirEntryPlus) error {
+			mu.Lock()
+			changed = append(changed, path)
+			mu.Unlock()
+			return nil
+		})
+	assert.NoError(t, err)
+	sort.Strings(changed)
+	// the directory containing the new file changed too
+	assert.Equal(t, []string{root + "/new", root + "/new/f3"}, changed)
+
+	t.Run("nothingChanged", func(t *testing.T) {
+		called := false
+		err := mount.ChangedSince(root, Timespec{Sec: time.Now().Unix() + 3600}, 4,
+			func(path string, entry *DirEntryPlus) error {
+				called = true
+				return nil
+			})
+		assert.NoError(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		err := mount.ChangedSince(root+".missing", since, 4,
+			func(path string, entry *DirEntryPlus) error {
+				return nil
+			})
+		assert.Error(t, err)
+	})
+}
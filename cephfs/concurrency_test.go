@@ -0,0 +1,123 @@
+package cephfs
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests are most useful when run with the race detector enabled.
+
+func TestConcurrentMountUse(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/concurrent"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer func() { assert.NoError(t, mount.RemoveAll(dir)) }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sub := fmt.Sprintf("%s/d%d", dir, i)
+			assert.NoError(t, mount.MakeDir(sub, 0755))
+			f, err := mount.Open(sub+"/file", os.O_RDWR|os.O_CREATE, 0644)
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, err = f.Write([]byte("data"))
+			assert.NoError(t, err)
+			assert.NoError(t, f.Close())
+			_, err = mount.Statx(sub+"/file", StatxBasicStats, 0)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	d, err := mount.OpenDir(dir)
+	require.NoError(t, err)
+	names, err := d.List()
+	assert.NoError(t, err)
+	assert.Len(t, names, 8)
+	assert.NoError(t, d.Close())
+}
+
+func TestConcurrentFileUse(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestConcurrentFileUse.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, mount.Unlink(fname)) }()
+
+	const blockSize = 512
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			block := make([]byte, blockSize)
+			for j := range block {
+				block[j] = byte(i)
+			}
+			_, err := f.WriteAt(block, int64(i*blockSize))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			block := make([]byte, blockSize)
+			n, err := f.ReadAt(block, int64(i*blockSize))
+			assert.NoError(t, err)
+			assert.Equal(t, blockSize, n)
+			assert.Equal(t, byte(i), block[blockSize-1])
+		}(i)
+	}
+	wg.Wait()
+
+	// closing while other goroutines use the file is safe, the calls
+	// either complete or fail with EBADF
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, blockSize)
+			_, err := f.ReadAt(buf, 0)
+			if err != nil {
+				assert.Equal(t, errBadFile, err)
+			}
+		}()
+	}
+	assert.NoError(t, f.Close())
+	wg.Wait()
+
+	_, err = f.ReadAt(make([]byte, 1), 0)
+	assert.Equal(t, errBadFile, err)
+	_, err = f.Seek(0, 0)
+	assert.Equal(t, errBadFile, err)
+}
+
+func TestClosedDirectory(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir, err := mount.OpenDir("/")
+	require.NoError(t, err)
+	assert.NoError(t, dir.Close())
+
+	_, err = dir.ReadDir()
+	assert.Equal(t, errBadFile, err)
+	_, err = dir.ReadDirPlus(StatxBasicStats, 0)
+	assert.Equal(t, errBadFile, err)
+}
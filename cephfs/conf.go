@@ -0,0 +1,172 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// SetConfigOption sets the value of the configuration option identified by
+// the given name.
+//
+// Implements:
+//  int ceph_conf_set(struct ceph_mount_info *cmount, const char *option, const char *value);
+func (mount *MountInfo) SetConfigOption(option, value string) error {
+	cOption := C.CString(option)
+	defer C.free(unsafe.Pointer(cOption))
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+
+	ret := C.ceph_conf_set(mount.mount, cOption, cValue)
+	return getError(ret)
+}
+
+// GetConfigOption returns the value of the Ceph configuration option
+// identified by the given name.
+//
+// Implements:
+//  int ceph_conf_get(struct ceph_mount_info *cmount, const char *option, char *buf, size_t len);
+func (mount *MountInfo) GetConfigOption(option string) (string, error) {
+	cOption := C.CString(option)
+	defer C.free(unsafe.Pointer(cOption))
+
+	buf := make([]byte, 4096)
+	for {
+		ret := C.ceph_conf_get(
+			mount.mount,
+			cOption,
+			(*C.char)(unsafe.Pointer(&buf[0])),
+			C.size_t(len(buf)))
+		if ret == -C.ENAMETOOLONG && len(buf) < 1024*1024 {
+			// the value did not fit, try again with a larger buffer
+			buf = make([]byte, len(buf)*2)
+			continue
+		}
+		if err := getError(ret); err != nil {
+			return "", err
+		}
+		return C.GoString((*C.char)(unsafe.Pointer(&buf[0]))), nil
+	}
+}
+
+// ParseCmdLineArgs configures the mount from command line arguments, such as
+// "--keyring" or "-m", as understood by the standard Ceph tools.
+//
+// Implements:
+//  int ceph_conf_parse_argv(struct ceph_mount_info *cmount, int argc, const char **argv);
+func (mount *MountInfo) ParseCmdLineArgs(args []string) error {
+	// add an empty element 0 -- Ceph treats the array as the actual contents
+	// of argv and skips the first element (the executable name)
+	argc := C.int(len(args) + 1)
+	argv := make([]*C.char, argc)
+
+	// make the first element a string just in case it is ever examined
+	argv[0] = C.CString("placeholder")
+	for i, arg := range args {
+		argv[i+1] = C.CString(arg)
+	}
+	// free all array elements in a single defer
+	defer func() {
+		for i := range argv {
+			C.free(unsafe.Pointer(argv[i]))
+		}
+	}()
+
+	ret := C.ceph_conf_parse_argv(mount.mount, argc, &argv[0])
+	return getError(ret)
+}
+
+// ParseDefaultConfigEnv configures the mount from the default Ceph
+// environment variable CEPH_ARGS.
+//
+// Implements:
+//  int ceph_conf_parse_env(struct ceph_mount_info *cmount, const char *var);
+func (mount *MountInfo) ParseDefaultConfigEnv() error {
+	ret := C.ceph_conf_parse_env(mount.mount, nil)
+	return getError(ret)
+}
+
+// SetMountTimeout sets the number of seconds Mount will wait for the
+// cluster to respond before giving up and returning an error. A value of
+// zero disables the timeout. It must be called before the mount is
+// mounted to have an effect.
+//
+// libcephfs does not provide a dedicated call for this, the timeout is
+// controlled by the client_mount_timeout configuration option.
+func (mount *MountInfo) SetMountTimeout(seconds uint32) error {
+	return mount.SetConfigOption(
+		"client_mount_timeout", strconv.FormatUint(uint64(seconds), 10))
+}
+
+// SetClientMetadata sets custom metadata, such as the name of the
+// application or of the container it runs in, that the client reports to
+// the MDS when it opens a session. The metadata is shown, along with the
+// metadata the client reports by itself, by the "session ls" command of
+// the MDS and can help identify the clients of a file system. It must be
+// called before the mount is mounted to have an effect. Keys must not be
+// empty and neither keys nor values may contain a comma, nor keys an
+// equals sign.
+//
+// libcephfs does not provide a dedicated call for this, the metadata is
+// controlled by the client_metadata configuration option.
+func (mount *MountInfo) SetClientMetadata(metadata map[string]string) error {
+	pairs := make([]string, 0, len(metadata))
+	for k, v := range metadata {
+		if k == "" || strings.ContainsAny(k, ",=") || strings.Contains(v, ",") {
+			return ErrInvalid
+		}
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return mount.SetConfigOption("client_metadata", strings.Join(pairs, ","))
+}
+
+// The following functions set commonly tuned client options. As with
+// SetConfigOption, an error is returned if the option is not known to the
+// version of libcephfs in use. Depending on the option and the Ceph
+// release, a change made after the mount is mounted may only apply to new
+// files or may need the mount to be remounted.
+
+// SetReadaheadMaxBytes sets the maximum number of bytes the client reads
+// ahead of sequential reads. Zero removes the limit, leaving readahead
+// bounded only by the number of file layout periods.
+func (mount *MountInfo) SetReadaheadMaxBytes(n uint64) error {
+	return mount.SetConfigOption(
+		"client_readahead_max_bytes", strconv.FormatUint(n, 10))
+}
+
+// SetCacheSize sets the maximum number of inodes the client keeps in its
+// metadata cache.
+func (mount *MountInfo) SetCacheSize(inodes uint64) error {
+	return mount.SetConfigOption(
+		"client_cache_size", strconv.FormatUint(inodes, 10))
+}
+
+// SetObjectCacheSize sets the maximum number of bytes of file data the
+// client caches.
+func (mount *MountInfo) SetObjectCacheSize(n uint64) error {
+	return mount.SetConfigOption(
+		"client_oc_size", strconv.FormatUint(n, 10))
+}
+
+// SetCapsReleaseDelay sets how long the client keeps capabilities it no
+// longer needs before returning them to the MDS. The delay has a
+// resolution of one second and must not be negative.
+func (mount *MountInfo) SetCapsReleaseDelay(d time.Duration) error {
+	if d < 0 {
+		return ErrInvalid
+	}
+	return mount.SetConfigOption(
+		"client_caps_release_delay", strconv.FormatInt(int64(d/time.Second), 10))
+}
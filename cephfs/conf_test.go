@@ -0,0 +1,150 @@
+package cephfs
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSetConfigOption(t *testing.T) {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	require.NotNil(t, mount)
+	defer mount.Release()
+
+	// rejects invalid options
+	err = mount.SetConfigOption("__dne__", "value")
+	assert.Error(t, err)
+	_, err = mount.GetConfigOption("__dne__")
+	assert.Error(t, err)
+
+	// verify SetConfigOption changes a value
+	origVal, err := mount.GetConfigOption("log_file")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "/dev/null", origVal)
+
+	err = mount.SetConfigOption("log_file", "/dev/null")
+	assert.NoError(t, err)
+	currVal, err := mount.GetConfigOption("log_file")
+	assert.NoError(t, err)
+	assert.Equal(t, "/dev/null", currVal)
+
+	// numeric client options
+	err = mount.SetConfigOption("client_mount_timeout", "37")
+	assert.NoError(t, err)
+	currVal, err = mount.GetConfigOption("client_mount_timeout")
+	assert.NoError(t, err)
+	assert.Contains(t, currVal, "37")
+
+	err = mount.SetConfigOption("client_mount_timeout", "not-a-number")
+	assert.Error(t, err)
+}
+
+func TestParseDefaultConfigEnv(t *testing.T) {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	require.NotNil(t, mount)
+	defer mount.Release()
+
+	origVal, err := mount.GetConfigOption("log_file")
+	assert.NoError(t, err)
+
+	err = os.Setenv("CEPH_ARGS", "--log-file /dev/null")
+	assert.NoError(t, err)
+	defer os.Unsetenv("CEPH_ARGS")
+
+	err = mount.ParseDefaultConfigEnv()
+	assert.NoError(t, err)
+
+	currVal, err := mount.GetConfigOption("log_file")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "/dev/null", origVal)
+	assert.Equal(t, "/dev/null", currVal)
+}
+
+func TestParseCmdLineArgs(t *testing.T) {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	require.NotNil(t, mount)
+	defer mount.Release()
+
+	origVal, err := mount.GetConfigOption("log_file")
+	assert.NoError(t, err)
+
+	args := []string{"--log_file", "/dev/null"}
+	err = mount.ParseCmdLineArgs(args)
+	assert.NoError(t, err)
+
+	currVal, err := mount.GetConfigOption("log_file")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "/dev/null", origVal)
+	assert.Equal(t, "/dev/null", currVal)
+
+	// an empty argument list is acceptable
+	assert.NoError(t, mount.ParseCmdLineArgs(nil))
+}
+
+func TestSetMountTimeout(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		mount, err := CreateMount()
+		require.NoError(t, err)
+		require.NotNil(t, mount)
+		defer mount.Release()
+
+		err = mount.SetMountTimeout(42)
+		assert.NoError(t, err)
+		val, err := mount.GetConfigOption("client_mount_timeout")
+		assert.NoError(t, err)
+		timeout, err := strconv.ParseFloat(val, 64)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(42), timeout)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		mount, err := CreateMount()
+		require.NoError(t, err)
+		require.NotNil(t, mount)
+		defer mount.Release()
+
+		// nothing listens on this address
+		err = mount.SetConfigOption("mon_host", "127.0.0.1:1")
+		require.NoError(t, err)
+		err = mount.SetMountTimeout(2)
+		require.NoError(t, err)
+
+		start := time.Now()
+		err = mount.Mount()
+		assert.Error(t, err)
+		assert.True(t, time.Since(start) < 60*time.Second)
+	})
+}
+
+func TestClientTuning(t *testing.T) {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	require.NotNil(t, mount)
+	defer mount.Release()
+
+	check := func(option, expected string) {
+		val, err := mount.GetConfigOption(option)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, val)
+	}
+
+	assert.NoError(t, mount.SetReadaheadMaxBytes(4*1024*1024))
+	check("client_readahead_max_bytes", "4194304")
+	assert.NoError(t, mount.SetCacheSize(4096))
+	check("client_cache_size", "4096")
+	assert.NoError(t, mount.SetObjectCacheSize(64*1024*1024))
+	check("client_oc_size", "67108864")
+	assert.NoError(t, mount.SetCapsReleaseDelay(10*time.Second))
+	val, err := mount.GetConfigOption("client_caps_release_delay")
+	assert.NoError(t, err)
+	assert.Contains(t, val, "10")
+
+	assert.Equal(t, ErrInvalid, mount.SetCapsReleaseDelay(-time.Second))
+}
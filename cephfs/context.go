@@ -0,0 +1,128 @@
+package cephfs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrMountAbandoned is returned by the context aware calls of a mount, and
+// by the calls closing its files or tearing it down, after an earlier
+// context aware call on the mount was abandoned.
+var ErrMountAbandoned = errors.New("cephfs mount abandoned by a canceled call")
+
+// Abandoned returns true if a context aware call on the mount stopped
+// waiting for libcephfs because its context was done. The abandoned call
+// may still be blocked inside libcephfs, holding locks of the mount or of
+// its files, so the mount should no longer be used. Close, Unmount and
+// Release fail with ErrMountAbandoned instead of waiting for the abandoned
+// call.
+func (mount *MountInfo) Abandoned() bool {
+	return atomic.LoadInt32(&mount.abandoned) != 0
+}
+
+// runContext calls fn in a new goroutine and waits for it to return or for
+// ctx to be done, whichever happens first. If fn returned nil is returned
+// and the results captured by fn may be used. If ctx is done first the
+// mount is flagged as abandoned and the context's error is returned; fn
+// keeps running in the background and its results must not be touched.
+func (mount *MountInfo) runContext(ctx context.Context, fn func()) error {
+	if mount.Abandoned() {
+		return ErrMountAbandoned
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		atomic.StoreInt32(&mount.abandoned, 1)
+		return ctx.Err()
+	}
+}
+
+// MountContext mounts the file system like Mount, but gives up waiting
+// when ctx is done. A mount that timed out is flagged as abandoned.
+func (mount *MountInfo) MountContext(ctx context.Context) error {
+	var err error
+	if cerr := mount.runContext(ctx, func() { err = mount.Mount() }); cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+// UnmountContext unmounts the file system like Unmount, but gives up
+// waiting when ctx is done. A mount that timed out is flagged as
+// abandoned.
+func (mount *MountInfo) UnmountContext(ctx context.Context) error {
+	var err error
+	if cerr := mount.runContext(ctx, func() { err = mount.Unmount() }); cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+// MountWithTimeout mounts the file system like Mount, but gives up waiting
+// after the duration d. A mount that timed out is flagged as abandoned.
+// See SetMountTimeout for a way to have libcephfs itself give up.
+func (mount *MountInfo) MountWithTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return mount.MountContext(ctx)
+}
+
+// UnmountWithTimeout unmounts the file system like Unmount, but gives up
+// waiting after the duration d. A mount that timed out is flagged as
+// abandoned.
+func (mount *MountInfo) UnmountWithTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return mount.UnmountContext(ctx)
+}
+
+// runIOContext is runContext for calls returning a byte count.
+func (f *File) runIOContext(ctx context.Context, fn func() (int, error)) (int, error) {
+	var (
+		n   int
+		err error
+	)
+	if cerr := f.mount.runContext(ctx, func() { n, err = fn() }); cerr != nil {
+		return 0, cerr
+	}
+	return n, err
+}
+
+// ReadContext reads from the file like Read, but gives up waiting when ctx
+// is done. If the call is abandoned libcephfs may still write into buf
+// later, so buf must not be reused.
+func (f *File) ReadContext(ctx context.Context, buf []byte) (int, error) {
+	return f.runIOContext(ctx, func() (int, error) { return f.Read(buf) })
+}
+
+// ReadAtContext reads from the file like ReadAt, but gives up waiting when
+// ctx is done. If the call is abandoned libcephfs may still write into buf
+// later, so buf must not be reused.
+func (f *File) ReadAtContext(ctx context.Context, buf []byte, offset int64) (int, error) {
+	return f.runIOContext(ctx, func() (int, error) { return f.ReadAt(buf, offset) })
+}
+
+// WriteContext writes to the file like Write, but gives up waiting when
+// ctx is done. If the call is abandoned libcephfs may still read from buf
+// later, so buf must not be modified.
+func (f *File) WriteContext(ctx context.Context, buf []byte) (int, error) {
+	return f.runIOContext(ctx, func() (int, error) { return f.Write(buf) })
+}
+
+// WriteAtContext writes to the file like WriteAt, but gives up waiting
+// when ctx is done. If the call is abandoned libcephfs may still read from
+// buf later, so buf must not be modified.
+func (f *File) WriteAtContext(ctx context.Context, buf []byte, offset int64) (int, error) {
+	return f.runIOContext(ctx, func() (int, error) { return f.WriteAt(buf, offset) })
+}
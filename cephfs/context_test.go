@@ -0,0 +1,153 @@
+package cephfs
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMountContext(t *testing.T) {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	require.NoError(t, mount.ReadDefaultConfigFile())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	require.NoError(t, mount.MountContext(ctx))
+	assert.True(t, mount.IsMounted())
+	assert.False(t, mount.Abandoned())
+
+	assert.NoError(t, mount.UnmountContext(ctx))
+	assert.NoError(t, mount.Release())
+}
+
+func TestMountContextCanceled(t *testing.T) {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	require.NoError(t, mount.ReadDefaultConfigFile())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// a context that is already done does not start the call
+	assert.Equal(t, context.Canceled, mount.MountContext(ctx))
+	assert.False(t, mount.Abandoned())
+	assert.False(t, mount.IsMounted())
+	assert.NoError(t, mount.Release())
+}
+
+func TestMountWithTimeout(t *testing.T) {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	require.NoError(t, mount.ReadDefaultConfigFile())
+
+	require.NoError(t, mount.MountWithTimeout(time.Minute))
+	assert.True(t, mount.IsMounted())
+	assert.NoError(t, mount.UnmountWithTimeout(time.Minute))
+	assert.False(t, mount.Abandoned())
+	assert.NoError(t, mount.Release())
+}
+
+func TestMountWithTimeoutExpired(t *testing.T) {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	// point the client at a monitor that does not exist so the mount
+	// can not complete
+	require.NoError(t, mount.SetConfigOption("mon_host", "192.0.2.1:6789"))
+
+	start := time.Now()
+	err = mount.MountWithTimeout(100 * time.Millisecond)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < 10*time.Second)
+	assert.True(t, mount.Abandoned())
+	assert.Equal(t, ErrMountAbandoned, mount.UnmountWithTimeout(time.Second))
+	// the abandoned mount is deliberately not released
+}
+
+func TestFileContextIO(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestFileContextIO.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, f.Close())
+		assert.NoError(t, mount.Unlink(fname))
+	}()
+
+	ctx := context.Background()
+	n, err := f.WriteContext(ctx, []byte("hello "))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, n)
+	n, err = f.WriteAtContext(ctx, []byte("world"), 6)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	buf := make([]byte, 16)
+	n, err = f.ReadAtContext(ctx, buf, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(buf[:n]))
+
+	_, err = f.Seek(0, io.SeekEnd)
+	assert.NoError(t, err)
+	_, err = f.ReadContext(ctx, buf)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestRunContextAbandon(t *testing.T) {
+	mount := &MountInfo{}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+	err := mount.runContext(ctx, func() { <-release })
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, mount.Abandoned())
+
+	// later calls fail right away
+	err = mount.runContext(context.Background(), func() {
+		t.Error("call on abandoned mount")
+	})
+	assert.Equal(t, ErrMountAbandoned, err)
+}
+
+func TestCloseAfterAbandonedRead(t *testing.T) {
+	mount := &MountInfo{}
+	f := &File{mount: mount, fd: 3}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// stands in for a read that is stuck in libcephfs
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	_, err := f.runIOContext(ctx, func() (int, error) {
+		if err := f.rlock(); err != nil {
+			return 0, err
+		}
+		defer f.mu.RUnlock()
+		close(locked)
+		<-release
+		return 0, nil
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, mount.Abandoned())
+	<-locked
+
+	closed := make(chan error, 1)
+	go func() { closed <- f.Close() }()
+	select {
+	case err = <-closed:
+		assert.Equal(t, ErrMountAbandoned, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Close blocked on the abandoned read")
+	}
+	assert.Equal(t, ErrMountAbandoned, mount.Unmount())
+	assert.Equal(t, ErrMountAbandoned, mount.Release())
+}
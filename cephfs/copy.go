@@ -0,0 +1,49 @@
+package cephfs
+
+import (
+	"io"
+)
+
+// copyBufferSize is the size of the chunks CopyFileRange copies data in.
+// It matches the default object size of CephFS files.
+const copyBufferSize = 4 * 1024 * 1024
+
+// CopyFileRange copies up to length bytes from the src file, starting at
+// srcOff, to the dst file, starting at dstOff. The file offsets of src and
+// dst are not changed. Copying stops early if the end of src is reached.
+// The number of bytes copied is returned.
+//
+// libcephfs does not provide a copy_file_range call that lets the copy be
+// done without the data passing through the client, so the data is read
+// and written in chunks.
+func CopyFileRange(src *File, srcOff int64, dst *File, dstOff int64, length int64) (int64, error) {
+	if srcOff < 0 || dstOff < 0 || length < 0 {
+		return 0, ErrInvalid
+	}
+	size := int64(copyBufferSize)
+	if length < size {
+		size = length
+	}
+	buf := make([]byte, size)
+
+	var copied int64
+	for copied < length {
+		chunk := buf
+		if remaining := length - copied; remaining < int64(len(chunk)) {
+			chunk = chunk[:remaining]
+		}
+		n, rerr := src.ReadAt(chunk, srcOff+copied)
+		if rerr != nil && rerr != io.EOF {
+			return copied, rerr
+		}
+		w, err := dst.WriteAt(chunk[:n], dstOff+copied)
+		copied += int64(w)
+		if err != nil {
+			return copied, err
+		}
+		if rerr == io.EOF {
+			break
+		}
+	}
+	return copied, nil
+}
@@ -0,0 +1,74 @@
+package cephfs
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyFileRange(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	// large enough to need more than one chunk
+	data := bytes.Repeat([]byte("0123456789abcdef"), (copyBufferSize/16)+100)
+
+	srcName := "TestCopyFileRange.src"
+	src, err := mount.Open(srcName, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	defer mount.Unlink(srcName)
+	defer src.Close()
+	_, err = src.Write(data)
+	require.NoError(t, err)
+
+	dstName := "TestCopyFileRange.dst"
+	dst, err := mount.Open(dstName, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	defer mount.Unlink(dstName)
+	defer dst.Close()
+
+	t.Run("whole", func(t *testing.T) {
+		n, err := CopyFileRange(src, 0, dst, 0, int64(len(data)))
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(data), n)
+
+		buf := make([]byte, len(data))
+		r, err := dst.ReadAt(buf, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, len(data), r)
+		assert.Equal(t, data, buf)
+	})
+
+	t.Run("offsets", func(t *testing.T) {
+		n, err := CopyFileRange(src, 16, dst, 3, 10)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 10, n)
+
+		buf := make([]byte, 16)
+		_, err = dst.ReadAt(buf, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "0120123456789def", string(buf))
+	})
+
+	t.Run("pastEOF", func(t *testing.T) {
+		n, err := CopyFileRange(src, int64(len(data))-5, dst, 0, 100)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 5, n)
+	})
+
+	t.Run("offsetUnchanged", func(t *testing.T) {
+		pos, err := src.Seek(0, 1)
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(data), pos)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := CopyFileRange(src, -1, dst, 0, 10)
+		assert.Error(t, err)
+		_, err = CopyFileRange(src, 0, dst, 0, -10)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,288 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <dirent.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// Directory represents an open directory handle.
+//
+// A Directory may be used by multiple goroutines at once, however the calls
+// are serialized as they all advance the same directory stream.
+type Directory struct {
+	mu       sync.Mutex
+	mount    *MountInfo
+	dir      *C.struct_ceph_dir_result
+	handleID uint64
+}
+
+// lock locks the directory for a call that uses its directory stream. If
+// the directory is closed the lock is not taken and an error is returned.
+func (dir *Directory) lock() error {
+	dir.mu.Lock()
+	if dir.dir == nil {
+		dir.mu.Unlock()
+		return errBadFile
+	}
+	return nil
+}
+
+// OpenDir returns a new Directory handle open for I/O.
+//
+// Implements:
+//  int ceph_opendir(struct ceph_mount_info *cmount, const char *name, struct ceph_dir_result **dirpp);
+func (mount *MountInfo) OpenDir(path string) (*Directory, error) {
+	var dir *C.struct_ceph_dir_result
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	ret := C.ceph_opendir(mount.mount, cPath, &dir)
+	if ret != 0 {
+		return nil, getError(ret)
+	}
+
+	d := &Directory{
+		mount: mount,
+		dir:   dir,
+	}
+	mount.trackDir(d, path)
+	return d, nil
+}
+
+// Close the open directory handle.
+//
+// Implements:
+//  int ceph_closedir(struct ceph_mount_info *cmount, struct ceph_dir_result *dirp);
+func (dir *Directory) Close() error {
+	dir.mu.Lock()
+	defer dir.mu.Unlock()
+	if dir.dir == nil {
+		// already closed
+		return nil
+	}
+	if err := getError(C.ceph_closedir(dir.mount.mount, dir.dir)); err != nil {
+		return err
+	}
+	dir.dir = nil
+	dir.mount.handles.remove(dir.handleID)
+	return nil
+}
+
+// DType values are used to determine, when possible, the file type
+// of a directory entry.
+type DType uint8
+
+const (
+	// DTypeBlk indicates a directory entry is a block device.
+	DTypeBlk = DType(C.DT_BLK)
+	// DTypeChr indicates a directory entry is a character device.
+	DTypeChr = DType(C.DT_CHR)
+	// DTypeDir indicates a directory entry is a directory.
+	DTypeDir = DType(C.DT_DIR)
+	// DTypeFIFO indicates a directory entry is a named pipe (FIFO).
+	DTypeFIFO = DType(C.DT_FIFO)
+	// DTypeLnk indicates a directory entry is a symbolic link.
+	DTypeLnk = DType(C.DT_LNK)
+	// DTypeReg indicates a directory entry is a regular file.
+	DTypeReg = DType(C.DT_REG)
+	// DTypeSock indicates a directory entry is a UNIX domain socket.
+	DTypeSock = DType(C.DT_SOCK)
+	// DTypeUnknown indicates that the file type could not be determined.
+	DTypeUnknown = DType(C.DT_UNKNOWN)
+)
+
+// DirEntry represents an entry within a directory.
+type DirEntry struct {
+	inode uint64
+	name  string
+	dtype DType
+}
+
+// Name returns the directory entry's name.
+func (d *DirEntry) Name() string {
+	return d.name
+}
+
+// Inode returns the directory entry's inode number.
+func (d *DirEntry) Inode() uint64 {
+	return d.inode
+}
+
+// DType returns the Directory-entry's Type, indicating if it is a
+// regular file, directory, etc.
+// DType may be unknown and thus require an additional call
+// (stat for example) if Unknown.
+func (d *DirEntry) DType() DType {
+	return d.dtype
+}
+
+func toDirEntry(de *C.struct_dirent) *DirEntry {
+	return &DirEntry{
+		inode: uint64(de.d_ino),
+		name:  C.GoString(&de.d_name[0]),
+		dtype: DType(de.d_type),
+	}
+}
+
+// ReadDir reads a single directory entry from the open Directory.
+// A nil DirEntry pointer will be returned when the Directory stream has been
+// exhausted.
+//
+// Implements:
+//  int ceph_readdir_r(struct ceph_mount_info *cmount, struct ceph_dir_result *dirp, struct dirent *de);
+func (dir *Directory) ReadDir() (*DirEntry, error) {
+	if err := dir.lock(); err != nil {
+		return nil, err
+	}
+	defer dir.mu.Unlock()
+
+	var de C.struct_dirent
+	ret := C.ceph_readdir_r(dir.mount.mount, dir.dir, &de)
+	if ret < 0 {
+		return nil, getError(ret)
+	}
+	if ret == 0 {
+		return nil, nil // End-of-stream
+	}
+	return toDirEntry(&de), nil
+}
+
+// List returns the names of all the entries in the Directory, excluding
+// the "." and ".." entries. The Directory stream is consumed by the call.
+func (dir *Directory) List() ([]string, error) {
+	names := []string{}
+	for {
+		entry, err := dir.ReadDir()
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return names, nil
+		}
+		if entry.name == "." || entry.name == ".." {
+			continue
+		}
+		names = append(names, entry.name)
+	}
+}
+
+// RewindDir sets the position of the Directory stream back to the
+// beginning of the directory.
+//
+// Implements:
+//  void ceph_rewinddir(struct ceph_mount_info *cmount, struct ceph_dir_result *dirp);
+func (dir *Directory) RewindDir() error {
+	if err := dir.lock(); err != nil {
+		return err
+	}
+	defer dir.mu.Unlock()
+
+	C.ceph_rewinddir(dir.mount.mount, dir.dir)
+	return nil
+}
+
+// TellDir returns the current position of the Directory stream. The position
+// is an opaque value that is only meaningful to SeekDir. Besides returning to
+// an earlier position of the same stream, it can be used to resume a
+// listing with a Directory opened later for the same directory, for
+// example to serve a large listing in pages.
+//
+// Implements:
+//  int64_t ceph_telldir(struct ceph_mount_info *cmount, struct ceph_dir_result *dirp);
+func (dir *Directory) TellDir() (int64, error) {
+	if err := dir.lock(); err != nil {
+		return 0, err
+	}
+	defer dir.mu.Unlock()
+
+	ret := C.ceph_telldir(dir.mount.mount, dir.dir)
+	if ret < 0 {
+		return 0, getError(C.int(ret))
+	}
+	return int64(ret), nil
+}
+
+// SeekDir sets the position of the Directory stream to an offset previously
+// returned by TellDir. The next entry read is the one that followed the
+// entries read before the offset was obtained.
+//
+// Implements:
+//  void ceph_seekdir(struct ceph_mount_info *cmount, struct ceph_dir_result *dirp, int64_t offset);
+func (dir *Directory) SeekDir(offset int64) error {
+	if err := dir.lock(); err != nil {
+		return err
+	}
+	defer dir.mu.Unlock()
+
+	if offset < 0 {
+		return ErrInvalid
+	}
+	C.ceph_seekdir(dir.mount.mount, dir.dir, C.int64_t(offset))
+	return nil
+}
+
+// DirEntryPlus is a DirEntry plus additional data (stat) for an entry
+// within a directory.
+type DirEntryPlus struct {
+	DirEntry
+	// statx: the converted statx returned by ceph_readdirplus_r
+	statx *CephStatx
+}
+
+// Statx returns cached stat metadata for the directory entry.
+// This call does not incur an actual file system stat.
+func (d *DirEntryPlus) Statx() *CephStatx {
+	return d.statx
+}
+
+// ReadDirPlus reads a single directory entry and stat information from the
+// open Directory.
+// A nil DirEntryPlus pointer will be returned when the Directory stream has
+// been exhausted.
+// The want parameter selects which stat fields should be filled in and the
+// flags parameter controls how they are fetched, see StatxMask and AtFlags.
+//
+// Implements:
+//  int ceph_readdirplus_r(struct ceph_mount_info *cmount, struct ceph_dir_result *dirp, struct dirent *de,
+//                         struct ceph_statx *stx, unsigned want, unsigned flags, struct Inode **out);
+func (dir *Directory) ReadDirPlus(
+	want StatxMask, flags AtFlags) (*DirEntryPlus, error) {
+	if err := dir.lock(); err != nil {
+		return nil, err
+	}
+	defer dir.mu.Unlock()
+
+	var (
+		de C.struct_dirent
+		s  C.struct_ceph_statx
+	)
+	ret := C.ceph_readdirplus_r(
+		dir.mount.mount,
+		dir.dir,
+		&de,
+		&s,
+		C.uint(want),
+		C.uint(flags),
+		nil, // unused, internal Inode type not needed for high level api
+	)
+	if ret < 0 {
+		return nil, getError(ret)
+	}
+	if ret == 0 {
+		return nil, nil // End-of-stream
+	}
+	return &DirEntryPlus{
+		DirEntry: *toDirEntry(&de),
+		statx:    cStructToCephStatx(s),
+	}, nil
+}
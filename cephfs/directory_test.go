@@ -0,0 +1,186 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenCloseDir(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir1 := "/base"
+	err := mount.MakeDir(dir1, 0755)
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, mount.RemoveDir(dir1)) }()
+
+	dir, err := mount.OpenDir(dir1)
+	assert.NoError(t, err)
+	require.NotNil(t, dir)
+	assert.NoError(t, dir.Close())
+	// closing a second time is harmless
+	assert.NoError(t, dir.Close())
+
+	_, err = mount.OpenDir("/no.such.dir")
+	assert.Error(t, err)
+}
+
+func TestReadDir(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir1 := "/readdir"
+	require.NoError(t, mount.MakeDir(dir1, 0755))
+	defer func() { assert.NoError(t, mount.RemoveDir(dir1)) }()
+
+	subdirs := []string{"a", "bb", "ccc"}
+	for _, s := range subdirs {
+		require.NoError(t, mount.MakeDir(dir1+"/"+s, 0755))
+		defer mount.RemoveDir(dir1 + "/" + s)
+	}
+	f, err := mount.Open(dir1+"/file", os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer os.Remove(CephMountTest + dir1 + "/file")
+
+	t.Run("entries", func(t *testing.T) {
+		dir, err := mount.OpenDir(dir1)
+		require.NoError(t, err)
+		defer dir.Close()
+
+		found := map[string]DType{}
+		for {
+			entry, err := dir.ReadDir()
+			require.NoError(t, err)
+			if entry == nil {
+				break
+			}
+			assert.NotEqual(t, uint64(0), entry.Inode())
+			found[entry.Name()] = entry.DType()
+		}
+		assert.Len(t, found, 6)
+		assert.Equal(t, DTypeDir, found["."])
+		assert.Equal(t, DTypeDir, found["bb"])
+		assert.Equal(t, DTypeReg, found["file"])
+	})
+
+	t.Run("list", func(t *testing.T) {
+		dir, err := mount.OpenDir(dir1)
+		require.NoError(t, err)
+		defer dir.Close()
+
+		names, err := dir.List()
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"a", "bb", "ccc", "file"}, names)
+	})
+}
+
+func TestReadDirPlus(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir1 := "/readdirplus"
+	require.NoError(t, mount.MakeDir(dir1, 0755))
+	defer func() { assert.NoError(t, mount.RemoveDir(dir1)) }()
+
+	require.NoError(t, mount.MakeDir(dir1+"/sub", 0700))
+	defer mount.RemoveDir(dir1 + "/sub")
+	f, err := mount.Open(dir1+"/data", os.O_WRONLY|os.O_CREATE, 0600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("1234567"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer os.Remove(CephMountTest + dir1 + "/data")
+
+	dir, err := mount.OpenDir(dir1)
+	require.NoError(t, err)
+	defer dir.Close()
+
+	found := map[string]*CephStatx{}
+	for {
+		entry, err := dir.ReadDirPlus(StatxBasicStats, AtNoAttrSync)
+		require.NoError(t, err)
+		if entry == nil {
+			break
+		}
+		require.NotNil(t, entry.Statx())
+		assert.Equal(t, entry.Inode(), entry.Statx().Inode)
+		found[entry.Name()] = entry.Statx()
+	}
+	require.Contains(t, found, "data")
+	require.Contains(t, found, "sub")
+	assert.EqualValues(t, 7, found["data"].Size)
+	assert.EqualValues(t, 0600, found["data"].Mode&0777)
+	assert.EqualValues(t, 0700, found["sub"].Mode&0777)
+}
+
+func TestDirectoryPosition(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir1 := "/dirposition"
+	require.NoError(t, mount.MakeDir(dir1, 0755))
+	defer func() { assert.NoError(t, mount.RemoveDir(dir1)) }()
+
+	for _, s := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, mount.MakeDir(dir1+"/"+s, 0755))
+		defer mount.RemoveDir(dir1 + "/" + s)
+	}
+
+	readNames := func(dir *Directory, n int) []string {
+		names := []string{}
+		for len(names) < n {
+			entry, err := dir.ReadDir()
+			require.NoError(t, err)
+			if entry == nil {
+				break
+			}
+			names = append(names, entry.Name())
+		}
+		return names
+	}
+
+	dir, err := mount.OpenDir(dir1)
+	require.NoError(t, err)
+	defer dir.Close()
+
+	first := readNames(dir, 3)
+	require.Len(t, first, 3)
+	pos, err := dir.TellDir()
+	assert.NoError(t, err)
+	rest := readNames(dir, 10)
+	assert.Len(t, rest, 4)
+
+	t.Run("seek", func(t *testing.T) {
+		assert.NoError(t, dir.SeekDir(pos))
+		assert.Equal(t, rest, readNames(dir, 10))
+		assert.Equal(t, ErrInvalid, dir.SeekDir(-1))
+	})
+
+	t.Run("rewind", func(t *testing.T) {
+		assert.NoError(t, dir.RewindDir())
+		assert.Equal(t, first, readNames(dir, 3))
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		// a listing can be resumed with another handle for the directory
+		dir2, err := mount.OpenDir(dir1)
+		require.NoError(t, err)
+		defer dir2.Close()
+		assert.NoError(t, dir2.SeekDir(pos))
+		assert.Equal(t, rest, readNames(dir2, 10))
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		dir3, err := mount.OpenDir(dir1)
+		require.NoError(t, err)
+		assert.NoError(t, dir3.Close())
+		_, err = dir3.TellDir()
+		assert.Equal(t, errBadFile, err)
+		assert.Equal(t, errBadFile, dir3.SeekDir(0))
+		assert.Equal(t, errBadFile, dir3.RewindDir())
+	})
+}
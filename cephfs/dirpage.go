@@ -0,0 +1,64 @@
+package cephfs
+
+import (
+	"strconv"
+)
+
+// ListDirPage returns up to limit entries of the directory at the given
+// path, excluding the "." and ".." entries, starting at the position given
+// by token. An empty token starts at the beginning of the directory. The
+// returned token is passed to the next call to continue the listing; it is
+// empty once the listing is complete. The token is an opaque string that
+// remains valid across mounts of the file system, so a large directory can
+// be listed in pages of bounded size, for example over the requests of a
+// paginated API, without holding the whole listing in memory.
+//
+// As with any directory stream, entries added or removed while the
+// listing is in progress may or may not be returned.
+func (mount *MountInfo) ListDirPage(path, token string, limit int) ([]DirEntry, string, error) {
+	if limit < 1 {
+		return nil, "", ErrInvalid
+	}
+	var offset int64
+	if token != "" {
+		var err error
+		offset, err = strconv.ParseInt(token, 16, 64)
+		if err != nil || offset < 0 {
+			return nil, "", ErrInvalid
+		}
+	}
+
+	dir, err := mount.OpenDir(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer dir.Close()
+	if offset > 0 {
+		if err := dir.SeekDir(offset); err != nil {
+			return nil, "", err
+		}
+	}
+
+	entries := []DirEntry{}
+	for {
+		pos, err := dir.TellDir()
+		if err != nil {
+			return nil, "", err
+		}
+		entry, err := dir.ReadDir()
+		if err != nil {
+			return nil, "", err
+		}
+		if entry == nil {
+			return entries, "", nil
+		}
+		if entry.name == "." || entry.name == ".." {
+			continue
+		}
+		if len(entries) == limit {
+			// more entries remain, continue with this one
+			return entries, strconv.FormatInt(pos, 16), nil
+		}
+		entries = append(entries, *entry)
+	}
+}
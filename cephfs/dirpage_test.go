@@ -0,0 +1,70 @@
+package cephfs
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDirPage(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/listdirpage"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveAll(dir)
+
+	expected := []string{}
+	for i := 0; i < 25; i++ {
+		name := fmt.Sprintf("file%02d", i)
+		f, err := mount.Open(dir+"/"+name, os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		assert.NoError(t, f.Close())
+		expected = append(expected, name)
+	}
+
+	for _, limit := range []int{1, 7, 25, 100} {
+		t.Run(fmt.Sprintf("limit%d", limit), func(t *testing.T) {
+			names := []string{}
+			token := ""
+			pages := 0
+			for {
+				entries, next, err := mount.ListDirPage(dir, token, limit)
+				require.NoError(t, err)
+				assert.True(t, len(entries) <= limit)
+				for _, e := range entries {
+					names = append(names, e.Name())
+				}
+				pages++
+				require.True(t, pages <= 26, "listing does not terminate")
+				if next == "" {
+					break
+				}
+				assert.Len(t, entries, limit)
+				token = next
+			}
+			assert.ElementsMatch(t, expected, names)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		require.NoError(t, mount.MakeDir(dir+"/empty", 0755))
+		defer mount.RemoveDir(dir + "/empty")
+		entries, next, err := mount.ListDirPage(dir+"/empty", "", 10)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 0)
+		assert.Equal(t, "", next)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := mount.ListDirPage(dir, "", 0)
+		assert.Equal(t, ErrInvalid, err)
+		_, _, err = mount.ListDirPage(dir, "not-a-token", 10)
+		assert.Equal(t, ErrInvalid, err)
+		_, _, err = mount.ListDirPage(dir+"/missing", "", 10)
+		assert.Equal(t, ErrNotExist, err)
+	})
+}
@@ -0,0 +1,94 @@
+package cephfs
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DirStats holds the statistics CephFS maintains for a directory. The
+// recursive (R prefixed) values cover the whole tree below the directory
+// and are propagated up the tree lazily, so they may lag slightly behind
+// recent changes.
+type DirStats struct {
+	// Entries is the number of entries in the directory.
+	Entries uint64
+	// Files is the number of files in the directory.
+	Files uint64
+	// Subdirs is the number of subdirectories of the directory.
+	Subdirs uint64
+	// REntries is the number of files and directories below the directory.
+	REntries uint64
+	// RFiles is the number of files below the directory.
+	RFiles uint64
+	// RSubdirs is the number of directories below the directory.
+	RSubdirs uint64
+	// RBytes is the total size of the files below the directory.
+	RBytes uint64
+	// RCtime is the most recent change time of any file or directory
+	// below the directory.
+	RCtime Timespec
+}
+
+func (mount *MountInfo) getUintXattr(path, name string) (uint64, error) {
+	value, err := mount.GetXattr(path, name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(value)), 10, 64)
+}
+
+// parseRctime parses the "<seconds>.<nanoseconds>" format of the
+// ceph.dir.rctime vxattr.
+func parseRctime(value string) (Timespec, error) {
+	ts := Timespec{}
+	parts := strings.SplitN(strings.TrimSpace(value), ".", 2)
+	sec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return ts, err
+	}
+	ts.Sec = sec
+	if len(parts) == 2 {
+		nsec, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return ts, err
+		}
+		ts.Nsec = nsec
+	}
+	return ts, nil
+}
+
+// GetDirStats returns the statistics of the directory at the given path,
+// read from the ceph.dir.* virtual extended attributes. This is a cheap way
+// to find the recursive size of a tree without walking it.
+func (mount *MountInfo) GetDirStats(path string) (*DirStats, error) {
+	stats := &DirStats{}
+	fields := []struct {
+		name  string
+		value *uint64
+	}{
+		{"ceph.dir.entries", &stats.Entries},
+		{"ceph.dir.files", &stats.Files},
+		{"ceph.dir.subdirs", &stats.Subdirs},
+		{"ceph.dir.rentries", &stats.REntries},
+		{"ceph.dir.rfiles", &stats.RFiles},
+		{"ceph.dir.rsubdirs", &stats.RSubdirs},
+		{"ceph.dir.rbytes", &stats.RBytes},
+	}
+	for _, field := range fields {
+		v, err := mount.getUintXattr(path, field.name)
+		if err != nil {
+			return nil, err
+		}
+		*field.value = v
+	}
+
+	value, err := mount.GetXattr(path, "ceph.dir.rctime")
+	if err != nil {
+		return nil, err
+	}
+	stats.RCtime, err = parseRctime(string(value))
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,55 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRctime(t *testing.T) {
+	ts, err := parseRctime("1589315123.000123456")
+	assert.NoError(t, err)
+	assert.Equal(t, Timespec{Sec: 1589315123, Nsec: 123456}, ts)
+
+	ts, err = parseRctime("1589315123\n")
+	assert.NoError(t, err)
+	assert.Equal(t, Timespec{Sec: 1589315123}, ts)
+
+	_, err = parseRctime("x.1")
+	assert.Error(t, err)
+	_, err = parseRctime("1.x")
+	assert.Error(t, err)
+}
+
+func TestGetDirStats(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	root := "TestGetDirStats"
+	require.NoError(t, mount.MakeDirs(root+"/sub", 0755))
+	defer mount.RemoveAll(root)
+	for _, fname := range []string{root + "/f1", root + "/sub/f2"} {
+		f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		_, err = f.Write([]byte("12345"))
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+	}
+	// make sure the recursive stats are propagated
+	require.NoError(t, mount.SyncFs())
+
+	stats, err := mount.GetDirStats(root)
+	assert.NoError(t, err)
+	require.NotNil(t, stats)
+	assert.EqualValues(t, 2, stats.Entries)
+	assert.EqualValues(t, 1, stats.Files)
+	assert.EqualValues(t, 1, stats.Subdirs)
+	assert.EqualValues(t, 2, stats.RFiles)
+	assert.EqualValues(t, 10, stats.RBytes)
+	assert.NotEqual(t, int64(0), stats.RCtime.Sec)
+
+	_, err = mount.GetDirStats("TestGetDirStats.missing")
+	assert.Error(t, err)
+}
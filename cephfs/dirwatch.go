@@ -0,0 +1,94 @@
+package cephfs
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// dirWatchBufferSize is the number of notifications a DirWatch buffers
+// before further notifications are dropped.
+const dirWatchBufferSize = 64
+
+// DirNotification reports that the client was told by the MDS to drop
+// cached state of a watched directory. If Name is set, the cached entry
+// with that name, for the inode Ino, was invalidated. Otherwise the cached
+// contents of the directory itself were invalidated.
+type DirNotification struct {
+	DirIno uint64
+	Ino    uint64
+	Name   string
+}
+
+// DirWatch delivers the notifications of a directory watched with
+// WatchDir.
+type DirWatch struct {
+	// C is the channel on which the notifications are delivered.
+	C <-chan DirNotification
+
+	c       chan DirNotification
+	mount   *MountInfo
+	inode   *Inode
+	ino     uint64
+	dropped uint64
+}
+
+// dirWatchRegistry keeps track of the directories watched through a mount.
+type dirWatchRegistry struct {
+	mu sync.Mutex
+	// handle identifies the mount in the callbacks. It is set when the
+	// callbacks are registered and never freed: the callbacks can not be
+	// unregistered, so the address must not be reused by another mount.
+	handle  unsafe.Pointer
+	watches map[*DirWatch]struct{}
+}
+
+// watchedMounts maps the handles of the mounts with watched directories to
+// their MountInfo.
+var watchedMounts = struct {
+	sync.Mutex
+	m map[uintptr]*MountInfo
+}{m: map[uintptr]*MountInfo{}}
+
+// Dropped returns the number of notifications that were dropped because
+// the channel of the watch was full.
+func (w *DirWatch) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Close stops watching the directory. No notifications are delivered once
+// Close returns, however the channel is not closed.
+func (w *DirWatch) Close() error {
+	r := &w.mount.dirWatches
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, found := r.watches[w]; !found {
+		// already closed
+		return nil
+	}
+	delete(r.watches, w)
+	if len(r.watches) == 0 {
+		// the callbacks stay registered, but are ignored until the
+		// next watch
+		watchedMounts.Lock()
+		delete(watchedMounts.m, uintptr(r.handle))
+		watchedMounts.Unlock()
+	}
+	return w.inode.Release()
+}
+
+// notify delivers a notification to the watches of the directory.
+func (r *dirWatchRegistry) notify(n DirNotification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for w := range r.watches {
+		if w.ino != n.DirIno {
+			continue
+		}
+		select {
+		case w.c <- n:
+		default:
+			atomic.AddUint64(&w.dropped, 1)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// +build !luminous
+//
+// Ceph Mimic is the first release that includes ceph_ll_register_callbacks().
+
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+
+extern void dirWatchInodeCallback(void *handle, vinodeno_t ino, int64_t off, int64_t len);
+extern void dirWatchDentryCallback(void *handle, vinodeno_t dirino, vinodeno_t ino,
+                                   char *name, size_t len);
+*/
+import "C"
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// EnableDirWatch registers the callbacks through which the client is told
+// about the invalidation of its cached directories, allowing directories to
+// be watched with WatchDir.
+//
+// This must be called after Init but before Mount. ErrNotConnected is
+// returned if Init was not called.
+//
+// Implements:
+//  void ceph_ll_register_callbacks(struct ceph_mount_info *cmount,
+//                                  struct ceph_client_callback_args *args);
+func (mount *MountInfo) EnableDirWatch() error {
+	// the callbacks are registered with the client created by Init
+	if atomic.LoadInt32(&mount.initialized) == 0 {
+		return ErrNotConnected
+	}
+	if mount.IsMounted() {
+		return CephFSError(-C.EISCONN)
+	}
+	r := &mount.dirWatches
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.handle != nil {
+		return nil
+	}
+
+	args := (*C.struct_ceph_client_callback_args)(
+		C.calloc(1, C.size_t(unsafe.Sizeof(C.struct_ceph_client_callback_args{}))))
+	defer C.free(unsafe.Pointer(args))
+	r.handle = C.malloc(1)
+	args.handle = r.handle
+	args.ino_cb = C.client_ino_callback_t(C.dirWatchInodeCallback)
+	args.dentry_cb = C.client_dentry_callback_t(C.dirWatchDentryCallback)
+	C.ceph_ll_register_callbacks(mount.mount, args)
+	return nil
+}
+
+// WatchDir starts watching the directory at the given path for changes
+// made by other clients. Whenever such a change makes the MDS revoke the
+// client's cached view of the directory or of one of its entries, a
+// DirNotification is delivered on the C channel of the returned DirWatch.
+// The directory is kept in the client's cache while it is watched.
+// EnableDirWatch must have been called before the mount was mounted,
+// otherwise ErrInvalid is returned.
+//
+// The notifications are hints: they carry no description of the change,
+// they may also be caused by the client trimming its own cache, and
+// notifications are dropped if the channel is not read fast enough (see
+// Dropped). A consumer is expected to read the directory again on each
+// notification. Only the entries that the client has cached, for example
+// because they were read since the last notification, produce
+// notifications. Close must be called to stop watching.
+func (mount *MountInfo) WatchDir(path string) (*DirWatch, error) {
+	r := &mount.dirWatches
+	r.mu.Lock()
+	enabled := r.handle != nil
+	r.mu.Unlock()
+	if !enabled {
+		return nil, ErrInvalid
+	}
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	var (
+		inode *C.struct_Inode
+		stx   C.struct_ceph_statx
+	)
+	ret := C.ceph_ll_walk(mount.mount, cPath, &inode, &stx,
+		C.uint(StatxIno|StatxMode), 0, C.ceph_mount_perms(mount.mount))
+	if ret != 0 {
+		return nil, getError(ret)
+	}
+	if !isDirMode(uint16(stx.stx_mode)) {
+		C.ceph_ll_put(mount.mount, inode)
+		return nil, ErrNotDir
+	}
+
+	c := make(chan DirNotification, dirWatchBufferSize)
+	w := &DirWatch{
+		C:     c,
+		c:     c,
+		mount: mount,
+		inode: &Inode{mount: mount, inode: inode},
+		ino:   uint64(stx.stx_ino),
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.watches == nil {
+		r.watches = map[*DirWatch]struct{}{}
+	}
+	r.watches[w] = struct{}{}
+	watchedMounts.Lock()
+	watchedMounts.m[uintptr(r.handle)] = mount
+	watchedMounts.Unlock()
+	return w, nil
+}
+
+func watchedMount(handle unsafe.Pointer) *MountInfo {
+	watchedMounts.Lock()
+	defer watchedMounts.Unlock()
+	return watchedMounts.m[uintptr(handle)]
+}
+
+//export dirWatchInodeCallback
+func dirWatchInodeCallback(handle unsafe.Pointer, ino C.vinodeno_t, off, length C.int64_t) {
+	if mount := watchedMount(handle); mount != nil {
+		mount.dirWatches.notify(DirNotification{
+			DirIno: uint64(ino.ino.val),
+			Ino:    uint64(ino.ino.val),
+		})
+	}
+}
+
+//export dirWatchDentryCallback
+func dirWatchDentryCallback(handle unsafe.Pointer, dirIno, ino C.vinodeno_t, name *C.char, length C.size_t) {
+	if mount := watchedMount(handle); mount != nil {
+		mount.dirWatches.notify(DirNotification{
+			DirIno: uint64(dirIno.ino.val),
+			Ino:    uint64(ino.ino.val),
+			Name:   C.GoStringN(name, C.int(length)),
+		})
+	}
+}
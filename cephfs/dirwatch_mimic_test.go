@@ -0,0 +1,90 @@
+// +build !luminous
+
+package cephfs
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fsConnectWithDirWatch(t *testing.T) *MountInfo {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	require.NoError(t, mount.ReadDefaultConfigFile())
+	// the client does not exist before Init
+	require.Equal(t, ErrNotConnected, mount.EnableDirWatch())
+	require.NoError(t, mount.Init())
+	require.NoError(t, mount.EnableDirWatch())
+	// enabling a second time is harmless
+	require.NoError(t, mount.EnableDirWatch())
+	require.NoError(t, mount.Mount())
+	return mount
+}
+
+func TestWatchDir(t *testing.T) {
+	mount := fsConnectWithDirWatch(t)
+	defer mount.Unmount()
+
+	dir := "/watchdir"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveAll(dir)
+	f, err := mount.Open(dir+"/a", os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	w, err := mount.WatchDir(dir)
+	require.NoError(t, err)
+	require.NotNil(t, w)
+	defer w.Close()
+
+	// cache the entry so that its removal is noticed
+	st, err := mount.Statx(dir+"/a", StatxIno, 0)
+	require.NoError(t, err)
+
+	mount2 := fsConnect(t)
+	defer mount2.Unmount()
+	require.NoError(t, mount2.Unlink(dir+"/a"))
+
+	timeout := time.After(10 * time.Second)
+	for found := false; !found; {
+		select {
+		case n := <-w.C:
+			assert.NotZero(t, n.DirIno)
+			found = n.Name == "a" && n.Ino == st.Inode
+		case <-timeout:
+			t.Fatal("no notification for the removed entry")
+		}
+	}
+	assert.Equal(t, uint64(0), w.Dropped())
+
+	assert.NoError(t, w.Close())
+	// closing a second time is harmless
+	assert.NoError(t, w.Close())
+
+	t.Run("watchAgain", func(t *testing.T) {
+		w, err := mount.WatchDir(dir)
+		require.NoError(t, err)
+		assert.NoError(t, w.Close())
+	})
+
+	t.Run("notEnabled", func(t *testing.T) {
+		_, err := mount2.WatchDir(dir)
+		assert.Equal(t, ErrInvalid, err)
+		// the callbacks must be registered before mounting
+		assert.Error(t, mount2.EnableDirWatch())
+	})
+
+	t.Run("notDir", func(t *testing.T) {
+		f, err := mount.Open(dir+"/file", os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		assert.NoError(t, f.Close())
+		_, err = mount.WatchDir(dir + "/file")
+		assert.Equal(t, ErrNotDir, err)
+		_, err = mount.WatchDir(dir + "/missing")
+		assert.Equal(t, ErrNotExist, err)
+	})
+}
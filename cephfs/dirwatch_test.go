@@ -0,0 +1,26 @@
+package cephfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirWatchRegistryNotify(t *testing.T) {
+	var r dirWatchRegistry
+	c1 := make(chan DirNotification, 1)
+	c2 := make(chan DirNotification, 1)
+	w1 := &DirWatch{C: c1, c: c1, ino: 1}
+	w2 := &DirWatch{C: c2, c: c2, ino: 2}
+	r.watches = map[*DirWatch]struct{}{w1: {}, w2: {}}
+
+	n := DirNotification{DirIno: 1, Ino: 10, Name: "x"}
+	r.notify(n)
+	assert.Equal(t, n, <-w1.C)
+	assert.Len(t, w2.C, 0)
+
+	// a full channel drops notifications
+	r.notify(n)
+	r.notify(n)
+	assert.Equal(t, uint64(1), w1.Dropped())
+}
@@ -1,4 +1,29 @@
 /*
 Package cephfs contains a set of wrappers around Ceph's libcephfs API.
+
+Concurrency
+
+libcephfs serializes the work of a mount internally, so a mounted MountInfo
+may be used by multiple goroutines at once without additional locking. The
+calls that set up or tear down the mount (the configuration functions,
+Init, Mount, Unmount, AbortConn and Release) are the exception: they must
+not run concurrently with any other call on the same MountInfo.
+
+File, FileHandle and Directory values may also be shared between
+goroutines; see their documentation for the details of how calls on them
+interact.
+
+Caching
+
+The client caches metadata and file data under capabilities granted by the
+MDS. Before another client changes a file, the MDS revokes the conflicting
+capabilities, which invalidates what was cached. Cached metadata is thus
+kept coherent without any help from the application and libcephfs offers
+no call to invalidate it explicitly. The exceptions are calls made with
+AtNoAttrSync, which may return attributes without checking that they are
+current, and files with lazy I/O enabled, whose cached data is synchronized
+on demand with File.LazyIOSynchronize. DropMDSCache can be used to make
+the MDS recall capabilities, and thereby cached metadata, from all of its
+clients.
 */
 package cephfs
@@ -0,0 +1,56 @@
+package cephfs
+
+/*
+#include <errno.h>
+*/
+import "C"
+
+import (
+	"os"
+)
+
+// Errors returned by the functions of this package for common error
+// conditions. They are CephFSError values and so can be compared directly
+// with the errors returned.
+var (
+	// ErrNotExist indicates the file or directory does not exist.
+	ErrNotExist = CephFSError(-C.ENOENT)
+	// ErrExist indicates the file or directory already exists.
+	ErrExist = CephFSError(-C.EEXIST)
+	// ErrPermission indicates the operation is not permitted.
+	ErrPermission = CephFSError(-C.EPERM)
+	// ErrAccessDenied indicates the caller lacks permission to access the
+	// file or directory.
+	ErrAccessDenied = CephFSError(-C.EACCES)
+	// ErrNotEmpty indicates the directory is not empty.
+	ErrNotEmpty = CephFSError(-C.ENOTEMPTY)
+	// ErrNotDir indicates a path component is not a directory.
+	ErrNotDir = CephFSError(-C.ENOTDIR)
+	// ErrIsDir indicates the operation can not be applied to a
+	// directory.
+	ErrIsDir = CephFSError(-C.EISDIR)
+	// ErrInvalid indicates an invalid argument was supplied.
+	ErrInvalid = CephFSError(-C.EINVAL)
+	// ErrNotConnected indicates the client of the mount handle has not
+	// been created yet.
+	ErrNotConnected = CephFSError(-C.ENOTCONN)
+	// ErrStale indicates a file handle refers to a file that no longer
+	// exists.
+	ErrStale = CephFSError(-C.ESTALE)
+)
+
+// Is returns true if the error is equivalent to target. It allows the
+// errors.Is function, available with Go 1.13 or newer, to match a
+// CephFSError with the portable errors of the os package, such as
+// os.ErrNotExist, in the same way a syscall.Errno is matched.
+func (e CephFSError) Is(target error) bool {
+	switch target {
+	case os.ErrNotExist:
+		return e == ErrNotExist
+	case os.ErrExist:
+		return e == ErrExist || e == ErrNotEmpty
+	case os.ErrPermission:
+		return e == ErrPermission || e == ErrAccessDenied
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+// +build go1.13
+
+package cephfs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorsIs(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	_, err := mount.Open("/no.such.file", os.O_RDONLY, 0)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.True(t, errors.Is(err, ErrNotExist))
+	assert.False(t, errors.Is(err, os.ErrPermission))
+
+	wrapped := fmt.Errorf("opening: %w", err)
+	assert.True(t, errors.Is(wrapped, os.ErrNotExist))
+}
@@ -0,0 +1,34 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorIs(t *testing.T) {
+	assert.True(t, ErrNotExist.Is(os.ErrNotExist))
+	assert.False(t, ErrNotExist.Is(os.ErrExist))
+	assert.True(t, ErrExist.Is(os.ErrExist))
+	assert.True(t, ErrNotEmpty.Is(os.ErrExist))
+	assert.True(t, ErrPermission.Is(os.ErrPermission))
+	assert.True(t, ErrAccessDenied.Is(os.ErrPermission))
+	assert.False(t, ErrInvalid.Is(os.ErrInvalid))
+	assert.False(t, CephFSError(-345).Is(os.ErrNotExist))
+}
+
+func TestSentinelErrors(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	_, err := mount.Statx("/no.such.file", StatxBasicStats, 0)
+	assert.Equal(t, ErrNotExist, err)
+
+	assert.NoError(t, mount.MakeDir("/sentinel", 0755))
+	defer func() { assert.NoError(t, mount.RemoveAll("/sentinel")) }()
+	assert.Equal(t, ErrExist, mount.MakeDir("/sentinel", 0755))
+	assert.NoError(t, mount.MakeDir("/sentinel/sub", 0755))
+	assert.Equal(t, ErrNotEmpty, mount.RemoveDir("/sentinel"))
+	assert.Equal(t, ErrIsDir, mount.Unlink("/sentinel"))
+}
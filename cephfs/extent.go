@@ -0,0 +1,109 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// FileExtent describes the OSDs that store a range of a file's data.
+type FileExtent struct {
+	// Offset of the first byte of the extent within the file.
+	Offset int64
+	// Length of the extent in bytes. All the data in the extent is
+	// stored in the same RADOS object.
+	Length int64
+	// OSDs lists the ids of the OSDs holding the extent's object, with
+	// the primary OSD first.
+	OSDs []int
+}
+
+// GetExtentOSDs returns the extent of the open file containing the given
+// offset, along with the OSDs that store it. Applications can use this to
+// place work close to the data it operates on.
+//
+// Implements:
+//  int ceph_get_file_extent_osds(struct ceph_mount_info *cmount, int fh, int64_t offset,
+//                                int64_t *length, int *osds, int nosds);
+func (f *File) GetExtentOSDs(offset int64) (*FileExtent, error) {
+	if err := f.rlock(); err != nil {
+		return nil, err
+	}
+	defer f.mu.RUnlock()
+
+	var length C.int64_t
+	ret := C.ceph_get_file_extent_osds(
+		f.mount.mount, f.fd, C.int64_t(offset), nil, nil, 0)
+	for {
+		if ret < 0 {
+			return nil, getError(ret)
+		}
+		osds := make([]C.int, int(ret)+1)
+		ret = C.ceph_get_file_extent_osds(
+			f.mount.mount,
+			f.fd,
+			C.int64_t(offset),
+			&length,
+			(*C.int)(unsafe.Pointer(&osds[0])),
+			C.int(len(osds)))
+		if ret == -C.ERANGE {
+			// the mapping changed between calls
+			ret = C.ceph_get_file_extent_osds(
+				f.mount.mount, f.fd, C.int64_t(offset), nil, nil, 0)
+			continue
+		}
+		if ret < 0 {
+			return nil, getError(ret)
+		}
+		extent := &FileExtent{
+			Offset: offset,
+			Length: int64(length),
+			OSDs:   make([]int, int(ret)),
+		}
+		for i := range extent.OSDs {
+			extent.OSDs[i] = int(osds[i])
+		}
+		return extent, nil
+	}
+}
+
+// GetReplication returns the number of replicas kept of the open file's
+// data.
+//
+// Implements:
+//  int ceph_get_file_replication(struct ceph_mount_info *cmount, int fh);
+func (f *File) GetReplication() (int, error) {
+	if err := f.rlock(); err != nil {
+		return 0, err
+	}
+	defer f.mu.RUnlock()
+
+	ret := C.ceph_get_file_replication(f.mount.mount, f.fd)
+	if ret < 0 {
+		return 0, getError(ret)
+	}
+	return int(ret), nil
+}
+
+// GetPathReplication returns the number of replicas kept of the data of
+// the file at the given path.
+//
+// Implements:
+//  int ceph_get_path_replication(struct ceph_mount_info *cmount, const char *path);
+func (mount *MountInfo) GetPathReplication(path string) (int, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	ret := C.ceph_get_path_replication(mount.mount, cPath)
+	if ret < 0 {
+		return 0, getError(ret)
+	}
+	return int(ret), nil
+}
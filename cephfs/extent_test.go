@@ -0,0 +1,57 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetExtentOSDs(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestGetExtentOSDs.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+	_, err = f.Write(make([]byte, 8192))
+	require.NoError(t, err)
+	require.NoError(t, f.Fsync(SyncAll))
+
+	layout, err := f.GetLayout()
+	require.NoError(t, err)
+
+	extent, err := f.GetExtentOSDs(4096)
+	assert.NoError(t, err)
+	require.NotNil(t, extent)
+	assert.EqualValues(t, 4096, extent.Offset)
+	// the extent runs to the end of the stripe unit
+	assert.EqualValues(t, int64(layout.StripeUnit)-4096, extent.Length)
+	// the ci environment has a single osd
+	assert.Equal(t, []int{0}, extent.OSDs)
+
+	repl, err := f.GetReplication()
+	assert.NoError(t, err)
+	assert.Equal(t, len(extent.OSDs), repl)
+}
+
+func TestGetPathReplication(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestGetPathReplication.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	repl, err := mount.GetPathReplication(fname)
+	assert.NoError(t, err)
+	assert.True(t, repl >= 1)
+
+	_, err = mount.GetPathReplication("TestGetPathReplication.missing")
+	assert.Error(t, err)
+}
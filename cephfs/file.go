@@ -0,0 +1,460 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <stdio.h>
+#include <linux/falloc.h>
+#include <sys/file.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"io"
+	"sync"
+	"unsafe"
+)
+
+var (
+	errBadFile = CephFSError(-C.EBADF)
+
+	// Compile-time checks that File satisfies the standard library's I/O
+	// interfaces.
+	_ io.Reader   = &File{}
+	_ io.ReaderAt = &File{}
+	_ io.Writer   = &File{}
+	_ io.WriterAt = &File{}
+	_ io.Seeker   = &File{}
+	_ io.Closer   = &File{}
+)
+
+// File represents an open file descriptor in cephfs.
+//
+// A File may be used by multiple goroutines at once. Calls that take an
+// explicit offset, such as ReadAt and WriteAt, can safely run in parallel,
+// while calls using the file position, such as Read, Write and Seek, share
+// that position with each other. Close waits for the calls in progress to
+// complete, after which all calls on the File fail.
+type File struct {
+	// mu is read locked by calls that use fd and write locked by Close
+	mu       sync.RWMutex
+	mount    *MountInfo
+	fd       C.int
+	handleID uint64
+}
+
+// rlock read locks the file for a call that uses its file descriptor. If
+// the file is closed the lock is not taken and an error is returned.
+func (f *File) rlock() error {
+	f.mu.RLock()
+	if f.fd == -1 {
+		f.mu.RUnlock()
+		return errBadFile
+	}
+	return nil
+}
+
+// Open a file at the given path. The flags are the same os.O_* flags
+// a local open would take, see also the O_* constants of this package. The
+// mode is the same as the mode argument for a local open and is applied if
+// a new file is created.
+//
+// Implements:
+//  int ceph_open(struct ceph_mount_info *cmount, const char *path, int flags, mode_t mode);
+func (mount *MountInfo) Open(path string, flags int, mode uint32) (*File, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	ret := C.ceph_open(mount.mount, cPath, C.int(flags), C.mode_t(mode))
+	if ret < 0 {
+		return nil, getError(ret)
+	}
+	f := &File{mount: mount, fd: ret}
+	mount.trackFile(f, path)
+	return f, nil
+}
+
+// Close the file.
+//
+// Implements:
+//  int ceph_close(struct ceph_mount_info *cmount, int fd);
+func (f *File) Close() error {
+	// an abandoned call may hold the read lock forever
+	if f.mount.Abandoned() {
+		return ErrMountAbandoned
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fd == -1 {
+		// already closed
+		return nil
+	}
+	if err := getError(C.ceph_close(f.mount.mount, f.fd)); err != nil {
+		return err
+	}
+	f.fd = -1
+	f.mount.handles.remove(f.handleID)
+	return nil
+}
+
+// read directly wraps the ceph_read call. An offset of -1 reads from the
+// current position of the file.
+//
+// Implements:
+//  int ceph_read(struct ceph_mount_info *cmount, int fd, char *buf, int64_t size, int64_t offset);
+func (f *File) read(buf []byte, offset int64) (int, error) {
+	if err := f.rlock(); err != nil {
+		return 0, err
+	}
+	defer f.mu.RUnlock()
+
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	bufptr := unsafe.Pointer(&buf[0])
+	ret := C.ceph_read(
+		f.mount.mount, f.fd, (*C.char)(bufptr), C.int64_t(len(buf)), C.int64_t(offset))
+	switch {
+	case ret < 0:
+		return 0, getError(ret)
+	case ret == 0:
+		return 0, io.EOF
+	}
+	return int(ret), nil
+}
+
+// Read data from file. Up to len(buf) bytes will be read from the file.
+// The number of bytes read will be returned.
+// When nothing is left to read from the file, Read returns, 0, io.EOF.
+func (f *File) Read(buf []byte) (int, error) {
+	return f.read(buf, -1)
+}
+
+// ReadAt will read data from the file starting at the given offset.
+// Up to len(buf) bytes will be read from the file.
+// The number of bytes read will be returned. As required by io.ReaderAt,
+// ReadAt reads until buf is full, and returns io.EOF if the end of the file
+// is reached first.
+func (f *File) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, ErrInvalid
+	}
+	return readFull(f.read, buf, offset)
+}
+
+// readFull calls read until buf is full, the end of the file is reached or
+// an error occurs, with the semantics of io.ReaderAt.
+func readFull(read func([]byte, int64) (int, error), buf []byte, offset int64) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := read(buf[total:], offset+int64(total))
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// write directly wraps the ceph_write call. An offset of -1 writes at the
+// current position of the file.
+//
+// Implements:
+//  int ceph_write(struct ceph_mount_info *cmount, int fd, const char *buf, int64_t size, int64_t offset);
+func (f *File) write(buf []byte, offset int64) (int, error) {
+	if err := f.rlock(); err != nil {
+		return 0, err
+	}
+	defer f.mu.RUnlock()
+
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	bufptr := unsafe.Pointer(&buf[0])
+	ret := C.ceph_write(
+		f.mount.mount, f.fd, (*C.char)(bufptr), C.int64_t(len(buf)), C.int64_t(offset))
+	if ret < 0 {
+		return 0, getError(ret)
+	}
+	return int(ret), nil
+}
+
+// Write data from buf to the file.
+// The number of bytes written is returned.
+func (f *File) Write(buf []byte) (int, error) {
+	n, err := f.write(buf, -1)
+	if err == nil && n < len(buf) {
+		err = io.ErrShortWrite
+	}
+	return n, err
+}
+
+// WriteAt writes data from buf to the file at the specified offset.
+// The number of bytes written is returned.
+func (f *File) WriteAt(buf []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, ErrInvalid
+	}
+	n, err := f.write(buf, offset)
+	if err == nil && n < len(buf) {
+		err = io.ErrShortWrite
+	}
+	return n, err
+}
+
+// Seek will reposition the file stream based on the given offset.
+// The whence argument takes the same io.Seek* values a local Seek would.
+// The new offset from the start of the file is returned.
+//
+// Implements:
+//  int64_t ceph_lseek(struct ceph_mount_info *cmount, int fd, int64_t offset, int whence);
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if err := f.rlock(); err != nil {
+		return 0, err
+	}
+	defer f.mu.RUnlock()
+
+	var cWhence C.int
+	switch whence {
+	case io.SeekStart:
+		cWhence = C.SEEK_SET
+	case io.SeekCurrent:
+		cWhence = C.SEEK_CUR
+	case io.SeekEnd:
+		cWhence = C.SEEK_END
+	default:
+		return 0, ErrInvalid
+	}
+
+	ret := C.ceph_lseek(f.mount.mount, f.fd, C.int64_t(offset), cWhence)
+	if ret < 0 {
+		return 0, getError(C.int(ret))
+	}
+	return int64(ret), nil
+}
+
+// Truncate sets the size of the open file. If the file is extended the new
+// space reads back as zeros.
+//
+// Implements:
+//  int ceph_ftruncate(struct ceph_mount_info *cmount, int fd, int64_t size);
+func (f *File) Truncate(size int64) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	ret := C.ceph_ftruncate(f.mount.mount, f.fd, C.int64_t(size))
+	return getError(ret)
+}
+
+// FallocFlags represent flags which determine the operation to be
+// performed on the given range.
+// CephFS supports only following two flags.
+type FallocFlags int
+
+const (
+	// FallocNoFlag means default option.
+	FallocNoFlag = FallocFlags(0)
+	// FallocFlKeepSize specifies that the file size will not be changed.
+	FallocFlKeepSize = FallocFlags(C.FALLOC_FL_KEEP_SIZE)
+	// FallocFlPunchHole specifies that the operation is to deallocate
+	// space and zero the byte range. It must be combined with
+	// FallocFlKeepSize.
+	FallocFlPunchHole = FallocFlags(C.FALLOC_FL_PUNCH_HOLE)
+)
+
+// Fallocate preallocates or releases disk space for the file for the
+// given byte range, the flags determine the operation to be performed
+// on the given range.
+//
+// With FallocNoFlag the file is extended, if needed, to cover the range and
+// reads from the new space return zeros. With FallocFlKeepSize the file size
+// is not changed. With FallocFlPunchHole|FallocFlKeepSize the range is
+// deallocated and will read back as zeros, leaving a sparse file.
+//
+// Implements:
+//  int ceph_fallocate(struct ceph_mount_info *cmount, int fd, int mode,
+//                     int64_t offset, int64_t length);
+func (f *File) Fallocate(mode FallocFlags, offset, length int64) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	ret := C.ceph_fallocate(
+		f.mount.mount, f.fd, C.int(mode), C.int64_t(offset), C.int64_t(length))
+	return getError(ret)
+}
+
+// SyncChoice is used to control how metadata and/or data is sync'ed to
+// the file system.
+type SyncChoice int
+
+const (
+	// SyncAll will synchronize both data and metadata.
+	SyncAll = SyncChoice(0)
+	// SyncDataOnly will synchronize only data.
+	SyncDataOnly = SyncChoice(1)
+)
+
+// Fsync ensures the file content that may be cached is committed to stable
+// storage.
+// Pass SyncAll to have this call behave like standard fsync and synchronize
+// all data and metadata.
+// Pass SyncDataOnly to have this call behave more like fdatasync (on linux).
+//
+// Implements:
+//  int ceph_fsync(struct ceph_mount_info *cmount, int fd, int syncdataonly);
+func (f *File) Fsync(sync SyncChoice) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	ret := C.ceph_fsync(f.mount.mount, f.fd, C.int(sync))
+	return getError(ret)
+}
+
+// Sync ensures the file content that may be cached is committed to stable
+// storage.
+// Sync behaves like Go's os package File.Sync function.
+func (f *File) Sync() error {
+	return f.Fsync(SyncAll)
+}
+
+// Preadv will read data from the file, starting at the given offset,
+// into the byte-slice buffers sequentially.
+// The number of bytes read will be returned.
+// When nothing is left to read from the file the return values will be:
+// 0, io.EOF.
+//
+// Implements:
+//  int ceph_preadv(struct ceph_mount_info *cmount, int fd, const struct iovec *iov, int iovcnt,
+//                  int64_t offset);
+func (f *File) Preadv(data [][]byte, offset int64) (int, error) {
+	if err := f.rlock(); err != nil {
+		return 0, err
+	}
+	defer f.mu.RUnlock()
+
+	iov := newIovec(data, false)
+	defer iov.free()
+
+	ret := C.ceph_preadv(
+		f.mount.mount,
+		f.fd,
+		iov.pointer(),
+		iov.length(),
+		C.int64_t(offset))
+	switch {
+	case ret < 0:
+		return 0, getError(ret)
+	case ret == 0:
+		return 0, io.EOF
+	}
+	iov.copyOut(int(ret))
+	return int(ret), nil
+}
+
+// Pwritev writes data from the slice of byte-slice buffers to the file at
+// the specified offset.
+// The number of bytes written is returned.
+//
+// Implements:
+//  int ceph_pwritev(struct ceph_mount_info *cmount, int fd, const struct iovec *iov, int iovcnt,
+//                   int64_t offset);
+func (f *File) Pwritev(data [][]byte, offset int64) (int, error) {
+	if err := f.rlock(); err != nil {
+		return 0, err
+	}
+	defer f.mu.RUnlock()
+
+	iov := newIovec(data, true)
+	defer iov.free()
+
+	ret := C.ceph_pwritev(
+		f.mount.mount,
+		f.fd,
+		iov.pointer(),
+		iov.length(),
+		C.int64_t(offset))
+	if ret < 0 {
+		return 0, getError(ret)
+	}
+	return int(ret), nil
+}
+
+// LockOp determines operations/type of locks which can be applied on a file.
+type LockOp int
+
+const (
+	// LockSH places a shared lock.
+	// More than one process may hold a shared lock for a given file at a
+	// given time.
+	LockSH = LockOp(C.LOCK_SH)
+	// LockEX places an exclusive lock.
+	// Only one process may hold an exclusive lock for a given file at a
+	// given time.
+	LockEX = LockOp(C.LOCK_EX)
+	// LockUN removes an existing lock held by this process.
+	LockUN = LockOp(C.LOCK_UN)
+	// LockNB can be ORed with any of the above to make a nonblocking call.
+	LockNB = LockOp(C.LOCK_NB)
+)
+
+// Flock applies or removes an advisory lock on an open file.
+// Param owner is the user-supplied identifier for the owner of the
+// lock, it must be an arbitrary integer. Locks are shared between all
+// File handles using the same owner value.
+//
+// Implements:
+//  int ceph_flock(struct ceph_mount_info *cmount, int fd, int operation, uint64_t owner);
+func (f *File) Flock(operation LockOp, owner uint64) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	// validate the operation: exactly one of SH, EX or UN, optionally
+	// combined with NB
+	switch operation &^ LockNB {
+	case LockSH, LockEX, LockUN:
+	default:
+		return ErrInvalid
+	}
+
+	ret := C.ceph_flock(f.mount.mount, f.fd, C.int(operation), C.uint64_t(owner))
+	return getError(ret)
+}
+
+// Chmod changes the mode bits (permissions) of the open file.
+//
+// Implements:
+//  int ceph_fchmod(struct ceph_mount_info *cmount, int fd, mode_t mode);
+func (f *File) Chmod(mode uint32) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	ret := C.ceph_fchmod(f.mount.mount, f.fd, C.mode_t(mode))
+	return getError(ret)
+}
+
+// Chown changes the ownership of the open file.
+//
+// Implements:
+//  int ceph_fchown(struct ceph_mount_info *cmount, int fd, int uid, int gid);
+func (f *File) Chown(user uint32, group uint32) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	ret := C.ceph_fchown(f.mount.mount, f.fd, C.int(user), C.int(group))
+	return getError(ret)
+}
@@ -0,0 +1,73 @@
+// +build !luminous,!mimic
+//
+// Ceph Nautilus is the first release that includes ceph_lazyio().
+
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+// LazyIO enables or disables lazy I/O on the open file. With lazy I/O
+// enabled the client relaxes the usual CephFS cache coherency for the file,
+// allowing it to buffer reads and writes even while other clients have the
+// file open. Applications that enable it are responsible for coordinating
+// access to the file's data themselves.
+//
+// Implements:
+//  int ceph_lazyio(struct ceph_mount_info *cmount, int fd, int enable);
+func (f *File) LazyIO(enable bool) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	var cEnable C.int
+	if enable {
+		cEnable = 1
+	}
+	ret := C.ceph_lazyio(f.mount.mount, f.fd, cEnable)
+	return getError(ret)
+}
+
+// LazyIOPropagate writes the data buffered by the client for the given
+// range of a file with lazy I/O enabled back to the OSDs, making it visible
+// to other clients. A count of zero covers the remainder of the file.
+//
+// Implements:
+//  int ceph_lazyio_propagate(struct ceph_mount_info *cmount, int fd, int64_t offset, size_t count);
+func (f *File) LazyIOPropagate(offset int64, count uint64) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	if offset < 0 {
+		return ErrInvalid
+	}
+	ret := C.ceph_lazyio_propagate(f.mount.mount, f.fd, C.int64_t(offset), C.size_t(count))
+	return getError(ret)
+}
+
+// LazyIOSynchronize propagates the buffered writes of the given range of a
+// file with lazy I/O enabled and invalidates the data cached for it, so that
+// later reads fetch the data, including changes made by other clients, from
+// the OSDs. A count of zero covers the remainder of the file.
+//
+// Implements:
+//  int ceph_lazyio_synchronize(struct ceph_mount_info *cmount, int fd, int64_t offset, size_t count);
+func (f *File) LazyIOSynchronize(offset int64, count uint64) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	if offset < 0 {
+		return ErrInvalid
+	}
+	ret := C.ceph_lazyio_synchronize(f.mount.mount, f.fd, C.int64_t(offset), C.size_t(count))
+	return getError(ret)
+}
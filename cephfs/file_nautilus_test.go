@@ -0,0 +1,51 @@
+// +build !luminous,!mimic
+
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileLazyIO(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestFileLazyIO.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+
+	assert.NoError(t, f.LazyIO(true))
+
+	data := []byte("lazy data")
+	_, err = f.WriteAt(data, 0)
+	assert.NoError(t, err)
+	buf := make([]byte, len(data))
+	n, err := f.ReadAt(buf, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, data, buf[:n])
+
+	assert.NoError(t, f.LazyIOPropagate(0, 0))
+	assert.NoError(t, f.LazyIOSynchronize(0, uint64(len(data))))
+	assert.Equal(t, ErrInvalid, f.LazyIOSynchronize(-1, 0))
+
+	// data read after synchronizing comes from the OSDs
+	n, err = f.ReadAt(buf, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, data, buf[:n])
+
+	assert.NoError(t, f.LazyIO(false))
+
+	t.Run("closed", func(t *testing.T) {
+		f2, err := mount.Open(fname, os.O_RDONLY, 0)
+		require.NoError(t, err)
+		require.NoError(t, f2.Close())
+		assert.Error(t, f2.LazyIO(true))
+		assert.Error(t, f2.LazyIOSynchronize(0, 0))
+	})
+}
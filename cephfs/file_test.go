@@ -0,0 +1,463 @@
+package cephfs
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileOpen(t *testing.T) {
+	fname := "TestFileOpen.txt"
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	t.Run("createAndClose", func(t *testing.T) {
+		f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+		assert.NoError(t, err)
+		require.NotNil(t, f)
+		assert.NoError(t, f.Close())
+		defer os.Remove(CephMountTest + fname)
+
+		// closing a second time is harmless
+		assert.NoError(t, f.Close())
+	})
+
+	t.Run("missingFile", func(t *testing.T) {
+		f, err := mount.Open("nonexistent.txt", os.O_RDONLY, 0)
+		assert.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
+
+func TestFileReadWrite(t *testing.T) {
+	fname := "TestFileReadWrite.txt"
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	t.Run("writeThenRead", func(t *testing.T) {
+		f1, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0666)
+		require.NoError(t, err)
+		n, err := f1.Write([]byte("yello world!"))
+		assert.NoError(t, err)
+		assert.Equal(t, 12, n)
+		assert.NoError(t, f1.Close())
+		defer os.Remove(CephMountTest + fname)
+
+		buf := make([]byte, 1024)
+		f2, err := mount.Open(fname, os.O_RDONLY, 0)
+		require.NoError(t, err)
+		n, err = f2.Read(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, 12, n)
+		assert.Equal(t, "yello world!", string(buf[:n]))
+
+		// the file is exhausted
+		n, err = f2.Read(buf)
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, 0, n)
+		assert.NoError(t, f2.Close())
+	})
+
+	t.Run("readWrittenExternally", func(t *testing.T) {
+		err := ioutil.WriteFile(CephMountTest+fname, []byte("hello"), 0666)
+		require.NoError(t, err)
+		defer os.Remove(CephMountTest + fname)
+
+		buf := make([]byte, 16)
+		f, err := mount.Open(fname, os.O_RDONLY, 0)
+		require.NoError(t, err)
+		defer f.Close()
+		n, err := f.Read(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(buf[:n]))
+	})
+
+	t.Run("writeToReadOnly", func(t *testing.T) {
+		f, err := mount.Open(fname, os.O_RDONLY|os.O_CREATE, 0666)
+		require.NoError(t, err)
+		defer os.Remove(CephMountTest + fname)
+		defer f.Close()
+		_, err = f.Write([]byte("nope"))
+		assert.Error(t, err)
+	})
+}
+
+func TestFileReadWriteAt(t *testing.T) {
+	fname := "TestFileReadWriteAt.txt"
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	defer os.Remove(CephMountTest + fname)
+	defer f.Close()
+
+	n, err := f.WriteAt([]byte("hello world"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 11, n)
+	n, err = f.WriteAt([]byte("WORLD"), 6)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	buf := make([]byte, 5)
+	n, err = f.ReadAt(buf, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, "WORLD", string(buf[:n]))
+
+	_, err = f.ReadAt(buf, 64)
+	assert.Equal(t, io.EOF, err)
+
+	// short reads at the end of the file report io.EOF
+	buf = make([]byte, 8)
+	n, err = f.ReadAt(buf, 6)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "WORLD", string(buf[:n]))
+
+	// a large read is not cut short
+	big := make([]byte, 4<<20)
+	for i := range big {
+		big[i] = byte(i)
+	}
+	n, err = f.WriteAt(big, 11)
+	assert.NoError(t, err)
+	assert.Equal(t, len(big), n)
+	out := make([]byte, len(big))
+	n, err = f.ReadAt(out, 11)
+	assert.NoError(t, err)
+	assert.Equal(t, len(big), n)
+	assert.Equal(t, big, out)
+
+	_, err = f.ReadAt(buf, -1)
+	assert.Error(t, err)
+	_, err = f.WriteAt(buf, -1)
+	assert.Error(t, err)
+}
+
+func TestFileSeek(t *testing.T) {
+	fname := "TestFileSeek.txt"
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	defer os.Remove(CephMountTest + fname)
+	defer f.Close()
+
+	_, err = f.Write([]byte("0123456789"))
+	require.NoError(t, err)
+
+	pos, err := f.Seek(2, io.SeekStart)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, pos)
+	pos, err = f.Seek(3, io.SeekCurrent)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, pos)
+
+	buf := make([]byte, 2)
+	_, err = f.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "56", string(buf))
+
+	pos, err = f.Seek(-1, io.SeekEnd)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 9, pos)
+
+	_, err = f.Seek(0, 99)
+	assert.Error(t, err)
+	_, err = f.Seek(-20, io.SeekStart)
+	assert.Error(t, err)
+}
+
+func TestFileIOCopy(t *testing.T) {
+	fname := "TestFileIOCopy.txt"
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	data := strings.Repeat("go-ceph!", 4096)
+	f1, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	defer os.Remove(CephMountTest + fname)
+	n, err := io.Copy(f1, strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.EqualValues(t, len(data), n)
+	assert.NoError(t, f1.Close())
+
+	f2, err := mount.Open(fname, os.O_RDONLY, 0)
+	require.NoError(t, err)
+	defer f2.Close()
+	out, err := ioutil.ReadAll(bufio.NewReader(f2))
+	assert.NoError(t, err)
+	assert.Equal(t, data, string(out))
+}
+
+func TestFileTruncate(t *testing.T) {
+	fname := "TestFileTruncate.txt"
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+
+	_, err = f.Write([]byte("hello world"))
+	require.NoError(t, err)
+
+	assert.NoError(t, f.Truncate(5))
+	buf := make([]byte, 32)
+	n, err := f.ReadAt(buf, 0)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "hello", string(buf[:n]))
+
+	assert.NoError(t, f.Truncate(8))
+	n, err = f.ReadAt(buf, 0)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []byte("hello\x00\x00\x00"), buf[:n])
+}
+
+func TestFileFallocate(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestFileFallocate.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+
+	size := func() uint64 {
+		st, err := mount.Statx(fname, StatxSize, 0)
+		require.NoError(t, err)
+		return st.Size
+	}
+
+	t.Run("extend", func(t *testing.T) {
+		err := f.Fallocate(FallocNoFlag, 0, 1024)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1024, size())
+	})
+
+	t.Run("keepSize", func(t *testing.T) {
+		err := f.Fallocate(FallocFlKeepSize, 1024, 1024)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1024, size())
+	})
+
+	t.Run("punchHole", func(t *testing.T) {
+		data := []byte(strings.Repeat("x", 1024))
+		_, err := f.WriteAt(data, 0)
+		require.NoError(t, err)
+
+		err = f.Fallocate(FallocFlPunchHole|FallocFlKeepSize, 256, 512)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1024, size())
+
+		buf := make([]byte, 1024)
+		_, err = f.ReadAt(buf, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, data[:256], buf[:256])
+		assert.Equal(t, make([]byte, 512), buf[256:768])
+		assert.Equal(t, data[768:], buf[768:])
+	})
+
+	t.Run("punchHoleWithoutKeepSize", func(t *testing.T) {
+		err := f.Fallocate(FallocFlPunchHole, 0, 16)
+		assert.Error(t, err)
+	})
+}
+
+func TestFileFsync(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestFileFsync.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+
+	_, err = f.Write([]byte("sync me"))
+	require.NoError(t, err)
+	assert.NoError(t, f.Fsync(SyncAll))
+
+	_, err = f.Write([]byte(" please"))
+	require.NoError(t, err)
+	assert.NoError(t, f.Fsync(SyncDataOnly))
+	assert.NoError(t, f.Sync())
+
+	// the data is visible to other clients once synced
+	data, err := ioutil.ReadFile(CephMountTest + fname)
+	assert.NoError(t, err)
+	assert.Equal(t, "sync me please", string(data))
+}
+
+func TestFilePreadvPwritev(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestFilePreadvPwritev.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+
+	t.Run("simple", func(t *testing.T) {
+		n, err := f.Pwritev([][]byte{
+			[]byte("one"),
+			[]byte("two"),
+			[]byte("three"),
+		}, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, n)
+
+		b1, b2 := make([]byte, 4), make([]byte, 16)
+		n, err = f.Preadv([][]byte{b1, b2}, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, n)
+		assert.Equal(t, "onet", string(b1))
+		assert.Equal(t, "wothree", string(b2[:7]))
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		n, err := f.Pwritev([][]byte{[]byte("TWO")}, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+
+		b1, b2 := make([]byte, 2), make([]byte, 2)
+		n, err = f.Preadv([][]byte{b1, b2}, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, n)
+		assert.Equal(t, "eT", string(b1))
+		assert.Equal(t, "WO", string(b2))
+	})
+
+	t.Run("emptyBuffers", func(t *testing.T) {
+		n, err := f.Pwritev([][]byte{{}, []byte("x"), {}}, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("eof", func(t *testing.T) {
+		n, err := f.Preadv([][]byte{make([]byte, 8)}, 1024)
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, 0, n)
+	})
+}
+
+func TestFileFlock(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestFileFlock.txt"
+	f1, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f1.Close()
+	f2, err := mount.Open(fname, os.O_RDWR, 0)
+	require.NoError(t, err)
+	defer f2.Close()
+
+	t.Run("exclusive", func(t *testing.T) {
+		err := f1.Flock(LockEX|LockNB, 100)
+		assert.NoError(t, err)
+		// another owner can not take any lock
+		err = f2.Flock(LockEX|LockNB, 200)
+		assert.Error(t, err)
+		err = f2.Flock(LockSH|LockNB, 200)
+		assert.Error(t, err)
+		assert.NoError(t, f1.Flock(LockUN, 100))
+		// now it is available
+		assert.NoError(t, f2.Flock(LockEX|LockNB, 200))
+		assert.NoError(t, f2.Flock(LockUN, 200))
+	})
+
+	t.Run("shared", func(t *testing.T) {
+		assert.NoError(t, f1.Flock(LockSH|LockNB, 100))
+		assert.NoError(t, f2.Flock(LockSH|LockNB, 200))
+		err := f2.Flock(LockEX|LockNB, 300)
+		assert.Error(t, err)
+		assert.NoError(t, f1.Flock(LockUN, 100))
+		assert.NoError(t, f2.Flock(LockUN, 200))
+	})
+
+	t.Run("invalidOperation", func(t *testing.T) {
+		assert.Error(t, f1.Flock(LockNB, 100))
+		assert.Error(t, f1.Flock(LockEX|LockSH, 100))
+		assert.Error(t, f1.Flock(LockOp(1024), 100))
+	})
+}
+
+func TestFileChmodChown(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestFileChmodChown.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+
+	t.Run("chmod", func(t *testing.T) {
+		err := f.Chmod(0600)
+		assert.NoError(t, err)
+
+		st, err := mount.Statx(fname, StatxMode, 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(0600), st.Mode&0777)
+	})
+
+	t.Run("chown", func(t *testing.T) {
+		// dockerfile creates bob user account
+		var bob uint32 = 1010
+		err := f.Chown(bob, bob)
+		assert.NoError(t, err)
+
+		st, err := mount.Statx(fname, StatxUid|StatxGid, 0)
+		require.NoError(t, err)
+		assert.Equal(t, bob, st.Uid)
+		assert.Equal(t, bob, st.Gid)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		f2, err := mount.Open(fname, os.O_RDONLY, 0)
+		require.NoError(t, err)
+		require.NoError(t, f2.Close())
+		assert.Error(t, f2.Chmod(0644))
+		assert.Error(t, f2.Chown(0, 0))
+	})
+}
+
+func TestReadFull(t *testing.T) {
+	data := []byte("0123456789")
+	// read returns at most 3 bytes at a time
+	read := func(buf []byte, offset int64) (int, error) {
+		if offset >= int64(len(data)) {
+			return 0, io.EOF
+		}
+		if len(buf) > 3 {
+			buf = buf[:3]
+		}
+		return copy(buf, data[offset:]), nil
+	}
+
+	buf := make([]byte, 8)
+	n, err := readFull(read, buf, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 8, n)
+	assert.Equal(t, "12345678", string(buf))
+
+	n, err = readFull(read, buf, 5)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, "56789", string(buf[:n]))
+
+	n, err = readFull(read, nil, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
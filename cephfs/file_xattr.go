@@ -0,0 +1,93 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// SetXattr sets an extended attribute on the open file.
+//
+// Implements:
+//  int ceph_fsetxattr(struct ceph_mount_info *cmount, int fd, const char *name,
+//                     const void *value, size_t size, int flags);
+func (f *File) SetXattr(name string, value []byte, flags XattrFlags) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	ret := C.ceph_fsetxattr(
+		f.mount.mount,
+		f.fd,
+		cName,
+		bufPointer(value),
+		C.size_t(len(value)),
+		C.int(flags))
+	return getError(ret)
+}
+
+// GetXattr gets an extended attribute from the open file.
+//
+// Implements:
+//  int ceph_fgetxattr(struct ceph_mount_info *cmount, int fd, const char *name,
+//                     void *value, size_t size);
+func (f *File) GetXattr(name string) ([]byte, error) {
+	if err := f.rlock(); err != nil {
+		return nil, err
+	}
+	defer f.mu.RUnlock()
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	return xattrBuffer(func(buf unsafe.Pointer, size C.size_t) C.int {
+		return C.ceph_fgetxattr(f.mount.mount, f.fd, cName, buf, size)
+	})
+}
+
+// ListXattr returns a slice containing strings for the name of each xattr set
+// on the open file. There is no limit on the number of names returned.
+//
+// Implements:
+//  int ceph_flistxattr(struct ceph_mount_info *cmount, int fd, char *list, size_t size);
+func (f *File) ListXattr() ([]string, error) {
+	if err := f.rlock(); err != nil {
+		return nil, err
+	}
+	defer f.mu.RUnlock()
+
+	buf, err := xattrBuffer(func(buf unsafe.Pointer, size C.size_t) C.int {
+		return C.ceph_flistxattr(f.mount.mount, f.fd, (*C.char)(buf), size)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseXattrList(buf), nil
+}
+
+// RemoveXattr removes the named xattr from the open file.
+//
+// Implements:
+//  int ceph_fremovexattr(struct ceph_mount_info *cmount, int fd, const char *name);
+func (f *File) RemoveXattr(name string) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	ret := C.ceph_fremovexattr(f.mount.mount, f.fd, cName)
+	return getError(ret)
+}
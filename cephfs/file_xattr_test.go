@@ -0,0 +1,72 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileGetSetXattr(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestFileGetSetXattr.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+
+	for _, s := range xattrSamples {
+		t.Run("roundTrip-"+s.name, func(t *testing.T) {
+			err := f.SetXattr(s.name, s.value, XattrDefault)
+			assert.NoError(t, err)
+			b, err := f.GetXattr(s.name)
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.value, b)
+		})
+	}
+
+	// values set through the fd are visible through the path
+	b, err := mount.GetXattr(fname, xattrSamples[0].name)
+	assert.NoError(t, err)
+	assert.EqualValues(t, xattrSamples[0].value, b)
+
+	err = f.SetXattr(xattrSamples[0].name, []byte("x"), XattrCreate)
+	assert.Error(t, err)
+	_, err = f.GetXattr("user.xNotThere")
+	assert.Error(t, err)
+}
+
+func TestFileListRemoveXattr(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestFileListRemoveXattr.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	defer f.Close()
+
+	expected := []string{}
+	for _, s := range xattrSamples[:3] {
+		require.NoError(t, f.SetXattr(s.name, s.value, XattrDefault))
+		expected = append(expected, s.name)
+	}
+
+	names, err := f.ListXattr()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, expected, names)
+
+	// attributes remain accessible on an unlinked but open file
+	require.NoError(t, mount.Unlink(fname))
+
+	err = f.RemoveXattr(xattrSamples[0].name)
+	assert.NoError(t, err)
+	names, err = f.ListXattr()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, expected[1:], names)
+
+	err = f.RemoveXattr(xattrSamples[0].name)
+	assert.Error(t, err)
+}
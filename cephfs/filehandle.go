@@ -0,0 +1,296 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <fcntl.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"io"
+	"sync"
+	"unsafe"
+)
+
+// FileHandle is an open file handle of the libcephfs low-level (ll_*)
+// interface. Some functionality, such as POSIX record locking, is only
+// provided for these handles and not for the file descriptors used by File.
+//
+// Like a File, a FileHandle may be used by multiple goroutines at once and
+// Close waits for the calls in progress, including a blocked SetLockWait,
+// to complete.
+type FileHandle struct {
+	// mu is read locked by calls that use fh and write locked by Close
+	mu    sync.RWMutex
+	mount *MountInfo
+	// inode is only set if the handle holds its own reference to the
+	// inode of the file, as when opened by OpenFileHandle
+	inode *C.struct_Inode
+	fh    *C.struct_Fh
+}
+
+// OpenFileHandle opens the file at the given path, using the credentials of
+// the mount, and returns a low-level file handle. The flags are the same
+// os.O_* flags a local open would take, however the file must already exist.
+//
+// Implements:
+//  int ceph_ll_walk(struct ceph_mount_info *cmount, const char* name, Inode **i,
+//                   struct ceph_statx *stx, unsigned int want, unsigned int flags,
+//                   const UserPerm *perms);
+//  int ceph_ll_open(struct ceph_mount_info *cmount, struct Inode *in, int flags,
+//                   struct Fh **fh, const UserPerm *perms);
+func (mount *MountInfo) OpenFileHandle(path string, flags int) (*FileHandle, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	perms := C.ceph_mount_perms(mount.mount)
+	var (
+		inode *C.struct_Inode
+		stx   C.struct_ceph_statx
+	)
+	ret := C.ceph_ll_walk(mount.mount, cPath, &inode, &stx, 0, 0, perms)
+	if ret != 0 {
+		return nil, getError(ret)
+	}
+
+	var fh *C.struct_Fh
+	ret = C.ceph_ll_open(mount.mount, inode, C.int(flags), &fh, perms)
+	if ret != 0 {
+		C.ceph_ll_put(mount.mount, inode)
+		return nil, getError(ret)
+	}
+	return &FileHandle{mount: mount, inode: inode, fh: fh}, nil
+}
+
+// delegationRecalls maps the C file handles holding a delegation to the
+// function to call when the delegation is recalled. The entry of a handle
+// is removed when the handle is closed, which also returns the delegation.
+var delegationRecalls = struct {
+	sync.Mutex
+	m map[uintptr]func()
+}{m: map[uintptr]func(){}}
+
+// rlock read locks the handle for a call that uses it. If the handle is
+// closed the lock is not taken and an error is returned.
+func (fh *FileHandle) rlock() error {
+	fh.mu.RLock()
+	if fh.fh == nil {
+		fh.mu.RUnlock()
+		return errBadFile
+	}
+	return nil
+}
+
+// Close the file handle, releasing any reference it holds on the file's
+// inode.
+//
+// Implements:
+//  int ceph_ll_close(struct ceph_mount_info *cmount, struct Fh* filehandle);
+//  int ceph_ll_put(struct ceph_mount_info *cmount, struct Inode *in);
+func (fh *FileHandle) Close() error {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+	if fh.fh == nil {
+		// already closed
+		return nil
+	}
+	if err := getError(C.ceph_ll_close(fh.mount.mount, fh.fh)); err != nil {
+		return err
+	}
+	delegationRecalls.Lock()
+	delete(delegationRecalls.m, uintptr(unsafe.Pointer(fh.fh)))
+	delegationRecalls.Unlock()
+	fh.fh = nil
+	if fh.inode != nil {
+		C.ceph_ll_put(fh.mount.mount, fh.inode)
+		fh.inode = nil
+	}
+	return nil
+}
+
+// ReadAt reads data from the file handle starting at the given offset.
+// Up to len(buf) bytes will be read from the file.
+// The number of bytes read will be returned. As required by io.ReaderAt,
+// ReadAt reads until buf is full, and returns io.EOF if the end of the file
+// is reached first.
+func (fh *FileHandle) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, ErrInvalid
+	}
+	return readFull(fh.read, buf, offset)
+}
+
+// read directly wraps the ceph_ll_read call.
+//
+// Implements:
+//  int ceph_ll_read(struct ceph_mount_info *cmount, struct Fh* filehandle, int64_t off,
+//                   uint64_t len, char* buf);
+func (fh *FileHandle) read(buf []byte, offset int64) (int, error) {
+	if err := fh.rlock(); err != nil {
+		return 0, err
+	}
+	defer fh.mu.RUnlock()
+
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	ret := C.ceph_ll_read(
+		fh.mount.mount, fh.fh, C.int64_t(offset), C.uint64_t(len(buf)),
+		(*C.char)(unsafe.Pointer(&buf[0])))
+	switch {
+	case ret < 0:
+		return 0, getError(C.int(ret))
+	case ret == 0:
+		return 0, io.EOF
+	}
+	return int(ret), nil
+}
+
+// WriteAt writes data from buf to the file handle at the specified offset.
+// The number of bytes written is returned.
+//
+// Implements:
+//  int ceph_ll_write(struct ceph_mount_info *cmount, struct Fh* filehandle, int64_t off,
+//                    uint64_t len, const char *data);
+func (fh *FileHandle) WriteAt(buf []byte, offset int64) (int, error) {
+	if err := fh.rlock(); err != nil {
+		return 0, err
+	}
+	defer fh.mu.RUnlock()
+
+	if offset < 0 {
+		return 0, ErrInvalid
+	}
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	ret := C.ceph_ll_write(
+		fh.mount.mount, fh.fh, C.int64_t(offset), C.uint64_t(len(buf)),
+		(*C.char)(unsafe.Pointer(&buf[0])))
+	if ret < 0 {
+		return 0, getError(C.int(ret))
+	}
+	n := int(ret)
+	if n < len(buf) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+// Fsync ensures the data written through the file handle is committed to
+// stable storage. See File.Fsync for the meaning of the sync argument.
+//
+// Implements:
+//  int ceph_ll_fsync(struct ceph_mount_info *cmount, struct Fh *fh, int syncdataonly);
+func (fh *FileHandle) Fsync(sync SyncChoice) error {
+	if err := fh.rlock(); err != nil {
+		return err
+	}
+	defer fh.mu.RUnlock()
+
+	ret := C.ceph_ll_fsync(fh.mount.mount, fh.fh, C.int(sync))
+	return getError(ret)
+}
+
+// LockType indicates the kind of a POSIX record lock.
+type LockType int16
+
+const (
+	// LockTypeRead is a shared (read) lock.
+	LockTypeRead = LockType(C.F_RDLCK)
+	// LockTypeWrite is an exclusive (write) lock.
+	LockTypeWrite = LockType(C.F_WRLCK)
+	// LockTypeUnlock releases a lock, or when returned from GetLock
+	// indicates that no conflicting lock exists.
+	LockTypeUnlock = LockType(C.F_UNLCK)
+)
+
+// RecordLock describes a POSIX (fcntl style) advisory lock on a byte range
+// of a file.
+type RecordLock struct {
+	// Type of the lock.
+	Type LockType
+	// Start is the offset, from the beginning of the file, of the first
+	// byte covered by the lock.
+	Start int64
+	// Len is the number of bytes covered by the lock. Zero means the lock
+	// extends to the end of the file, no matter how large it grows.
+	Len int64
+	// Pid identifies the process holding a conflicting lock. It is only
+	// filled in by GetLock.
+	Pid int32
+}
+
+func (lock *RecordLock) toCStruct() C.struct_flock {
+	var fl C.struct_flock
+	fl.l_type = C.short(lock.Type)
+	fl.l_whence = C.SEEK_SET
+	fl.l_start = C.off_t(lock.Start)
+	fl.l_len = C.off_t(lock.Len)
+	fl.l_pid = C.pid_t(lock.Pid)
+	return fl
+}
+
+// GetLock tests if the given lock could be placed by owner. If the lock
+// could be placed the returned lock has the type LockTypeUnlock, otherwise
+// it describes one of the locks that conflict with the request.
+//
+// Implements:
+//  int ceph_ll_getlk(struct ceph_mount_info *cmount, Fh *fh, struct flock *fl, uint64_t owner);
+func (fh *FileHandle) GetLock(lock RecordLock, owner uint64) (*RecordLock, error) {
+	if err := fh.rlock(); err != nil {
+		return nil, err
+	}
+	defer fh.mu.RUnlock()
+
+	fl := lock.toCStruct()
+	ret := C.ceph_ll_getlk(fh.mount.mount, fh.fh, &fl, C.uint64_t(owner))
+	if err := getError(ret); err != nil {
+		return nil, err
+	}
+	return &RecordLock{
+		Type:  LockType(fl.l_type),
+		Start: int64(fl.l_start),
+		Len:   int64(fl.l_len),
+		Pid:   int32(fl.l_pid),
+	}, nil
+}
+
+func (fh *FileHandle) setLock(lock RecordLock, owner uint64, sleep bool) error {
+	if err := fh.rlock(); err != nil {
+		return err
+	}
+	defer fh.mu.RUnlock()
+
+	var cSleep C.int
+	if sleep {
+		cSleep = 1
+	}
+	fl := lock.toCStruct()
+	ret := C.ceph_ll_setlk(fh.mount.mount, fh.fh, &fl, C.uint64_t(owner), cSleep)
+	return getError(ret)
+}
+
+// SetLock acquires or, when the lock type is LockTypeUnlock, releases the
+// given lock on behalf of owner. If a conflicting lock is held an error is
+// returned immediately.
+//
+// Implements:
+//  int ceph_ll_setlk(struct ceph_mount_info *cmount, Fh *fh, struct flock *fl,
+//                    uint64_t owner, int sleep);
+func (fh *FileHandle) SetLock(lock RecordLock, owner uint64) error {
+	return fh.setLock(lock, owner, false)
+}
+
+// SetLockWait acquires the given lock on behalf of owner, blocking until any
+// conflicting locks have been released.
+//
+// Implements:
+//  int ceph_ll_setlk(struct ceph_mount_info *cmount, Fh *fh, struct flock *fl,
+//                    uint64_t owner, int sleep);
+func (fh *FileHandle) SetLockWait(lock RecordLock, owner uint64) error {
+	return fh.setLock(lock, owner, true)
+}
@@ -0,0 +1,113 @@
+// +build !luminous
+//
+// Ceph Mimic is the first release that includes ceph_ll_delegation().
+
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+
+extern void delegationRecallCallback(Fh *fh, void *priv);
+*/
+import "C"
+
+import (
+	"time"
+	"unsafe"
+)
+
+// DelegationType is the kind of delegation requested with SetDelegation.
+type DelegationType uint
+
+const (
+	// DelegationNone returns the delegation held by the file handle.
+	DelegationNone = DelegationType(C.CEPH_DELEGATION_NONE)
+	// DelegationRead is a read delegation. The file can not be written to
+	// by other clients, or through other file handles, while it is held.
+	DelegationRead = DelegationType(C.CEPH_DELEGATION_RD)
+	// DelegationWrite is a write delegation. The file can not be accessed
+	// by other clients, or through other file handles, while it is held.
+	DelegationWrite = DelegationType(C.CEPH_DELEGATION_WR)
+)
+
+// SetDelegationTimeout sets the time, rounded down to whole seconds, the
+// holders of delegations are given to return a recalled delegation. If a
+// delegation is not returned in time, the client is evicted from the file
+// system. The timeout must be shorter than the MDS session timeout.
+// Delegations can only be acquired after a timeout has been set.
+//
+// Implements:
+//  int ceph_set_deleg_timeout(struct ceph_mount_info *cmount, uint32_t timeout);
+func (mount *MountInfo) SetDelegationTimeout(timeout time.Duration) error {
+	if timeout < time.Second {
+		return ErrInvalid
+	}
+	ret := C.ceph_set_deleg_timeout(mount.mount, C.uint32_t(timeout/time.Second))
+	return getError(ret)
+}
+
+// SetDelegation acquires a delegation of the given type for the file
+// handle, allowing the holder to cache the file's data and attributes
+// without checking with the file system, as an NFS server does for its own
+// clients. A delegation of type DelegationNone returns the delegation held.
+// Acquiring a delegation fails with EAGAIN if a conflicting open of the
+// file exists. See also SetDelegationTimeout.
+//
+// When another access to the file conflicts with the delegation, libcephfs
+// recalls it by calling recall in a new goroutine. The holder must then
+// return the delegation, with SetDelegation(DelegationNone, nil) or by
+// closing the handle, within the delegation timeout of the client, or the
+// client is evicted from the file system.
+//
+// Implements:
+//  int ceph_ll_delegation(struct ceph_mount_info *cmount, Fh *fh, unsigned cmd,
+//                         ceph_deleg_cb_t cb, void *priv);
+func (fh *FileHandle) SetDelegation(deleg DelegationType, recall func()) error {
+	if err := fh.rlock(); err != nil {
+		return err
+	}
+	defer fh.mu.RUnlock()
+
+	if deleg != DelegationNone && recall == nil {
+		return ErrInvalid
+	}
+	key := uintptr(unsafe.Pointer(fh.fh))
+	delegationRecalls.Lock()
+	prev, hadPrev := delegationRecalls.m[key]
+	if deleg != DelegationNone {
+		// the delegation may be recalled before the call returns, so the
+		// recall function must be registered first
+		delegationRecalls.m[key] = recall
+	}
+	delegationRecalls.Unlock()
+
+	ret := C.ceph_ll_delegation(
+		fh.mount.mount, fh.fh, C.uint(deleg),
+		C.ceph_deleg_cb_t(C.delegationRecallCallback), nil)
+
+	delegationRecalls.Lock()
+	switch {
+	case ret != 0 && hadPrev:
+		// a delegation held before is kept
+		delegationRecalls.m[key] = prev
+	case ret != 0 || deleg == DelegationNone:
+		delete(delegationRecalls.m, key)
+	}
+	delegationRecalls.Unlock()
+	return getError(ret)
+}
+
+//export delegationRecallCallback
+func delegationRecallCallback(fh *C.Fh, priv unsafe.Pointer) {
+	delegationRecalls.Lock()
+	recall := delegationRecalls.m[uintptr(unsafe.Pointer(fh))]
+	delegationRecalls.Unlock()
+	if recall != nil {
+		// the callback is made with the client lock held, so recall must
+		// run separately to be able to return the delegation
+		go recall()
+	}
+}
@@ -0,0 +1,66 @@
+// +build !luminous
+
+package cephfs
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelegation(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestDelegation.txt"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	assert.Equal(t, ErrInvalid, mount.SetDelegationTimeout(0))
+	require.NoError(t, mount.SetDelegationTimeout(30*time.Second))
+
+	fh, err := mount.OpenFileHandle(fname, os.O_RDONLY)
+	require.NoError(t, err)
+	defer fh.Close()
+
+	assert.Equal(t, ErrInvalid, fh.SetDelegation(DelegationRead, nil))
+
+	recalled := make(chan struct{})
+	err = fh.SetDelegation(DelegationRead, func() {
+		assert.NoError(t, fh.SetDelegation(DelegationNone, nil))
+		close(recalled)
+	})
+	require.NoError(t, err)
+
+	// a write by another client recalls the delegation
+	mount2 := fsConnect(t)
+	defer mount2.Unmount()
+	written := make(chan error, 1)
+	go func() {
+		f2, err := mount2.Open(fname, os.O_WRONLY, 0)
+		if err == nil {
+			_, err = f2.Write([]byte("recall"))
+			f2.Close()
+		}
+		written <- err
+	}()
+
+	select {
+	case <-recalled:
+	case <-time.After(20 * time.Second):
+		t.Fatal("delegation not recalled")
+	}
+	assert.NoError(t, <-written)
+
+	t.Run("closed", func(t *testing.T) {
+		fh2, err := mount.OpenFileHandle(fname, os.O_RDONLY)
+		require.NoError(t, err)
+		require.NoError(t, fh2.Close())
+		assert.Equal(t, errBadFile, fh2.SetDelegation(DelegationNone, nil))
+	})
+}
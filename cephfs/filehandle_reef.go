@@ -0,0 +1,163 @@
+// +build !luminous,!mimic,!nautilus,!octopus,!pacific,!quincy
+//
+// Ceph Reef is the first release that includes
+// ceph_ll_nonblocking_readv_writev().
+
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+
+extern void nonblockingIOCallback(struct ceph_ll_io_info *info);
+*/
+import "C"
+
+import (
+	"io"
+	"sync"
+	"unsafe"
+)
+
+// IOResult reports the outcome of a nonblocking read or write.
+type IOResult struct {
+	// N is the number of bytes read or written.
+	N int
+	// Err is set if the I/O failed. A read starting at or beyond the end
+	// of the file fails with io.EOF.
+	Err error
+}
+
+// pendingIO tracks a nonblocking I/O submitted to libcephfs until its
+// completion callback is called.
+type pendingIO struct {
+	fh     *FileHandle
+	iov    *iovec
+	write  bool
+	result chan IOResult
+}
+
+// pendingIOs maps the address of the C io info struct of each I/O in
+// flight to the state needed to complete it. The C struct can not refer to
+// Go memory, so the callback uses its address to find the Go side.
+var pendingIOs = struct {
+	sync.Mutex
+	m map[uintptr]*pendingIO
+}{m: map[uintptr]*pendingIO{}}
+
+// PreadvNonblocking starts reading data from the file handle, starting at
+// the given offset, into the byte-slice buffers sequentially. It returns
+// without waiting for the read to complete. The outcome of the read is
+// delivered on the returned channel, after the data has been copied into
+// the buffers. The buffers must not be used until then.
+//
+// Any number of nonblocking reads and writes may be in flight at once
+// without tying up a goroutine, or an OS thread, for each of them. The
+// file handle can not be closed until all of them have completed.
+//
+// Implements:
+//  int64_t ceph_ll_nonblocking_readv_writev(struct ceph_mount_info *cmount,
+//                                           struct ceph_ll_io_info *io_info);
+func (fh *FileHandle) PreadvNonblocking(data [][]byte, offset int64) (<-chan IOResult, error) {
+	return fh.nonblockingIO(data, offset, false)
+}
+
+// PwritevNonblocking starts writing data from the slice of byte-slice
+// buffers to the file handle at the specified offset. It returns without
+// waiting for the write to complete. The data is copied before
+// PwritevNonblocking returns, so the buffers may be reused right away. The
+// outcome of the write is delivered on the returned channel.
+//
+// See PreadvNonblocking for details on nonblocking I/O.
+//
+// Implements:
+//  int64_t ceph_ll_nonblocking_readv_writev(struct ceph_mount_info *cmount,
+//                                           struct ceph_ll_io_info *io_info);
+func (fh *FileHandle) PwritevNonblocking(data [][]byte, offset int64) (<-chan IOResult, error) {
+	return fh.nonblockingIO(data, offset, true)
+}
+
+func (fh *FileHandle) nonblockingIO(data [][]byte, offset int64, write bool) (<-chan IOResult, error) {
+	if err := fh.rlock(); err != nil {
+		return nil, err
+	}
+	// from here on the read lock is held until the I/O completes
+
+	if offset < 0 {
+		fh.mu.RUnlock()
+		return nil, ErrInvalid
+	}
+	result := make(chan IOResult, 1)
+	size := 0
+	for _, buf := range data {
+		size += len(buf)
+	}
+	if size == 0 {
+		fh.mu.RUnlock()
+		result <- IOResult{}
+		return result, nil
+	}
+
+	info := (*C.struct_ceph_ll_io_info)(
+		C.calloc(1, C.size_t(unsafe.Sizeof(C.struct_ceph_ll_io_info{}))))
+	iov := newIovec(data, write)
+	info.callback = (*[0]byte)(C.nonblockingIOCallback)
+	info.fh = fh.fh
+	info.iov = iov.pointer()
+	info.iovcnt = iov.length()
+	info.off = C.int64_t(offset)
+	info.write = C.bool(write)
+
+	// the I/O may complete before the call returns, so it must be
+	// registered first
+	key := uintptr(unsafe.Pointer(info))
+	pendingIOs.Lock()
+	pendingIOs.m[key] = &pendingIO{fh: fh, iov: iov, write: write, result: result}
+	pendingIOs.Unlock()
+
+	ret := C.ceph_ll_nonblocking_readv_writev(fh.mount.mount, info)
+	if ret < 0 {
+		// the I/O was not started and the callback will not be called
+		pendingIOs.Lock()
+		delete(pendingIOs.m, key)
+		pendingIOs.Unlock()
+		iov.free()
+		C.free(unsafe.Pointer(info))
+		fh.mu.RUnlock()
+		return nil, getError(C.int(ret))
+	}
+	return result, nil
+}
+
+//export nonblockingIOCallback
+func nonblockingIOCallback(info *C.struct_ceph_ll_io_info) {
+	key := uintptr(unsafe.Pointer(info))
+	pendingIOs.Lock()
+	p := pendingIOs.m[key]
+	delete(pendingIOs.m, key)
+	pendingIOs.Unlock()
+	if p == nil {
+		return
+	}
+
+	var res IOResult
+	ret := int64(info.result)
+	switch {
+	case ret < 0:
+		res.Err = getError(C.int(ret))
+	case ret == 0 && !p.write:
+		res.Err = io.EOF
+	default:
+		res.N = int(ret)
+		if !p.write {
+			p.iov.copyOut(res.N)
+		}
+	}
+	p.iov.free()
+	C.free(unsafe.Pointer(info))
+	p.fh.mu.RUnlock()
+	// the channel is buffered so the libcephfs thread never blocks here
+	p.result <- res
+}
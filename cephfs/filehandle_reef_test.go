@@ -0,0 +1,94 @@
+// +build !luminous,!mimic,!nautilus,!octopus,!pacific,!quincy
+
+package cephfs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonblockingIO(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestNonblockingIO.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	fh, err := mount.OpenFileHandle(fname, os.O_RDWR)
+	require.NoError(t, err)
+	defer fh.Close()
+
+	const count, size = 16, 4096
+	t.Run("writeMany", func(t *testing.T) {
+		results := make([]<-chan IOResult, count)
+		for i := range results {
+			buf := bytes.Repeat([]byte{byte('a' + i)}, size)
+			results[i], err = fh.PwritevNonblocking([][]byte{buf}, int64(i*size))
+			require.NoError(t, err)
+		}
+		for _, ch := range results {
+			res := <-ch
+			assert.NoError(t, res.Err)
+			assert.Equal(t, size, res.N)
+		}
+	})
+
+	t.Run("readMany", func(t *testing.T) {
+		bufs := make([][]byte, count)
+		results := make([]<-chan IOResult, count)
+		for i := range results {
+			bufs[i] = make([]byte, size)
+			results[i], err = fh.PreadvNonblocking(
+				[][]byte{bufs[i][:size/2], bufs[i][size/2:]}, int64(i*size))
+			require.NoError(t, err)
+		}
+		for i, ch := range results {
+			res := <-ch
+			assert.NoError(t, res.Err)
+			assert.Equal(t, size, res.N)
+			assert.Equal(t, bytes.Repeat([]byte{byte('a' + i)}, size), bufs[i])
+		}
+	})
+
+	t.Run("readEOF", func(t *testing.T) {
+		ch, err := fh.PreadvNonblocking([][]byte{make([]byte, 8)}, count*size)
+		require.NoError(t, err)
+		res := <-ch
+		assert.Equal(t, io.EOF, res.Err)
+		assert.Equal(t, 0, res.N)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		ch, err := fh.PwritevNonblocking(nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, IOResult{}, <-ch)
+	})
+
+	t.Run("invalidOffset", func(t *testing.T) {
+		_, err := fh.PreadvNonblocking([][]byte{make([]byte, 8)}, -1)
+		assert.Equal(t, ErrInvalid, err)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		fh2, err := mount.OpenFileHandle(fname, os.O_RDONLY)
+		require.NoError(t, err)
+		ch, err := fh2.PreadvNonblocking([][]byte{make([]byte, 8)}, 0)
+		require.NoError(t, err)
+		// close waits for the read in flight
+		assert.NoError(t, fh2.Close())
+		res := <-ch
+		assert.NoError(t, res.Err)
+		assert.Equal(t, 8, res.N)
+
+		_, err = fh2.PreadvNonblocking([][]byte{make([]byte, 8)}, 0)
+		assert.Equal(t, errBadFile, err)
+	})
+}
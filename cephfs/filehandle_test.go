@@ -0,0 +1,113 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenFileHandle(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestOpenFileHandle.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	fh, err := mount.OpenFileHandle(fname, os.O_RDWR)
+	assert.NoError(t, err)
+	require.NotNil(t, fh)
+	assert.NoError(t, fh.Close())
+	// closing a second time is harmless
+	assert.NoError(t, fh.Close())
+
+	_, err = mount.OpenFileHandle("TestOpenFileHandle.missing", os.O_RDONLY)
+	assert.Error(t, err)
+}
+
+func TestRecordLocks(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestRecordLocks.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	fh1, err := mount.OpenFileHandle(fname, os.O_RDWR)
+	require.NoError(t, err)
+	defer fh1.Close()
+	fh2, err := mount.OpenFileHandle(fname, os.O_RDWR)
+	require.NoError(t, err)
+	defer fh2.Close()
+
+	const owner1, owner2 = 1001, 1002
+	wlock := RecordLock{Type: LockTypeWrite, Start: 0, Len: 100}
+	unlock := RecordLock{Type: LockTypeUnlock, Start: 0, Len: 100}
+
+	t.Run("noConflict", func(t *testing.T) {
+		l, err := fh1.GetLock(wlock, owner1)
+		assert.NoError(t, err)
+		require.NotNil(t, l)
+		assert.Equal(t, LockTypeUnlock, l.Type)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		require.NoError(t, fh1.SetLock(wlock, owner1))
+
+		l, err := fh2.GetLock(RecordLock{Type: LockTypeRead, Start: 50, Len: 10}, owner2)
+		assert.NoError(t, err)
+		require.NotNil(t, l)
+		assert.Equal(t, LockTypeWrite, l.Type)
+		assert.EqualValues(t, 0, l.Start)
+		assert.EqualValues(t, 100, l.Len)
+
+		err = fh2.SetLock(wlock, owner2)
+		assert.Error(t, err)
+
+		// a non-overlapping range is available
+		err = fh2.SetLock(RecordLock{Type: LockTypeWrite, Start: 100, Len: 10}, owner2)
+		assert.NoError(t, err)
+		assert.NoError(t, fh2.SetLock(RecordLock{Type: LockTypeUnlock, Start: 100, Len: 10}, owner2))
+
+		assert.NoError(t, fh1.SetLock(unlock, owner1))
+	})
+
+	t.Run("sharedReaders", func(t *testing.T) {
+		rlock := RecordLock{Type: LockTypeRead, Start: 0, Len: 0}
+		assert.NoError(t, fh1.SetLock(rlock, owner1))
+		assert.NoError(t, fh2.SetLock(rlock, owner2))
+		assert.NoError(t, fh1.SetLock(RecordLock{Type: LockTypeUnlock}, owner1))
+		assert.NoError(t, fh2.SetLock(RecordLock{Type: LockTypeUnlock}, owner2))
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		require.NoError(t, fh1.SetLock(wlock, owner1))
+
+		ch := make(chan error)
+		go func() {
+			ch <- fh2.SetLockWait(wlock, owner2)
+		}()
+
+		select {
+		case <-ch:
+			t.Fatalf("lock acquired while still held")
+		case <-time.After(500 * time.Millisecond):
+		}
+
+		assert.NoError(t, fh1.SetLock(unlock, owner1))
+		select {
+		case err := <-ch:
+			assert.NoError(t, err)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for lock")
+		}
+		assert.NoError(t, fh2.SetLock(unlock, owner2))
+	})
+}
@@ -0,0 +1,97 @@
+package cephfs
+
+import (
+	"os"
+	"path"
+	"syscall"
+	"time"
+)
+
+// fileInfo adapts the results of a statx call to the os.FileInfo interface.
+type fileInfo struct {
+	name  string
+	statx *CephStatx
+}
+
+var _ os.FileInfo = &fileInfo{}
+
+// Name returns the base name of the file.
+func (fi *fileInfo) Name() string {
+	return fi.name
+}
+
+// Size returns the length of the file in bytes.
+func (fi *fileInfo) Size() int64 {
+	return int64(fi.statx.Size)
+}
+
+// Mode returns the file's mode and permission bits.
+func (fi *fileInfo) Mode() os.FileMode {
+	return toFileMode(fi.statx.Mode)
+}
+
+// ModTime returns the modification time of the file.
+func (fi *fileInfo) ModTime() time.Time {
+	return time.Unix(fi.statx.Mtime.Sec, fi.statx.Mtime.Nsec)
+}
+
+// IsDir reports if the file is a directory.
+func (fi *fileInfo) IsDir() bool {
+	return fi.Mode().IsDir()
+}
+
+// Sys returns the underlying *CephStatx.
+func (fi *fileInfo) Sys() interface{} {
+	return fi.statx
+}
+
+// toFileMode converts a unix mode, as returned by ceph, to an os.FileMode.
+func toFileMode(mode uint16) os.FileMode {
+	fm := os.FileMode(mode & 0777)
+	switch uint32(mode) & syscall.S_IFMT {
+	case syscall.S_IFBLK:
+		fm |= os.ModeDevice
+	case syscall.S_IFCHR:
+		fm |= os.ModeDevice | os.ModeCharDevice
+	case syscall.S_IFDIR:
+		fm |= os.ModeDir
+	case syscall.S_IFIFO:
+		fm |= os.ModeNamedPipe
+	case syscall.S_IFLNK:
+		fm |= os.ModeSymlink
+	case syscall.S_IFSOCK:
+		fm |= os.ModeSocket
+	}
+	if mode&syscall.S_ISGID != 0 {
+		fm |= os.ModeSetgid
+	}
+	if mode&syscall.S_ISUID != 0 {
+		fm |= os.ModeSetuid
+	}
+	if mode&syscall.S_ISVTX != 0 {
+		fm |= os.ModeSticky
+	}
+	return fm
+}
+
+func (mount *MountInfo) stat(name string, flags AtFlags) (os.FileInfo, error) {
+	stx, err := mount.Statx(name, StatxBasicStats, flags)
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo{name: path.Base(name), statx: stx}, nil
+}
+
+// Stat returns an os.FileInfo describing the named file. If the file is a
+// symbolic link the returned information describes the link's target.
+// The Sys method of the result returns a *CephStatx.
+func (mount *MountInfo) Stat(name string) (os.FileInfo, error) {
+	return mount.stat(name, 0)
+}
+
+// Lstat returns an os.FileInfo describing the named file. If the file is a
+// symbolic link the returned information describes the link itself.
+// The Sys method of the result returns a *CephStatx.
+func (mount *MountInfo) Lstat(name string) (os.FileInfo, error) {
+	return mount.stat(name, AtSymlinkNofollow)
+}
@@ -0,0 +1,68 @@
+package cephfs
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToFileMode(t *testing.T) {
+	assert.Equal(t, os.FileMode(0644), toFileMode(syscall.S_IFREG|0644))
+	assert.Equal(t, os.ModeDir|0755, toFileMode(syscall.S_IFDIR|0755))
+	assert.Equal(t, os.ModeSymlink|0777, toFileMode(syscall.S_IFLNK|0777))
+	assert.Equal(t, os.ModeNamedPipe|0600, toFileMode(syscall.S_IFIFO|0600))
+	assert.Equal(t, os.ModeSocket|0600, toFileMode(syscall.S_IFSOCK|0600))
+	assert.Equal(t, os.ModeDevice|0600, toFileMode(syscall.S_IFBLK|0600))
+	assert.Equal(t,
+		os.ModeDevice|os.ModeCharDevice|0600,
+		toFileMode(syscall.S_IFCHR|0600))
+	assert.Equal(t,
+		os.ModeDir|os.ModeSticky|0777,
+		toFileMode(syscall.S_IFDIR|syscall.S_ISVTX|0777))
+	assert.Equal(t,
+		os.ModeSetuid|os.ModeSetgid|0755,
+		toFileMode(syscall.S_IFREG|syscall.S_ISUID|syscall.S_ISGID|0755))
+}
+
+func TestStatLstat(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "fileinfo.txt"
+	lname := "fileinfo.link"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("some data"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer os.Remove(CephMountTest + fname)
+	require.NoError(t, os.Symlink(fname, CephMountTest+lname))
+	defer os.Remove(CephMountTest + lname)
+
+	fi, err := mount.Stat(lname)
+	assert.NoError(t, err)
+	require.NotNil(t, fi)
+	assert.Equal(t, lname, fi.Name())
+	assert.EqualValues(t, 9, fi.Size())
+	assert.Equal(t, os.FileMode(0600), fi.Mode())
+	assert.False(t, fi.IsDir())
+	assert.WithinDuration(t, time.Now(), fi.ModTime(), time.Minute)
+	assert.IsType(t, &CephStatx{}, fi.Sys())
+
+	fi, err = mount.Lstat(lname)
+	assert.NoError(t, err)
+	require.NotNil(t, fi)
+	assert.True(t, fi.Mode()&os.ModeSymlink != 0)
+
+	fi, err = mount.Stat("/")
+	assert.NoError(t, err)
+	require.NotNil(t, fi)
+	assert.True(t, fi.IsDir())
+
+	_, err = mount.Stat("fileinfo.missing")
+	assert.Error(t, err)
+}
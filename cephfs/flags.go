@@ -0,0 +1,41 @@
+package cephfs
+
+/*
+#cgo CPPFLAGS: -D_GNU_SOURCE
+#include <fcntl.h>
+*/
+import "C"
+
+// Flags for Open, named like those of the os package. libcephfs interprets
+// open flags using the Linux values, which these constants always have,
+// so they should be preferred to the os constants when portability
+// matters. Flags that the os package does not provide, such as O_DIRECT,
+// are also included.
+const (
+	// O_RDONLY opens the file read-only.
+	O_RDONLY = int(C.O_RDONLY)
+	// O_WRONLY opens the file write-only.
+	O_WRONLY = int(C.O_WRONLY)
+	// O_RDWR opens the file read-write.
+	O_RDWR = int(C.O_RDWR)
+	// O_APPEND appends data to the file when writing.
+	O_APPEND = int(C.O_APPEND)
+	// O_CREATE creates a new file if none exists.
+	O_CREATE = int(C.O_CREAT)
+	// O_EXCL is used with O_CREATE, the file must not exist.
+	O_EXCL = int(C.O_EXCL)
+	// O_TRUNC truncates a regular writable file when it is opened.
+	O_TRUNC = int(C.O_TRUNC)
+	// O_SYNC makes each write wait until the data and metadata are
+	// stable on the OSDs.
+	O_SYNC = int(C.O_SYNC)
+	// O_DSYNC makes each write wait until the data is stable on the
+	// OSDs.
+	O_DSYNC = int(C.O_DSYNC)
+	// O_DIRECT bypasses the client's data cache for reads and writes.
+	O_DIRECT = int(C.O_DIRECT)
+	// O_NOFOLLOW fails the open if the path is a symbolic link.
+	O_NOFOLLOW = int(C.O_NOFOLLOW)
+	// O_DIRECTORY fails the open if the path is not a directory.
+	O_DIRECTORY = int(C.O_DIRECTORY)
+)
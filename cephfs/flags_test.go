@@ -0,0 +1,59 @@
+package cephfs
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenFlagValues(t *testing.T) {
+	// on linux the os package flags have the same values
+	assert.Equal(t, os.O_RDONLY, O_RDONLY)
+	assert.Equal(t, os.O_WRONLY, O_WRONLY)
+	assert.Equal(t, os.O_RDWR, O_RDWR)
+	assert.Equal(t, os.O_CREATE, O_CREATE)
+	assert.Equal(t, os.O_EXCL, O_EXCL)
+	assert.Equal(t, os.O_SYNC, O_SYNC)
+	assert.NotEqual(t, 0, O_DIRECT)
+}
+
+func TestOpenFlags(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestOpenFlags.txt"
+	f, err := mount.Open(fname, O_WRONLY|O_CREATE|O_EXCL|O_SYNC, 0644)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, mount.Unlink(fname)) }()
+	_, err = f.Write([]byte("synchronous"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	_, err = mount.Open(fname, O_WRONLY|O_CREATE|O_EXCL, 0644)
+	assert.Equal(t, ErrExist, err)
+
+	f, err = mount.Open(fname, O_RDWR|O_DIRECT, 0)
+	require.NoError(t, err)
+	_, err = f.WriteAt([]byte("direct"), 0)
+	assert.NoError(t, err)
+	buf := make([]byte, 32)
+	n, err := f.ReadAt(buf, 0)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "directonous", string(buf[:n]))
+	assert.NoError(t, f.Close())
+
+	f, err = mount.Open(fname, O_WRONLY|O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("!"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	st, err := mount.Statx(fname, StatxSize, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 12, st.Size)
+
+	_, err = mount.Open(fname, O_RDONLY|O_DIRECTORY, 0)
+	assert.Equal(t, ErrNotDir, err)
+}
@@ -0,0 +1,92 @@
+// +build !luminous
+//
+// Ceph Mimic is the first release that includes ceph_get_fs_cid().
+
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// fsCid returns the id of the file system mounted by the mount.
+//
+// Implements:
+//  int64_t ceph_get_fs_cid(struct ceph_mount_info *cmount);
+func (mount *MountInfo) fsCid() (int64, error) {
+	ret := C.ceph_get_fs_cid(mount.mount)
+	if ret < 0 {
+		return 0, getError(C.int(ret))
+	}
+	return int64(ret), nil
+}
+
+// MDSRankStatus describes the MDS daemon holding a rank of a file system.
+type MDSRankStatus struct {
+	Rank int64 `json:"whoami"`
+	// GID is the global id of the daemon.
+	GID int64 `json:"id"`
+	// State of the daemon, for example "up:active".
+	State string `json:"state"`
+}
+
+// FSStatus reports the state of a mounted file system.
+type FSStatus struct {
+	// FSCID is the id of the file system.
+	FSCID int64
+	// Ranks lists the MDS daemons holding ranks, ordered by rank.
+	Ranks []MDSRankStatus
+	// Usage reports the space and inodes used by the file system.
+	Usage *CephStatVFS
+}
+
+var mdsStatusCmd = []byte(`{"prefix": "status"}`)
+
+// FSStatus returns the state of the file system mounted by the mount, as
+// far as the client can tell without a separate cluster connection: the id
+// of the file system, the MDS daemons holding its ranks, as reported by
+// their "status" command, and its usage. The ranks are queried in order,
+// the list ends with the last rank before one that is not held by an MDS.
+// The admin package provides a complete status of any file system of the
+// cluster.
+func (mount *MountInfo) FSStatus() (*FSStatus, error) {
+	fscid, err := mount.fsCid()
+	if err != nil {
+		return nil, err
+	}
+	usage, err := mount.StatFS("/")
+	if err != nil {
+		return nil, err
+	}
+	status := &FSStatus{
+		FSCID: fscid,
+		Ranks: []MDSRankStatus{},
+		Usage: usage,
+	}
+	for rank := 0; ; rank++ {
+		// a role of the form <fscid>:<rank> is not resolved against the
+		// default file system of the cluster
+		spec := fmt.Sprintf("%d:%d", fscid, rank)
+		buf, info, err := mount.MdsCommand(spec, [][]byte{mdsStatusCmd})
+		if err == ErrNotExist {
+			break
+		} else if err != nil && info != "" {
+			return nil, fmt.Errorf("%v: %s", err, info)
+		} else if err != nil {
+			return nil, err
+		}
+		var rs MDSRankStatus
+		if err := json.Unmarshal(buf, &rs); err != nil {
+			return nil, err
+		}
+		status.Ranks = append(status.Ranks, rs)
+	}
+	return status, nil
+}
@@ -0,0 +1,26 @@
+// +build !luminous
+
+package cephfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSStatus(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	status, err := mount.FSStatus()
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	assert.True(t, status.FSCID >= 0)
+	require.NotEmpty(t, status.Ranks)
+	assert.EqualValues(t, 0, status.Ranks[0].Rank)
+	assert.NotZero(t, status.Ranks[0].GID)
+	assert.Equal(t, "up:active", status.Ranks[0].State)
+	require.NotNil(t, status.Usage)
+	assert.NotZero(t, status.Usage.Blocks)
+}
@@ -0,0 +1,240 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Inode is a reference, held by the client, to an inode of the file system.
+// Inodes are used by the low-level (ll_*) interface of libcephfs, which
+// operates on inodes rather than paths and so avoids resolving the same
+// path over and over again. The reference must be released with Release
+// when it is no longer needed.
+type Inode struct {
+	mount *MountInfo
+	inode *C.struct_Inode
+}
+
+// permsOrDefault returns the C credentials of perm, or those of the mount
+// if perm is nil.
+func (mount *MountInfo) permsOrDefault(perm *UserPerm) *C.UserPerm {
+	if perm == nil {
+		return C.ceph_mount_perms(mount.mount)
+	}
+	return perm.userPerm
+}
+
+// LookupRoot returns the Inode of the root directory of the mount.
+//
+// Implements:
+//  int ceph_ll_lookup_root(struct ceph_mount_info *cmount, Inode **parent);
+func (mount *MountInfo) LookupRoot() (*Inode, error) {
+	var inode *C.struct_Inode
+	ret := C.ceph_ll_lookup_root(mount.mount, &inode)
+	if ret != 0 {
+		return nil, getError(ret)
+	}
+	return &Inode{mount: mount, inode: inode}, nil
+}
+
+// validate returns an error if the reference to the inode was released.
+func (in *Inode) validate() error {
+	if in.inode == nil {
+		return errBadFile
+	}
+	return nil
+}
+
+// Release drops the reference to the inode.
+//
+// Implements:
+//  int ceph_ll_put(struct ceph_mount_info *cmount, struct Inode *in);
+func (in *Inode) Release() error {
+	if in.inode == nil {
+		// already released
+		return nil
+	}
+	if err := getError(C.ceph_ll_put(in.mount.mount, in.inode)); err != nil {
+		return err
+	}
+	in.inode = nil
+	return nil
+}
+
+// Lookup returns the Inode of the entry with the given name within the
+// directory inode. The perm argument gives the credentials used for the
+// call; if it is nil those of the mount are used.
+//
+// Implements:
+//  int ceph_ll_lookup(struct ceph_mount_info *cmount, Inode *parent, const char *name,
+//                     Inode **out, struct ceph_statx *stx, unsigned want, unsigned flags,
+//                     const UserPerm *perms);
+func (in *Inode) Lookup(name string, perm *UserPerm) (*Inode, error) {
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	var (
+		out *C.struct_Inode
+		stx C.struct_ceph_statx
+	)
+	ret := C.ceph_ll_lookup(
+		in.mount.mount, in.inode, cName, &out, &stx, 0, 0,
+		in.mount.permsOrDefault(perm))
+	if ret != 0 {
+		return nil, getError(ret)
+	}
+	return &Inode{mount: in.mount, inode: out}, nil
+}
+
+// GetAttr returns the stat information of the inode. The want and flags
+// arguments behave as they do for Statx. The perm argument gives the
+// credentials used for the call; if it is nil those of the mount are used.
+//
+// Implements:
+//  int ceph_ll_getattr(struct ceph_mount_info *cmount, struct Inode *in, struct ceph_statx *stx,
+//                      unsigned int want, unsigned int flags, const UserPerm *perms);
+func (in *Inode) GetAttr(want StatxMask, flags AtFlags, perm *UserPerm) (*CephStatx, error) {
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+	var stx C.struct_ceph_statx
+	ret := C.ceph_ll_getattr(
+		in.mount.mount, in.inode, &stx, C.uint(want), C.uint(flags),
+		in.mount.permsOrDefault(perm))
+	if ret != 0 {
+		return nil, getError(ret)
+	}
+	return cStructToCephStatx(stx), nil
+}
+
+// Open the file inode, returning a low-level file handle. The flags are the
+// same os.O_* flags a local open would take. The perm argument gives the
+// credentials used for the call; if it is nil those of the mount are used.
+//
+// Implements:
+//  int ceph_ll_open(struct ceph_mount_info *cmount, struct Inode *in, int flags,
+//                   struct Fh **fh, const UserPerm *perms);
+func (in *Inode) Open(flags int, perm *UserPerm) (*FileHandle, error) {
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+	var fh *C.struct_Fh
+	ret := C.ceph_ll_open(
+		in.mount.mount, in.inode, C.int(flags), &fh,
+		in.mount.permsOrDefault(perm))
+	if ret != 0 {
+		return nil, getError(ret)
+	}
+	return &FileHandle{mount: in.mount, fh: fh}, nil
+}
+
+// Create a new file with the given name within the directory inode and
+// open it. The Inode of the new file and an open file handle are returned.
+// The flags are the same os.O_* flags a local open would take and the mode
+// is applied to the new file. The perm argument gives the credentials used
+// for the call; if it is nil those of the mount are used.
+//
+// Implements:
+//  int ceph_ll_create(struct ceph_mount_info *cmount, Inode *parent, const char *name,
+//                     mode_t mode, int oflags, Inode **outp, Fh **fhp,
+//                     struct ceph_statx *stx, unsigned want, unsigned lflags,
+//                     const UserPerm *perms);
+func (in *Inode) Create(name string, flags int, mode uint32, perm *UserPerm) (*Inode, *FileHandle, error) {
+	if err := in.validate(); err != nil {
+		return nil, nil, err
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	var (
+		out *C.struct_Inode
+		fh  *C.struct_Fh
+		stx C.struct_ceph_statx
+	)
+	ret := C.ceph_ll_create(
+		in.mount.mount, in.inode, cName, C.mode_t(mode), C.int(flags),
+		&out, &fh, &stx, 0, 0, in.mount.permsOrDefault(perm))
+	if ret != 0 {
+		return nil, nil, getError(ret)
+	}
+	return &Inode{mount: in.mount, inode: out},
+		&FileHandle{mount: in.mount, fh: fh},
+		nil
+}
+
+// MakeDir creates a new directory with the given name within the directory
+// inode and returns the Inode of the new directory. The perm argument gives
+// the credentials used for the call; if it is nil those of the mount are
+// used.
+//
+// Implements:
+//  int ceph_ll_mkdir(struct ceph_mount_info *cmount, Inode *parent, const char *name,
+//                    mode_t mode, Inode **out, struct ceph_statx *stx, unsigned want,
+//                    unsigned flags, const UserPerm *perms);
+func (in *Inode) MakeDir(name string, mode uint32, perm *UserPerm) (*Inode, error) {
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	var (
+		out *C.struct_Inode
+		stx C.struct_ceph_statx
+	)
+	ret := C.ceph_ll_mkdir(
+		in.mount.mount, in.inode, cName, C.mode_t(mode), &out, &stx, 0, 0,
+		in.mount.permsOrDefault(perm))
+	if ret != 0 {
+		return nil, getError(ret)
+	}
+	return &Inode{mount: in.mount, inode: out}, nil
+}
+
+// RemoveDir removes the empty directory with the given name from the
+// directory inode. The perm argument gives the credentials used for the
+// call; if it is nil those of the mount are used.
+//
+// Implements:
+//  int ceph_ll_rmdir(struct ceph_mount_info *cmount, struct Inode *in, const char *name,
+//                    const UserPerm *perms);
+func (in *Inode) RemoveDir(name string, perm *UserPerm) error {
+	if err := in.validate(); err != nil {
+		return err
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	ret := C.ceph_ll_rmdir(
+		in.mount.mount, in.inode, cName, in.mount.permsOrDefault(perm))
+	return getError(ret)
+}
+
+// Unlink removes the file with the given name from the directory inode.
+// The perm argument gives the credentials used for the call; if it is nil
+// those of the mount are used.
+//
+// Implements:
+//  int ceph_ll_unlink(struct ceph_mount_info *cmount, struct Inode *in, const char *name,
+//                     const UserPerm *perms);
+func (in *Inode) Unlink(name string, perm *UserPerm) error {
+	if err := in.validate(); err != nil {
+		return err
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	ret := C.ceph_ll_unlink(
+		in.mount.mount, in.inode, cName, in.mount.permsOrDefault(perm))
+	return getError(ret)
+}
@@ -0,0 +1,32 @@
+// +build !luminous,!mimic
+//
+// ceph_ll_lookup_inode() is only used with Ceph Nautilus and newer.
+
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+// LookupInode returns the Inode with the given inode number. The inode
+// does not need to be in the client's cache, making it possible to
+// reopen a file from an inode number saved earlier, even by a different
+// client or after a restart.
+//
+// Implements:
+//  int ceph_ll_lookup_inode(struct ceph_mount_info *cmount, struct inodeno_t ino, Inode **inode);
+func (mount *MountInfo) LookupInode(ino uint64) (*Inode, error) {
+	var (
+		inode *C.struct_Inode
+		cIno  C.struct_inodeno_t
+	)
+	cIno.val = C.uint64_t(ino)
+	ret := C.ceph_ll_lookup_inode(mount.mount, cIno, &inode)
+	if ret != 0 {
+		return nil, getError(ret)
+	}
+	return &Inode{mount: mount, inode: inode}, nil
+}
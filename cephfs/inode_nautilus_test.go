@@ -0,0 +1,64 @@
+// +build !luminous,!mimic
+
+package cephfs
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupInode(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestLookupInode.txt"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	st, err := mount.Statx(fname, StatxIno, 0)
+	require.NoError(t, err)
+
+	t.Run("sameMount", func(t *testing.T) {
+		in, err := mount.LookupInode(st.Inode)
+		assert.NoError(t, err)
+		require.NotNil(t, in)
+		defer in.Release()
+
+		ist, err := in.GetAttr(StatxIno|StatxSize, 0, nil)
+		require.NoError(t, err)
+		assert.Equal(t, st.Inode, ist.Inode)
+		assert.EqualValues(t, 5, ist.Size)
+	})
+
+	t.Run("otherMount", func(t *testing.T) {
+		// a fresh client does not have the inode cached
+		mount2 := fsConnect(t)
+		defer mount2.Unmount()
+
+		in, err := mount2.LookupInode(st.Inode)
+		assert.NoError(t, err)
+		require.NotNil(t, in)
+		defer in.Release()
+
+		fh, err := in.Open(os.O_RDONLY, nil)
+		require.NoError(t, err)
+		defer fh.Close()
+		buf := make([]byte, 16)
+		n, err := fh.ReadAt(buf, 0)
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, "hello", string(buf[:n]))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := mount.LookupInode(0xfffffffff)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,134 @@
+// +build !luminous,!mimic,!nautilus,!octopus
+//
+// Ceph Pacific is the first release that includes ceph_ll_lookup_vino().
+
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"encoding/binary"
+)
+
+// NoSnapID is the snapshot id that refers to the live (head) version of a
+// file rather than to one of its snapshots.
+const NoSnapID = uint64(0xfffffffffffffffe)
+
+// LookupVino returns the Inode with the given inode number within the
+// snapshot identified by snapid. Passing NoSnapID looks up the live
+// version of the inode. The inode number of a removed file may be reused
+// by a new file, see PersistentHandle for a handle that detects this.
+//
+// Implements:
+//  int ceph_ll_lookup_vino(struct ceph_mount_info *cmount, vinodeno_t vino, Inode **inode);
+func (mount *MountInfo) LookupVino(ino, snapid uint64) (*Inode, error) {
+	var (
+		inode *C.struct_Inode
+		vino  C.vinodeno_t
+	)
+	vino.ino.val = C.uint64_t(ino)
+	vino.snapid.val = C.uint64_t(snapid)
+	ret := C.ceph_ll_lookup_vino(mount.mount, vino, &inode)
+	if ret != 0 {
+		return nil, getError(ret)
+	}
+	return &Inode{mount: mount, inode: inode}, nil
+}
+
+// PersistentHandle identifies a file across client restarts, making it
+// suitable as the file handle of an NFS gateway.
+type PersistentHandle struct {
+	// FSCID is the id of the file system holding the file.
+	FSCID int64
+	Ino   uint64
+	// SnapID is the snapshot the handle refers to, NoSnapID for the live
+	// version of the file.
+	SnapID uint64
+	// Btime is the creation time of the inode. The MDS hands out the
+	// numbers of removed inodes again, so it serves as a generation number
+	// telling apart the files that were given the same inode number.
+	Btime Timespec
+}
+
+const persistentHandleLen = 36
+
+// PersistentHandle returns the persistent handle of the inode.
+func (in *Inode) PersistentHandle() (*PersistentHandle, error) {
+	// the statx of a file reports the snapid of the inode as its device
+	st, err := in.GetAttr(StatxIno|StatxBtime, AtNoAttrSync, nil)
+	if err != nil {
+		return nil, err
+	}
+	fscid, err := in.mount.fsCid()
+	if err != nil {
+		return nil, err
+	}
+	return &PersistentHandle{
+		FSCID:  fscid,
+		Ino:    st.Inode,
+		SnapID: st.Dev,
+		Btime:  st.Btime,
+	}, nil
+}
+
+// Encode returns the handle as a compact, fixed size, byte string.
+func (h *PersistentHandle) Encode() []byte {
+	buf := make([]byte, persistentHandleLen)
+	binary.LittleEndian.PutUint64(buf[0:], uint64(h.FSCID))
+	binary.LittleEndian.PutUint64(buf[8:], h.Ino)
+	binary.LittleEndian.PutUint64(buf[16:], h.SnapID)
+	binary.LittleEndian.PutUint64(buf[24:], uint64(h.Btime.Sec))
+	binary.LittleEndian.PutUint32(buf[32:], uint32(h.Btime.Nsec))
+	return buf
+}
+
+// DecodePersistentHandle parses a handle encoded by Encode.
+func DecodePersistentHandle(buf []byte) (*PersistentHandle, error) {
+	if len(buf) != persistentHandleLen {
+		return nil, ErrInvalid
+	}
+	return &PersistentHandle{
+		FSCID:  int64(binary.LittleEndian.Uint64(buf[0:])),
+		Ino:    binary.LittleEndian.Uint64(buf[8:]),
+		SnapID: binary.LittleEndian.Uint64(buf[16:]),
+		Btime: Timespec{
+			Sec:  int64(binary.LittleEndian.Uint64(buf[24:])),
+			Nsec: int64(binary.LittleEndian.Uint32(buf[32:])),
+		},
+	}, nil
+}
+
+// OpenPersistentHandle returns the Inode identified by the handle. The
+// reference must be released with Release when it is no longer needed.
+// ErrStale is returned if the handle belongs to another file system, or if
+// its file was removed, even if its inode number was reused since.
+func (mount *MountInfo) OpenPersistentHandle(h *PersistentHandle) (*Inode, error) {
+	fscid, err := mount.fsCid()
+	if err != nil {
+		return nil, err
+	}
+	if fscid != h.FSCID {
+		return nil, ErrStale
+	}
+	in, err := mount.LookupVino(h.Ino, h.SnapID)
+	if err == ErrNotExist {
+		return nil, ErrStale
+	} else if err != nil {
+		return nil, err
+	}
+	st, err := in.GetAttr(StatxBtime, 0, nil)
+	if err != nil {
+		in.Release()
+		return nil, err
+	}
+	if st.Btime != h.Btime {
+		in.Release()
+		return nil, ErrStale
+	}
+	return in, nil
+}
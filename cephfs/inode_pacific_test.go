@@ -0,0 +1,87 @@
+// +build !luminous,!mimic,!nautilus,!octopus
+
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupVino(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestLookupVino.txt"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	st, err := mount.Statx(fname, StatxIno, 0)
+	require.NoError(t, err)
+
+	in, err := mount.LookupVino(st.Inode, NoSnapID)
+	assert.NoError(t, err)
+	require.NotNil(t, in)
+	defer in.Release()
+
+	ist, err := in.GetAttr(StatxIno, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, st.Inode, ist.Inode)
+}
+
+func TestPersistentHandle(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestPersistentHandle.txt"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	st, err := mount.Statx(fname, StatxIno, 0)
+	require.NoError(t, err)
+	in, err := mount.LookupVino(st.Inode, NoSnapID)
+	require.NoError(t, err)
+	defer in.Release()
+
+	h, err := in.PersistentHandle()
+	require.NoError(t, err)
+	require.NotNil(t, h)
+	assert.Equal(t, st.Inode, h.Ino)
+	assert.Equal(t, NoSnapID, h.SnapID)
+	assert.NotZero(t, h.Btime.Sec)
+
+	buf := h.Encode()
+	assert.Len(t, buf, 36)
+	h2, err := DecodePersistentHandle(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, h, h2)
+	_, err = DecodePersistentHandle(buf[:16])
+	assert.Equal(t, ErrInvalid, err)
+
+	// the handle can be used by another client
+	mount2 := fsConnect(t)
+	defer mount2.Unmount()
+	in2, err := mount2.OpenPersistentHandle(h2)
+	require.NoError(t, err)
+	defer in2.Release()
+	ist, err := in2.GetAttr(StatxIno, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, st.Inode, ist.Inode)
+
+	// a file given the inode number of a removed one has another btime
+	reused := *h
+	reused.Btime.Nsec++
+	_, err = mount2.OpenPersistentHandle(&reused)
+	assert.Equal(t, ErrStale, err)
+
+	otherFS := *h
+	otherFS.FSCID++
+	_, err = mount2.OpenPersistentHandle(&otherFS)
+	assert.Equal(t, ErrStale, err)
+}
@@ -0,0 +1,143 @@
+package cephfs
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupRoot(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	root, err := mount.LookupRoot()
+	assert.NoError(t, err)
+	require.NotNil(t, root)
+
+	st, err := root.GetAttr(StatxBasicStats, 0, nil)
+	assert.NoError(t, err)
+	require.NotNil(t, st)
+	assert.Equal(t, uint16(syscall.S_IFDIR), st.Mode&syscall.S_IFMT)
+	// the root of a cephfs file system is always inode 1
+	assert.EqualValues(t, 1, st.Inode)
+
+	assert.NoError(t, root.Release())
+	// releasing a second time is harmless
+	assert.NoError(t, root.Release())
+
+	// a released inode can no longer be used
+	_, err = root.GetAttr(StatxBasicStats, 0, nil)
+	assert.Equal(t, errBadFile, err)
+	_, err = root.Lookup("x", nil)
+	assert.Equal(t, errBadFile, err)
+	_, err = root.Open(os.O_RDONLY, nil)
+	assert.Equal(t, errBadFile, err)
+	_, _, err = root.Create("x", os.O_RDWR, 0644, nil)
+	assert.Equal(t, errBadFile, err)
+	_, err = root.MakeDir("x", 0755, nil)
+	assert.Equal(t, errBadFile, err)
+	assert.Equal(t, errBadFile, root.RemoveDir("x", nil))
+	assert.Equal(t, errBadFile, root.Unlink("x", nil))
+}
+
+func TestInodeOperations(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	root, err := mount.LookupRoot()
+	require.NoError(t, err)
+	defer root.Release()
+
+	dname := "TestInodeOperations"
+	dir, err := root.MakeDir(dname, 0755, nil)
+	require.NoError(t, err)
+	defer root.RemoveDir(dname, nil)
+	defer dir.Release()
+
+	st, err := dir.GetAttr(StatxMode, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(0755), st.Mode&0777)
+
+	data := []byte("the quick brown fox")
+	t.Run("createWrite", func(t *testing.T) {
+		file, fh, err := dir.Create("file.txt", os.O_RDWR|os.O_CREATE, 0640, nil)
+		require.NoError(t, err)
+		defer file.Release()
+		defer fh.Close()
+
+		n, err := fh.WriteAt(data, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, len(data), n)
+		assert.NoError(t, fh.Fsync(SyncAll))
+
+		st, err := file.GetAttr(StatxSize|StatxMode, 0, nil)
+		require.NoError(t, err)
+		assert.EqualValues(t, len(data), st.Size)
+		assert.Equal(t, uint16(0640), st.Mode&0777)
+	})
+
+	t.Run("lookupRead", func(t *testing.T) {
+		file, err := dir.Lookup("file.txt", nil)
+		require.NoError(t, err)
+		defer file.Release()
+		fh, err := file.Open(os.O_RDONLY, nil)
+		require.NoError(t, err)
+		defer fh.Close()
+
+		buf := make([]byte, 64)
+		n, err := fh.ReadAt(buf, 4)
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, data[4:], buf[:n])
+
+		_, err = fh.ReadAt(buf, int64(len(data)))
+		assert.Equal(t, io.EOF, err)
+		_, err = fh.ReadAt(buf, -1)
+		assert.Error(t, err)
+	})
+
+	t.Run("visibleByPath", func(t *testing.T) {
+		st, err := mount.Statx(dname+"/file.txt", StatxSize, 0)
+		require.NoError(t, err)
+		assert.EqualValues(t, len(data), st.Size)
+	})
+
+	t.Run("lookupMissing", func(t *testing.T) {
+		_, err := dir.Lookup("missing.txt", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("unlink", func(t *testing.T) {
+		err := dir.Unlink("file.txt", nil)
+		assert.NoError(t, err)
+		_, err = dir.Lookup("file.txt", nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestInodePerms(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	root, err := mount.LookupRoot()
+	require.NoError(t, err)
+	defer root.Release()
+
+	dname := "TestInodePerms"
+	dir, err := root.MakeDir(dname, 0700, nil)
+	require.NoError(t, err)
+	defer root.RemoveDir(dname, nil)
+	defer dir.Release()
+
+	// dockerfile creates bob user account
+	bob := NewUserPerm(1010, 1010, nil)
+	defer bob.Destroy()
+
+	_, err = dir.MakeDir("sub", 0755, bob)
+	assert.Error(t, err)
+	_, _, err = dir.Create("file.txt", os.O_RDWR|os.O_CREATE, 0644, bob)
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+// +build !luminous,!mimic,!nautilus
+//
+// Ceph Octopus is the first release that accepts the MDS "dump inode"
+// command through ceph_mds_command().
+
+package cephfs
+
+import (
+	"encoding/json"
+)
+
+// InodePath returns the path, relative to the root of the file system, of
+// the inode with the given inode number. The path is resolved by the MDS
+// identified by mdsSpec, which must have the inode in its cache, using the
+// "dump inode" command. An error is returned if the MDS does not know of
+// the inode.
+func (mount *MountInfo) InodePath(mdsSpec string, ino uint64) (string, error) {
+	buf, err := mount.mdsJSONCommand(mdsSpec, map[string]interface{}{
+		"prefix": "dump inode",
+		"number": ino,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var inode struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(buf, &inode); err != nil {
+		return "", err
+	}
+	return inode.Path, nil
+}
@@ -0,0 +1,35 @@
+// +build !luminous,!mimic,!nautilus
+
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInodePath(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dname := "TestInodePath"
+	fname := dname + "/file.txt"
+	require.NoError(t, mount.MakeDir(dname, 0755))
+	defer mount.RemoveDir(dname)
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	st, err := mount.Statx(fname, StatxIno, 0)
+	require.NoError(t, err)
+
+	path, err := mount.InodePath(testMdsName, st.Inode)
+	assert.NoError(t, err)
+	assert.Equal(t, "/"+fname, path)
+
+	_, err = mount.InodePath(testMdsName, 0xfffffffff)
+	assert.Error(t, err)
+}
@@ -0,0 +1,241 @@
+// +build go1.16
+
+package cephfs
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"syscall"
+)
+
+// FS adapts a mounted file system to the interfaces of the io/fs package,
+// so that CephFS trees can be used with anything that consumes an fs.FS,
+// such as http.FS or template.ParseFS. Names are interpreted relative to
+// the root of the mount and use the slash separated, unrooted form
+// required by io/fs. The FS is read-only.
+type FS struct {
+	mount *MountInfo
+}
+
+var (
+	_ fs.FS          = &FS{}
+	_ fs.StatFS      = &FS{}
+	_ fs.ReadDirFS   = &FS{}
+	_ fs.ReadDirFile = &fsDir{}
+	// http.FS requires files to be seekable to serve them
+	_ io.Seeker   = &fsFile{}
+	_ io.ReaderAt = &fsFile{}
+)
+
+// NewFS returns an FS backed by the given mount.
+func NewFS(mount *MountInfo) *FS {
+	return &FS{mount: mount}
+}
+
+// cephPath converts a valid io/fs name to a path within the mount.
+func cephPath(name string) string {
+	if name == "." {
+		return "/"
+	}
+	return "/" + name
+}
+
+// toPathError returns err wrapped in an fs.PathError. Errors reported by
+// libcephfs are converted to their syscall.Errno equivalents so that they
+// can be matched against fs.ErrNotExist and friends with errors.Is.
+func toPathError(op, name string, err error) error {
+	if e, ok := err.(CephFSError); ok {
+		err = syscall.Errno(-e)
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+func (fsys *FS) stat(op, name string) (*fileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	stx, err := fsys.mount.Statx(cephPath(name), StatxBasicStats, 0)
+	if err != nil {
+		return nil, toPathError(op, name, err)
+	}
+	return &fileInfo{name: path.Base(name), statx: stx}, nil
+}
+
+// Stat returns a fs.FileInfo describing the named file. The Sys method of
+// the result returns a *CephStatx.
+func (fsys *FS) Stat(name string) (fs.FileInfo, error) {
+	return fsys.stat("stat", name)
+}
+
+// Open opens the named file or directory for reading.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	fi, err := fsys.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		dir, err := fsys.mount.OpenDir(cephPath(name))
+		if err != nil {
+			return nil, toPathError("open", name, err)
+		}
+		return &fsDir{fsys: fsys, dir: dir, name: name, info: fi}, nil
+	}
+	f, err := fsys.mount.Open(cephPath(name), os.O_RDONLY, 0)
+	if err != nil {
+		return nil, toPathError("open", name, err)
+	}
+	return &fsFile{file: f, name: name, info: fi}, nil
+}
+
+// ReadDir reads the named directory and returns a list of its entries
+// sorted by name.
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dir, ok := f.(*fsDir)
+	if !ok {
+		return nil, toPathError("readdir", name, syscall.ENOTDIR)
+	}
+	entries, err := dir.ReadDir(-1)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, err
+}
+
+// fsFile implements fs.File for regular (non-directory) files.
+type fsFile struct {
+	file *File
+	name string
+	info *fileInfo
+}
+
+func (f *fsFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *fsFile) Read(buf []byte) (int, error) {
+	n, err := f.file.Read(buf)
+	if err != nil && err != io.EOF {
+		err = toPathError("read", f.name, err)
+	}
+	return n, err
+}
+
+func (f *fsFile) ReadAt(buf []byte, offset int64) (int, error) {
+	n, err := f.file.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		err = toPathError("read", f.name, err)
+	}
+	return n, err
+}
+
+func (f *fsFile) Seek(offset int64, whence int) (int64, error) {
+	pos, err := f.file.Seek(offset, whence)
+	if err != nil {
+		err = toPathError("seek", f.name, err)
+	}
+	return pos, err
+}
+
+func (f *fsFile) Close() error {
+	if err := f.file.Close(); err != nil {
+		return toPathError("close", f.name, err)
+	}
+	return nil
+}
+
+// fsDir implements fs.ReadDirFile for directories.
+type fsDir struct {
+	fsys *FS
+	dir  *Directory
+	name string
+	info *fileInfo
+}
+
+func (d *fsDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *fsDir) Read([]byte) (int, error) {
+	return 0, toPathError("read", d.name, syscall.EISDIR)
+}
+
+func (d *fsDir) Close() error {
+	if err := d.dir.Close(); err != nil {
+		return toPathError("close", d.name, err)
+	}
+	return nil
+}
+
+// ReadDir returns up to n entries of the directory, as described for
+// fs.ReadDirFile. The "." and ".." entries are skipped.
+func (d *fsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries := []fs.DirEntry{}
+	for n <= 0 || len(entries) < n {
+		entry, err := d.dir.ReadDir()
+		if err != nil {
+			return entries, toPathError("readdir", d.name, err)
+		}
+		if entry == nil {
+			break
+		}
+		if entry.name == "." || entry.name == ".." {
+			continue
+		}
+		entries = append(entries, &fsDirEntry{
+			DirEntry: *entry,
+			fsys:     d.fsys,
+			dirName:  d.name,
+		})
+	}
+	if n > 0 && len(entries) == 0 {
+		return entries, io.EOF
+	}
+	return entries, nil
+}
+
+// fsDirEntry implements fs.DirEntry.
+type fsDirEntry struct {
+	DirEntry
+	fsys    *FS
+	dirName string
+}
+
+func (e *fsDirEntry) IsDir() bool {
+	return e.dtype == DTypeDir
+}
+
+func (e *fsDirEntry) Type() fs.FileMode {
+	switch e.dtype {
+	case DTypeDir:
+		return fs.ModeDir
+	case DTypeLnk:
+		return fs.ModeSymlink
+	case DTypeFIFO:
+		return fs.ModeNamedPipe
+	case DTypeSock:
+		return fs.ModeSocket
+	case DTypeChr:
+		return fs.ModeDevice | fs.ModeCharDevice
+	case DTypeBlk:
+		return fs.ModeDevice
+	}
+	return 0
+}
+
+func (e *fsDirEntry) Info() (fs.FileInfo, error) {
+	name := path.Join(e.dirName, e.name)
+	stx, err := e.fsys.mount.Statx(
+		cephPath(name), StatxBasicStats, AtSymlinkNofollow)
+	if err != nil {
+		return nil, toPathError("stat", name, err)
+	}
+	return &fileInfo{name: e.name, statx: stx}, nil
+}
@@ -0,0 +1,146 @@
+// +build go1.16
+
+package cephfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFS(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	base := "TestFS"
+	require.NoError(t, mount.MakeDir(base, 0755))
+	defer mount.RemoveDir(base)
+	require.NoError(t, mount.MakeDir(base+"/sub", 0755))
+	defer mount.RemoveDir(base + "/sub")
+	files := map[string]string{
+		base + "/a.txt":     "alpha",
+		base + "/sub/b.txt": "bravo bravo",
+	}
+	for name, content := range files {
+		f, err := mount.Open(name, os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+		defer mount.Unlink(name)
+	}
+
+	fsys := NewFS(mount)
+
+	t.Run("fstest", func(t *testing.T) {
+		sub, err := fs.Sub(fsys, base)
+		require.NoError(t, err)
+		assert.NoError(t, fstest.TestFS(sub, "a.txt", "sub/b.txt"))
+	})
+
+	t.Run("readFile", func(t *testing.T) {
+		data, err := fs.ReadFile(fsys, base+"/sub/b.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, "bravo bravo", string(data))
+	})
+
+	t.Run("readDir", func(t *testing.T) {
+		entries, err := fs.ReadDir(fsys, base)
+		assert.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "a.txt", entries[0].Name())
+		assert.False(t, entries[0].IsDir())
+		assert.Equal(t, "sub", entries[1].Name())
+		assert.True(t, entries[1].IsDir())
+
+		_, err = fs.ReadDir(fsys, base+"/a.txt")
+		assert.Error(t, err)
+	})
+
+	t.Run("stat", func(t *testing.T) {
+		fi, err := fs.Stat(fsys, base+"/a.txt")
+		assert.NoError(t, err)
+		require.NotNil(t, fi)
+		assert.Equal(t, "a.txt", fi.Name())
+		assert.EqualValues(t, 5, fi.Size())
+
+		fi, err = fs.Stat(fsys, ".")
+		assert.NoError(t, err)
+		require.NotNil(t, fi)
+		assert.True(t, fi.IsDir())
+	})
+
+	t.Run("seekReadAt", func(t *testing.T) {
+		f, err := fsys.Open(base + "/sub/b.txt")
+		require.NoError(t, err)
+		defer f.Close()
+
+		seeker, ok := f.(io.ReadSeeker)
+		require.True(t, ok)
+		pos, err := seeker.Seek(6, io.SeekStart)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 6, pos)
+		data, err := ioutil.ReadAll(seeker)
+		assert.NoError(t, err)
+		assert.Equal(t, "bravo", string(data))
+
+		readerAt, ok := f.(io.ReaderAt)
+		require.True(t, ok)
+		buf := make([]byte, 5)
+		n, err := readerAt.ReadAt(buf, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "bravo", string(buf[:n]))
+	})
+
+	t.Run("httpFileServer", func(t *testing.T) {
+		sub, err := fs.Sub(fsys, base)
+		require.NoError(t, err)
+		server := httptest.NewServer(http.FileServer(http.FS(sub)))
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "/sub/b.txt")
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "bravo bravo", string(body))
+		assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+
+		req, err := http.NewRequest("GET", server.URL+"/a.txt", nil)
+		require.NoError(t, err)
+		req.Header.Set("Range", "bytes=1-3")
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+		assert.Equal(t, "lph", string(body))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := fsys.Open(base + "/missing")
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+		_, err = fsys.Open("/" + base)
+		assert.True(t, errors.Is(err, fs.ErrInvalid))
+		_, err = fsys.Open(base + "/../" + base)
+		assert.True(t, errors.Is(err, fs.ErrInvalid))
+
+		f, err := fsys.Open(base)
+		require.NoError(t, err)
+		defer f.Close()
+		_, err = ioutil.ReadAll(f)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,83 @@
+package cephfs
+
+/*
+#include <stdlib.h>
+#include <string.h>
+#include <sys/uio.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// iovec manages an array of C struct iovec entries backed by C allocated
+// buffers. Go memory can not be referenced by memory handed to C, so the
+// contents of the Go buffers are copied in and out of the C buffers.
+type iovec struct {
+	iovecs  []C.struct_iovec
+	buffers [][]byte
+}
+
+var iovecSize = C.size_t(unsafe.Sizeof(C.struct_iovec{}))
+
+// newIovec allocates C memory matching the layout of the given Go buffers.
+// If copyIn is true the contents of the Go buffers are copied to C memory.
+func newIovec(buffers [][]byte, copyIn bool) *iovec {
+	iov := &iovec{buffers: buffers}
+	if len(buffers) == 0 {
+		return iov
+	}
+	p := C.malloc(iovecSize * C.size_t(len(buffers)))
+	iov.iovecs = (*[1 << 28]C.struct_iovec)(p)[:len(buffers):len(buffers)]
+	for i, buf := range buffers {
+		iov.iovecs[i].iov_len = C.size_t(len(buf))
+		iov.iovecs[i].iov_base = C.malloc(C.size_t(len(buf)) + 1)
+		if copyIn && len(buf) > 0 {
+			C.memcpy(iov.iovecs[i].iov_base, unsafe.Pointer(&buf[0]), C.size_t(len(buf)))
+		}
+	}
+	return iov
+}
+
+// pointer returns a pointer to the C iovec array, suitable for passing
+// to a C function.
+func (iov *iovec) pointer() *C.struct_iovec {
+	if len(iov.iovecs) == 0 {
+		return nil
+	}
+	return &iov.iovecs[0]
+}
+
+// length returns the number of entries in the iovec array.
+func (iov *iovec) length() C.int {
+	return C.int(len(iov.iovecs))
+}
+
+// copyOut copies up to n bytes from the C buffers back to the Go buffers.
+func (iov *iovec) copyOut(n int) {
+	for i, buf := range iov.buffers {
+		if n <= 0 {
+			return
+		}
+		count := len(buf)
+		if count > n {
+			count = n
+		}
+		if count > 0 {
+			C.memcpy(unsafe.Pointer(&buf[0]), iov.iovecs[i].iov_base, C.size_t(count))
+		}
+		n -= count
+	}
+}
+
+// free releases all C memory held by the iovec.
+func (iov *iovec) free() {
+	for i := range iov.iovecs {
+		C.free(iov.iovecs[i].iov_base)
+	}
+	if len(iov.iovecs) > 0 {
+		C.free(unsafe.Pointer(&iov.iovecs[0]))
+	}
+	iov.iovecs = nil
+}
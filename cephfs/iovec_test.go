@@ -0,0 +1,35 @@
+package cephfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIovec(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		iov := newIovec(nil, true)
+		assert.Nil(t, iov.pointer())
+		assert.EqualValues(t, 0, iov.length())
+		iov.copyOut(10)
+		iov.free()
+	})
+
+	t.Run("roundTrip", func(t *testing.T) {
+		src := [][]byte{[]byte("abc"), {}, []byte("defgh")}
+		iov := newIovec(src, true)
+		defer iov.free()
+		assert.NotNil(t, iov.pointer())
+		assert.EqualValues(t, 3, iov.length())
+
+		// clobber the go buffers, then restore a prefix from C memory
+		for _, b := range src {
+			for i := range b {
+				b[i] = '-'
+			}
+		}
+		iov.copyOut(5)
+		assert.Equal(t, "abc", string(src[0]))
+		assert.Equal(t, "de---", string(src[2]))
+	})
+}
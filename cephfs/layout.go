@@ -0,0 +1,150 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	fileLayoutXattr = "ceph.file.layout"
+	dirLayoutXattr  = "ceph.dir.layout"
+)
+
+// Layout describes how the data of a file is striped over RADOS objects.
+// When setting a layout, fields with zero values are left unchanged.
+type Layout struct {
+	// StripeUnit is the size, in bytes, of the blocks data is striped in.
+	StripeUnit uint64
+	// StripeCount is the number of objects a stripe is spread over.
+	StripeCount uint64
+	// ObjectSize is the maximum size, in bytes, of each RADOS object.
+	ObjectSize uint64
+	// Pool identifies the data pool, either by name or by numeric id.
+	Pool string
+	// PoolNamespace is the RADOS namespace, within the pool, the data
+	// objects are stored in.
+	PoolNamespace string
+}
+
+// parseLayout parses the "key=value ..." format of the layout vxattrs.
+func parseLayout(value string) (*Layout, error) {
+	layout := &Layout{}
+	for _, field := range strings.Fields(value) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		var err error
+		switch kv[0] {
+		case "stripe_unit":
+			layout.StripeUnit, err = strconv.ParseUint(kv[1], 10, 64)
+		case "stripe_count":
+			layout.StripeCount, err = strconv.ParseUint(kv[1], 10, 64)
+		case "object_size":
+			layout.ObjectSize, err = strconv.ParseUint(kv[1], 10, 64)
+		case "pool":
+			layout.Pool = kv[1]
+		case "pool_namespace":
+			layout.PoolNamespace = kv[1]
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return layout, nil
+}
+
+// String returns the layout in the "key=value ..." format understood by
+// the layout vxattrs. Fields with zero values are omitted.
+func (l *Layout) String() string {
+	fields := []string{}
+	if l.StripeUnit != 0 {
+		fields = append(fields, "stripe_unit="+strconv.FormatUint(l.StripeUnit, 10))
+	}
+	if l.StripeCount != 0 {
+		fields = append(fields, "stripe_count="+strconv.FormatUint(l.StripeCount, 10))
+	}
+	if l.ObjectSize != 0 {
+		fields = append(fields, "object_size="+strconv.FormatUint(l.ObjectSize, 10))
+	}
+	if l.Pool != "" {
+		fields = append(fields, "pool="+l.Pool)
+	}
+	if l.PoolNamespace != "" {
+		fields = append(fields, "pool_namespace="+l.PoolNamespace)
+	}
+	return strings.Join(fields, " ")
+}
+
+func (mount *MountInfo) getLayout(path, name string) (*Layout, error) {
+	value, err := mount.GetXattr(path, name)
+	if err != nil {
+		return nil, err
+	}
+	return parseLayout(string(value))
+}
+
+// GetFileLayout returns the layout of the file at the given path.
+func (mount *MountInfo) GetFileLayout(path string) (*Layout, error) {
+	return mount.getLayout(path, fileLayoutXattr)
+}
+
+// SetFileLayout changes the layout of the file at the given path. The
+// layout of a file can only be changed while the file is empty.
+func (mount *MountInfo) SetFileLayout(path string, layout *Layout) error {
+	return mount.SetXattr(
+		path, fileLayoutXattr, []byte(layout.String()), XattrDefault)
+}
+
+// GetDirLayout returns the layout set on the directory at the given path.
+// New files created below the directory are given this layout. If the
+// directory has no layout of its own, and so inherits the layout of its
+// parent, nil is returned.
+func (mount *MountInfo) GetDirLayout(path string) (*Layout, error) {
+	layout, err := mount.getLayout(path, dirLayoutXattr)
+	if err == errNoData {
+		return nil, nil
+	}
+	return layout, err
+}
+
+// SetDirLayout sets the layout of the directory at the given path. The
+// layout applies to files created below the directory afterwards; existing
+// files are not changed.
+func (mount *MountInfo) SetDirLayout(path string, layout *Layout) error {
+	return mount.SetXattr(
+		path, dirLayoutXattr, []byte(layout.String()), XattrDefault)
+}
+
+// GetLayout returns the layout of the open file. The Pool field is set to
+// the numeric id of the data pool.
+//
+// Implements:
+//  int ceph_get_file_layout(struct ceph_mount_info *cmount, int fh, int *stripe_unit,
+//                           int *stripe_count, int *object_size, int *pg_pool);
+func (f *File) GetLayout() (*Layout, error) {
+	if err := f.rlock(); err != nil {
+		return nil, err
+	}
+	defer f.mu.RUnlock()
+
+	var stripeUnit, stripeCount, objectSize, pool C.int
+	ret := C.ceph_get_file_layout(
+		f.mount.mount, f.fd, &stripeUnit, &stripeCount, &objectSize, &pool)
+	if err := getError(ret); err != nil {
+		return nil, err
+	}
+	return &Layout{
+		StripeUnit:  uint64(stripeUnit),
+		StripeCount: uint64(stripeCount),
+		ObjectSize:  uint64(objectSize),
+		Pool:        strconv.Itoa(int(pool)),
+	}, nil
+}
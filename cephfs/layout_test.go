@@ -0,0 +1,130 @@
+package cephfs
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLayout(t *testing.T) {
+	l, err := parseLayout(
+		"stripe_unit=1048576 stripe_count=2 object_size=8388608 pool=cephfs_data pool_namespace=ns1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Layout{
+		StripeUnit:    1048576,
+		StripeCount:   2,
+		ObjectSize:    8388608,
+		Pool:          "cephfs_data",
+		PoolNamespace: "ns1",
+	}, l)
+	// String is the inverse
+	assert.Equal(t,
+		"stripe_unit=1048576 stripe_count=2 object_size=8388608 pool=cephfs_data pool_namespace=ns1",
+		l.String())
+
+	// zero fields are omitted
+	assert.Equal(t, "object_size=4194304", (&Layout{ObjectSize: 4194304}).String())
+
+	_, err = parseLayout("stripe_unit=abc")
+	assert.Error(t, err)
+}
+
+func TestFileLayout(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestFileLayout.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+
+	l, err := mount.GetFileLayout(fname)
+	assert.NoError(t, err)
+	require.NotNil(t, l)
+	assert.NotEqual(t, uint64(0), l.StripeUnit)
+	assert.NotEqual(t, uint64(0), l.StripeCount)
+	assert.NotEqual(t, uint64(0), l.ObjectSize)
+	assert.Equal(t, "cephfs_data", l.Pool)
+
+	err = mount.SetFileLayout(fname, &Layout{
+		StripeUnit:  1048576,
+		StripeCount: 2,
+		ObjectSize:  8388608,
+	})
+	assert.NoError(t, err)
+
+	l, err = mount.GetFileLayout(fname)
+	assert.NoError(t, err)
+	require.NotNil(t, l)
+	assert.EqualValues(t, 1048576, l.StripeUnit)
+	assert.EqualValues(t, 2, l.StripeCount)
+	assert.EqualValues(t, 8388608, l.ObjectSize)
+
+	t.Run("openFile", func(t *testing.T) {
+		fl, err := f.GetLayout()
+		assert.NoError(t, err)
+		require.NotNil(t, fl)
+		assert.EqualValues(t, 1048576, fl.StripeUnit)
+		assert.EqualValues(t, 2, fl.StripeCount)
+		assert.EqualValues(t, 8388608, fl.ObjectSize)
+		_, err = strconv.Atoi(fl.Pool)
+		assert.NoError(t, err)
+	})
+
+	t.Run("nonEmpty", func(t *testing.T) {
+		_, err := f.Write([]byte("data"))
+		require.NoError(t, err)
+		require.NoError(t, f.Fsync(SyncAll))
+		err = mount.SetFileLayout(fname, &Layout{StripeCount: 4})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := mount.GetFileLayout("TestFileLayout.missing")
+		assert.Error(t, err)
+	})
+}
+
+func TestDirLayout(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dname := "TestDirLayout"
+	require.NoError(t, mount.MakeDir(dname, 0755))
+	defer mount.RemoveDir(dname)
+
+	l, err := mount.GetDirLayout(dname)
+	assert.NoError(t, err)
+	assert.Nil(t, l)
+
+	err = mount.SetDirLayout(dname, &Layout{
+		StripeUnit:  65536,
+		StripeCount: 4,
+		ObjectSize:  4194304,
+	})
+	assert.NoError(t, err)
+
+	l, err = mount.GetDirLayout(dname)
+	assert.NoError(t, err)
+	require.NotNil(t, l)
+	assert.EqualValues(t, 65536, l.StripeUnit)
+	assert.EqualValues(t, 4, l.StripeCount)
+
+	// new files inherit the layout of the directory
+	fname := dname + "/file.txt"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	l, err = mount.GetFileLayout(fname)
+	assert.NoError(t, err)
+	require.NotNil(t, l)
+	assert.EqualValues(t, 65536, l.StripeUnit)
+	assert.EqualValues(t, 4, l.StripeCount)
+	assert.EqualValues(t, 4194304, l.ObjectSize)
+}
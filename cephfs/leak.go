@@ -0,0 +1,132 @@
+package cephfs
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HandleKind identifies the type of an OpenHandle.
+type HandleKind string
+
+const (
+	// FileHandleKind is the kind of handles for files opened with Open.
+	FileHandleKind = HandleKind("file")
+	// DirectoryHandleKind is the kind of handles for directories opened
+	// with OpenDir.
+	DirectoryHandleKind = HandleKind("directory")
+)
+
+// OpenHandle describes a File or Directory that was opened through a
+// mount and has not been closed.
+type OpenHandle struct {
+	Kind   HandleKind
+	Path   string
+	Opened time.Time
+}
+
+// LeakHandler is called with the description of a File or Directory that
+// was garbage collected without being closed.
+type LeakHandler func(OpenHandle)
+
+// handleRegistry keeps track of the handles opened through a mount. It
+// stores descriptions rather than the handles themselves so that it does
+// not keep leaked handles from being garbage collected.
+type handleRegistry struct {
+	mu     sync.Mutex
+	nextID uint64
+	open   map[uint64]OpenHandle
+	leakFn LeakHandler
+}
+
+// add registers a new handle and returns its id along with the current
+// leak handler.
+func (r *handleRegistry) add(kind HandleKind, path string) (uint64, LeakHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.open == nil {
+		r.open = map[uint64]OpenHandle{}
+	}
+	r.nextID++
+	r.open[r.nextID] = OpenHandle{Kind: kind, Path: path, Opened: time.Now()}
+	return r.nextID, r.leakFn
+}
+
+func (r *handleRegistry) remove(id uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.open, id)
+}
+
+func (r *handleRegistry) get(id uint64) (OpenHandle, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, ok := r.open[id]
+	return h, ok
+}
+
+// OpenHandles returns the Files and Directories opened through the mount
+// that have not been closed, oldest first. It is intended to help find
+// handles that are leaked by an application.
+func (mount *MountInfo) OpenHandles() []OpenHandle {
+	r := &mount.handles
+	r.mu.Lock()
+	handles := make([]OpenHandle, 0, len(r.open))
+	for _, h := range r.open {
+		handles = append(handles, h)
+	}
+	r.mu.Unlock()
+	sort.Slice(handles, func(i, j int) bool {
+		return handles[i].Opened.Before(handles[j].Opened)
+	})
+	return handles
+}
+
+// SetLeakHandler sets a function to be called when a File or Directory
+// opened through the mount is garbage collected without being closed. The
+// handler only applies to handles opened after it is set, and is called
+// from the finalizer goroutine so it must not block. Passing nil disables
+// the check for handles opened afterwards.
+//
+// Note that the underlying libcephfs handle of a leaked File or Directory
+// stays open and is still reported by OpenHandles.
+func (mount *MountInfo) SetLeakHandler(fn LeakHandler) {
+	mount.handles.mu.Lock()
+	defer mount.handles.mu.Unlock()
+	mount.handles.leakFn = fn
+}
+
+// trackFile registers an opened file with the mount.
+func (mount *MountInfo) trackFile(f *File, path string) {
+	id, leakFn := mount.handles.add(FileHandleKind, path)
+	f.handleID = id
+	if leakFn == nil {
+		return
+	}
+	runtime.SetFinalizer(f, func(f *File) {
+		if f.fd == -1 {
+			return
+		}
+		if h, ok := mount.handles.get(f.handleID); ok {
+			leakFn(h)
+		}
+	})
+}
+
+// trackDir registers an opened directory with the mount.
+func (mount *MountInfo) trackDir(dir *Directory, path string) {
+	id, leakFn := mount.handles.add(DirectoryHandleKind, path)
+	dir.handleID = id
+	if leakFn == nil {
+		return
+	}
+	runtime.SetFinalizer(dir, func(dir *Directory) {
+		if dir.dir == nil {
+			return
+		}
+		if h, ok := mount.handles.get(dir.handleID); ok {
+			leakFn(h)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package cephfs
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenHandles(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	assert.Len(t, mount.OpenHandles(), 0)
+
+	fname := "TestOpenHandles.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, mount.Unlink(fname)) }()
+	dir, err := mount.OpenDir("/")
+	require.NoError(t, err)
+
+	handles := mount.OpenHandles()
+	require.Len(t, handles, 2)
+	assert.Equal(t, FileHandleKind, handles[0].Kind)
+	assert.Equal(t, fname, handles[0].Path)
+	assert.Equal(t, DirectoryHandleKind, handles[1].Kind)
+	assert.Equal(t, "/", handles[1].Path)
+	assert.False(t, handles[0].Opened.IsZero())
+
+	assert.NoError(t, f.Close())
+	handles = mount.OpenHandles()
+	require.Len(t, handles, 1)
+	assert.Equal(t, DirectoryHandleKind, handles[0].Kind)
+
+	assert.NoError(t, dir.Close())
+	assert.Len(t, mount.OpenHandles(), 0)
+
+	// failing to open does not register a handle
+	_, err = mount.OpenDir("/no.such.dir")
+	assert.Error(t, err)
+	assert.Len(t, mount.OpenHandles(), 0)
+}
+
+func TestLeakHandler(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	leaked := make(chan OpenHandle, 1)
+	mount.SetLeakHandler(func(h OpenHandle) {
+		leaked <- h
+	})
+
+	fname := "TestLeakHandler.txt"
+	func() {
+		f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		_, err = f.Write([]byte("leak"))
+		assert.NoError(t, err)
+	}()
+	defer func() { assert.NoError(t, mount.Unlink(fname)) }()
+
+	var h OpenHandle
+	for i := 0; i < 50 && h.Path == ""; i++ {
+		runtime.GC()
+		select {
+		case h = <-leaked:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	assert.Equal(t, fname, h.Path)
+	assert.Equal(t, FileHandleKind, h.Kind)
+	// the leaked file is still open
+	assert.Len(t, mount.OpenHandles(), 1)
+
+	// closed files are not reported
+	mount.SetLeakHandler(func(h OpenHandle) {
+		t.Errorf("closed handle reported as leaked: %v", h)
+	})
+	dir, err := mount.OpenDir("/")
+	require.NoError(t, err)
+	assert.NoError(t, dir.Close())
+	dir = nil
+	runtime.GC()
+}
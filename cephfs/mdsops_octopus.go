@@ -0,0 +1,123 @@
+// +build !luminous,!mimic,!nautilus
+//
+// Ceph Octopus is the first release that accepts the MDS "ops" and
+// "scrub" commands through ceph_mds_command().
+
+package cephfs
+
+import (
+	"encoding/json"
+)
+
+// OpTypeData holds the operation specific details of an OpInFlight.
+type OpTypeData struct {
+	// FlagPoint describes the stage the operation has reached.
+	FlagPoint string `json:"flag_point"`
+	// ReqID identifies the client request.
+	ReqID string `json:"reqid"`
+	// OpType is the kind of operation, for example "client_request".
+	OpType string `json:"op_type"`
+}
+
+// OpInFlight describes an operation being processed by an MDS.
+type OpInFlight struct {
+	Description string     `json:"description"`
+	InitiatedAt string     `json:"initiated_at"`
+	Age         float64    `json:"age"`
+	Duration    float64    `json:"duration"`
+	TypeData    OpTypeData `json:"type_data"`
+}
+
+// DumpOpsInFlight returns the operations currently being processed by the
+// MDS identified by mdsSpec. Long running operations are often the first
+// sign of a stuck client or a slow OSD.
+func (mount *MountInfo) DumpOpsInFlight(mdsSpec string) ([]OpInFlight, error) {
+	buf, err := mount.mdsJSONCommand(mdsSpec, map[string]interface{}{
+		"prefix": "dump_ops_in_flight",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ops struct {
+		Ops []OpInFlight `json:"ops"`
+	}
+	if err := json.Unmarshal(buf, &ops); err != nil {
+		return nil, err
+	}
+	return ops.Ops, nil
+}
+
+// ScrubOption values modify the behavior of a scrub started by StartScrub.
+type ScrubOption string
+
+const (
+	// ScrubRecursive scrubs the entire tree below the path.
+	ScrubRecursive = ScrubOption("recursive")
+	// ScrubForce scrubs inodes even if they were recently scrubbed.
+	ScrubForce = ScrubOption("force")
+	// ScrubRepair repairs the damage found by the scrub.
+	ScrubRepair = ScrubOption("repair")
+)
+
+// StartScrub starts a scrub of path on the MDS identified by mdsSpec. The
+// scrub runs in the background; the tag identifying it is returned and can
+// be found in the output of ScrubStatus.
+func (mount *MountInfo) StartScrub(mdsSpec, path string, opts ...ScrubOption) (string, error) {
+	scrubops := make([]string, len(opts))
+	for i, o := range opts {
+		scrubops[i] = string(o)
+	}
+	args := map[string]interface{}{
+		"prefix": "scrub start",
+		"path":   path,
+	}
+	if len(scrubops) > 0 {
+		args["scrubops"] = scrubops
+	}
+	buf, err := mount.mdsJSONCommand(mdsSpec, args)
+	if err != nil {
+		return "", err
+	}
+	var res struct {
+		ReturnCode int    `json:"return_code"`
+		ScrubTag   string `json:"scrub_tag"`
+	}
+	if err := json.Unmarshal(buf, &res); err != nil {
+		return "", err
+	}
+	if res.ReturnCode < 0 {
+		return "", CephFSError(res.ReturnCode)
+	}
+	return res.ScrubTag, nil
+}
+
+// ScrubInfo describes a scrub being run by an MDS.
+type ScrubInfo struct {
+	Path    string `json:"path"`
+	Tag     string `json:"tag"`
+	Options string `json:"options"`
+}
+
+// ScrubStatus reports the state of the scrubs of an MDS.
+type ScrubStatus struct {
+	// Status is a human readable summary of the scrub state.
+	Status string `json:"status"`
+	// Scrubs holds the active scrubs, keyed by tag.
+	Scrubs map[string]ScrubInfo `json:"scrubs"`
+}
+
+// GetScrubStatus returns the state of the scrubs of the MDS identified by
+// mdsSpec.
+func (mount *MountInfo) GetScrubStatus(mdsSpec string) (*ScrubStatus, error) {
+	buf, err := mount.mdsJSONCommand(mdsSpec, map[string]interface{}{
+		"prefix": "scrub status",
+	})
+	if err != nil {
+		return nil, err
+	}
+	status := &ScrubStatus{}
+	if err := json.Unmarshal(buf, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
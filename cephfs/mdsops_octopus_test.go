@@ -0,0 +1,44 @@
+// +build !luminous,!mimic,!nautilus
+
+package cephfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpOpsInFlight(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	ops, err := mount.DumpOpsInFlight(testMdsName)
+	assert.NoError(t, err)
+	for _, op := range ops {
+		assert.NotEmpty(t, op.Description)
+	}
+
+	_, err = mount.DumpOpsInFlight("no-such-mds")
+	assert.Error(t, err)
+}
+
+func TestScrub(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	tag, err := mount.StartScrub(testMdsName, "/", ScrubRecursive)
+	require.NoError(t, err)
+	assert.NotEmpty(t, tag)
+
+	status, err := mount.GetScrubStatus(testMdsName)
+	require.NoError(t, err)
+	assert.NotEmpty(t, status.Status)
+	// the scrub of the small test file system may already be complete
+	if s, ok := status.Scrubs[tag]; ok {
+		assert.Equal(t, "/", s.Path)
+	}
+
+	_, err = mount.StartScrub(testMdsName, "/no.such.dir")
+	assert.Error(t, err)
+}
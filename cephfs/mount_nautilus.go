@@ -0,0 +1,26 @@
+// +build !luminous,!mimic
+//
+// Ceph Nautilus is the first release that includes ceph_mount_perms_set().
+
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+// SetMountPerms applies the given UserPerm to the mount object, which it
+// will then use to define the mount's default credentials. All calls made
+// through the mount are then checked against, and performed with, these
+// credentials rather than those of the process.
+//
+// This must be called after Init but before Mount.
+//
+// Implements:
+//  int ceph_mount_perms_set(struct ceph_mount_info *cmount, UserPerm *perm);
+func (mount *MountInfo) SetMountPerms(perm *UserPerm) error {
+	ret := C.ceph_mount_perms_set(mount.mount, perm.userPerm)
+	return getError(ret)
+}
@@ -0,0 +1,58 @@
+// +build !luminous,!mimic
+
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetMountPerms(t *testing.T) {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	require.NotNil(t, mount)
+	defer mount.Release()
+
+	err = mount.ReadDefaultConfigFile()
+	require.NoError(t, err)
+	err = mount.Init()
+	require.NoError(t, err)
+
+	// dockerfile creates bob user account
+	uperm := NewUserPerm(1010, 1010, []int{1010})
+	defer uperm.Destroy()
+	err = mount.SetMountPerms(uperm)
+	require.NoError(t, err)
+
+	err = mount.Mount()
+	require.NoError(t, err)
+	defer mount.Unmount()
+
+	// the root of the file system is only writable by root
+	open := "TestSetMountPerms.open"
+	closed := "TestSetMountPerms.closed"
+	require.NoError(t, os.Mkdir(CephMountTest+open, 0755))
+	defer os.Remove(CephMountTest + open)
+	require.NoError(t, os.Chmod(CephMountTest+open, 0777))
+	require.NoError(t, os.Mkdir(CephMountTest+closed, 0700))
+	defer os.Remove(CephMountTest + closed)
+
+	fname := open + "/file.txt"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	// the file is owned by the credentials of the mount
+	st, err := mount.Statx(fname, StatxUid|StatxGid, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1010, st.Uid)
+	assert.EqualValues(t, 1010, st.Gid)
+
+	// and the credentials are also used for permission checks
+	_, err = mount.Open(closed+"/file.txt", os.O_WRONLY|os.O_CREATE, 0644)
+	assert.Error(t, err)
+}
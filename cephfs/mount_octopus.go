@@ -0,0 +1,23 @@
+// +build !luminous,!mimic,!nautilus
+//
+// Ceph Octopus is the first release that includes ceph_umask().
+
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+// Umask sets the file mode creation mask of the mount, like the umask
+// system call does for a process, and returns the previous mask. The mask
+// is applied to the mode of the files and directories created through the
+// mount.
+//
+// Implements:
+//  mode_t ceph_umask(struct ceph_mount_info *cmount, mode_t mode);
+func (mount *MountInfo) Umask(mask uint32) uint32 {
+	return uint32(C.ceph_umask(mount.mount, C.mode_t(mask)))
+}
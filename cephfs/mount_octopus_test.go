@@ -0,0 +1,34 @@
+// +build !luminous,!mimic,!nautilus
+
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUmask(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	orig := mount.Umask(027)
+	defer mount.Umask(orig)
+	assert.Equal(t, uint32(027), mount.Umask(027))
+
+	dname := "/umask"
+	require.NoError(t, mount.MakeDir(dname, 0777))
+	defer func() { assert.NoError(t, mount.RemoveAll(dname)) }()
+	st, err := mount.Statx(dname, StatxMode, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0750, st.Mode&0777)
+
+	f, err := mount.Open(dname+"/file", os.O_WRONLY|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	st, err = mount.Statx(dname+"/file", StatxMode, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0640, st.Mode&0777)
+}
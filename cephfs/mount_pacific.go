@@ -0,0 +1,34 @@
+// +build !luminous,!mimic,!nautilus,!octopus
+//
+// Ceph Pacific is the first release that includes ceph_getaddrs().
+
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// GetAddrs returns the addresses the client uses to communicate with the
+// cluster, formatted as an address vector, e.g.
+// "[v2:10.0.0.1:0/123456,v1:10.0.0.1:0/123456]". These are the addresses
+// reported in the "inst" field of the client's MDS session.
+//
+// Implements:
+//  int ceph_getaddrs(struct ceph_mount_info *cmount, char **addrs);
+func (mount *MountInfo) GetAddrs() (string, error) {
+	var cAddrs *C.char
+	ret := C.ceph_getaddrs(mount.mount, &cAddrs)
+	if ret < 0 {
+		return "", getError(ret)
+	}
+	defer C.free(unsafe.Pointer(cAddrs))
+	return C.GoString(cAddrs), nil
+}
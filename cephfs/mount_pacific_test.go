@@ -0,0 +1,40 @@
+// +build !luminous,!mimic,!nautilus,!octopus
+
+package cephfs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAddrs(t *testing.T) {
+	mount := mountWithID(t, "getaddrs")
+	defer mount.Unmount()
+
+	addrs, err := mount.GetAddrs()
+	require.NoError(t, err)
+	assert.NotEmpty(t, addrs)
+
+	// the addresses can be used to find the client's MDS session
+	sessions, err := mount.ListClientSessions(testMdsName)
+	require.NoError(t, err)
+	s := findSession(sessions, "getaddrs")
+	require.NotNil(t, s)
+	addr := strings.Trim(addrs, "[]")
+	if i := strings.Index(addr, ","); i >= 0 {
+		addr = addr[:i]
+	}
+	assert.Contains(t, s.Inst, strings.TrimPrefix(strings.TrimPrefix(addr, "v2:"), "v1:"))
+}
+
+func TestGetAddrsUnmounted(t *testing.T) {
+	mount, err := CreateMount()
+	require.NoError(t, err)
+	defer mount.Release()
+
+	_, err = mount.GetAddrs()
+	assert.Error(t, err)
+}
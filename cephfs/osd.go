@@ -0,0 +1,77 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <sys/socket.h>
+#include <netinet/in.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"net"
+	"unsafe"
+)
+
+// GetLocalOSD returns the id of an OSD running on the same host as the
+// client, as determined from the OSD map, or -1 if there is none. Together
+// with GetExtentOSDs this allows applications to prefer reading data that
+// is stored locally.
+//
+// Implements:
+//  int ceph_get_local_osd(struct ceph_mount_info *cmount);
+func (mount *MountInfo) GetLocalOSD() (int, error) {
+	ret := C.ceph_get_local_osd(mount.mount)
+	switch {
+	case ret == -1:
+		// no osd shares an address with the client
+		return -1, nil
+	case ret < 0:
+		return -1, getError(ret)
+	}
+	return int(ret), nil
+}
+
+// GetOSDAddr returns the address of the OSD with the given id.
+//
+// Implements:
+//  int ceph_get_osd_addr(struct ceph_mount_info *cmount, int osd, struct sockaddr_storage *addr);
+func (mount *MountInfo) GetOSDAddr(osd int) (*net.TCPAddr, error) {
+	var ss C.struct_sockaddr_storage
+	ret := C.ceph_get_osd_addr(mount.mount, C.int(osd), &ss)
+	if ret < 0 {
+		return nil, getError(ret)
+	}
+	return sockaddrToTCPAddr(&ss)
+}
+
+// sockaddrToTCPAddr converts an IPv4 or IPv6 socket address to a
+// net.TCPAddr.
+func sockaddrToTCPAddr(ss *C.struct_sockaddr_storage) (*net.TCPAddr, error) {
+	switch ss.ss_family {
+	case C.AF_INET:
+		sin := (*C.struct_sockaddr_in)(unsafe.Pointer(ss))
+		ip := C.GoBytes(unsafe.Pointer(&sin.sin_addr), C.sizeof_struct_in_addr)
+		return &net.TCPAddr{
+			IP:   net.IP(ip),
+			Port: int(ntohs(uint16(sin.sin_port))),
+		}, nil
+	case C.AF_INET6:
+		sin6 := (*C.struct_sockaddr_in6)(unsafe.Pointer(ss))
+		ip := C.GoBytes(unsafe.Pointer(&sin6.sin6_addr), C.sizeof_struct_in6_addr)
+		return &net.TCPAddr{
+			IP:   net.IP(ip),
+			Port: int(ntohs(uint16(sin6.sin6_port))),
+		}, nil
+	}
+	return nil, ErrInvalid
+}
+
+// ntohs converts a 16 bit value from network to host byte order.
+func ntohs(v uint16) uint16 {
+	var b [2]byte
+	*(*uint16)(unsafe.Pointer(&b[0])) = v
+	return binary.BigEndian.Uint16(b[:])
+}
@@ -0,0 +1,38 @@
+package cephfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLocalOSD(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	osd, err := mount.GetLocalOSD()
+	assert.NoError(t, err)
+	// the test cluster runs all of its daemons on the local host, but the
+	// osd may have bound to a different address than the client
+	if osd >= 0 {
+		_, err = mount.GetOSDAddr(osd)
+		assert.NoError(t, err)
+	} else {
+		assert.Equal(t, -1, osd)
+	}
+}
+
+func TestGetOSDAddr(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	addr, err := mount.GetOSDAddr(0)
+	require.NoError(t, err)
+	require.NotNil(t, addr)
+	assert.NotNil(t, addr.IP)
+	assert.NotEqual(t, 0, addr.Port)
+
+	_, err = mount.GetOSDAddr(1000)
+	assert.Error(t, err)
+}
@@ -0,0 +1,334 @@
+package cephfs
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatx(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	t.Run("statDirectory", func(t *testing.T) {
+		dirname := "statme"
+		require.NoError(t, mount.MakeDir(dirname, 0755))
+		defer mount.RemoveDir(dirname)
+
+		st, err := mount.Statx(dirname, StatxBasicStats, 0)
+		assert.NoError(t, err)
+		require.NotNil(t, st)
+		assert.Equal(t, uint16(0755), st.Mode&0777)
+		assert.Equal(t, uint16(syscall.S_IFDIR), st.Mode&syscall.S_IFMT)
+		assert.NotEqual(t, uint64(0), st.Inode)
+	})
+
+	t.Run("statFile", func(t *testing.T) {
+		fname := "statme.txt"
+		f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0640)
+		require.NoError(t, err)
+		_, err = f.Write([]byte("abc"))
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+		defer os.Remove(CephMountTest + fname)
+
+		st, err := mount.Statx(fname, StatxBasicStats|StatxBtime, 0)
+		assert.NoError(t, err)
+		require.NotNil(t, st)
+		assert.EqualValues(t, 3, st.Size)
+		assert.EqualValues(t, 1, st.Nlink)
+		assert.Equal(t, uint16(0640), st.Mode&0777)
+		assert.NotEqual(t, StatxMask(0), st.Mask&StatxBtime)
+		assert.NotEqual(t, int64(0), st.Btime.Sec)
+	})
+
+	t.Run("symlinkNofollow", func(t *testing.T) {
+		lname := "statme.link"
+		err := os.Symlink("nowhere", CephMountTest+lname)
+		require.NoError(t, err)
+		defer os.Remove(CephMountTest + lname)
+
+		_, err = mount.Statx(lname, StatxBasicStats, 0)
+		assert.Error(t, err)
+
+		st, err := mount.Statx(lname, StatxBasicStats, AtSymlinkNofollow)
+		assert.NoError(t, err)
+		require.NotNil(t, st)
+		assert.EqualValues(t, len("nowhere"), st.Size)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		st, err := mount.Statx("does.not.exist", StatxBasicStats, 0)
+		assert.Error(t, err)
+		assert.Nil(t, st)
+	})
+}
+
+func TestSymlinkReadlink(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "symlink.target"
+	lname := "symlink.link"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer os.Remove(CephMountTest + fname)
+
+	err = mount.Symlink(fname, lname)
+	assert.NoError(t, err)
+	defer os.Remove(CephMountTest + lname)
+
+	target, err := os.Readlink(CephMountTest + lname)
+	assert.NoError(t, err)
+	assert.Equal(t, fname, target)
+
+	target, err = mount.Readlink(lname)
+	assert.NoError(t, err)
+	assert.Equal(t, fname, target)
+
+	// link already exists
+	err = mount.Symlink(fname, lname)
+	assert.Error(t, err)
+
+	// not a link
+	_, err = mount.Readlink(fname)
+	assert.Error(t, err)
+
+	t.Run("dangling", func(t *testing.T) {
+		dname := "symlink.dangling"
+		err := mount.Symlink("/no/such/file/here", dname)
+		assert.NoError(t, err)
+		defer os.Remove(CephMountTest + dname)
+
+		target, err := mount.Readlink(dname)
+		assert.NoError(t, err)
+		assert.Equal(t, "/no/such/file/here", target)
+	})
+}
+
+func TestLink(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "hardlink.target"
+	lname := "hardlink.link"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("shared data"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer os.Remove(CephMountTest + fname)
+
+	err = mount.Link(fname, lname)
+	assert.NoError(t, err)
+	defer os.Remove(CephMountTest + lname)
+
+	st1, err := mount.Statx(fname, StatxBasicStats, 0)
+	require.NoError(t, err)
+	st2, err := mount.Statx(lname, StatxBasicStats, 0)
+	require.NoError(t, err)
+	assert.Equal(t, st1.Inode, st2.Inode)
+	assert.EqualValues(t, 2, st2.Nlink)
+
+	// link already exists
+	err = mount.Link(fname, lname)
+	assert.Error(t, err)
+
+	// source missing
+	err = mount.Link("hardlink.missing", "hardlink.other")
+	assert.Error(t, err)
+
+	// directories can not be hard linked
+	require.NoError(t, mount.MakeDir("hardlink.dir", 0755))
+	defer mount.RemoveDir("hardlink.dir")
+	err = mount.Link("hardlink.dir", "hardlink.dirlink")
+	assert.Error(t, err)
+}
+
+func TestRename(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	t.Run("renameFile", func(t *testing.T) {
+		n1 := "rename.a"
+		n2 := "rename.b"
+		f, err := mount.Open(n1, os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		assert.NoError(t, f.Close())
+
+		err = mount.Rename(n1, n2)
+		assert.NoError(t, err)
+		defer os.Remove(CephMountTest + n2)
+
+		_, err = mount.Statx(n1, StatxBasicStats, 0)
+		assert.Error(t, err)
+		_, err = mount.Statx(n2, StatxBasicStats, 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("replaceExisting", func(t *testing.T) {
+		n1 := "rename.tmp"
+		n2 := "rename.final"
+		for _, n := range []string{n1, n2} {
+			f, err := mount.Open(n, os.O_WRONLY|os.O_CREATE, 0644)
+			require.NoError(t, err)
+			_, err = f.Write([]byte(n))
+			assert.NoError(t, err)
+			assert.NoError(t, f.Close())
+		}
+		defer os.Remove(CephMountTest + n2)
+
+		err := mount.Rename(n1, n2)
+		assert.NoError(t, err)
+
+		f, err := mount.Open(n2, os.O_RDONLY, 0)
+		require.NoError(t, err)
+		defer f.Close()
+		buf := make([]byte, 64)
+		n, err := f.Read(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, n1, string(buf[:n]))
+	})
+
+	t.Run("renameDir", func(t *testing.T) {
+		require.NoError(t, mount.MakeDir("rename.dir1", 0755))
+		err := mount.Rename("rename.dir1", "rename.dir2")
+		assert.NoError(t, err)
+		assert.NoError(t, mount.RemoveDir("rename.dir2"))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		err := mount.Rename("rename.missing", "rename.other")
+		assert.Error(t, err)
+	})
+}
+
+func TestUnlink(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	t.Run("unlinkFile", func(t *testing.T) {
+		fname := "unlink.txt"
+		f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		assert.NoError(t, f.Close())
+
+		err = mount.Unlink(fname)
+		assert.NoError(t, err)
+
+		_, err = os.Stat(CephMountTest + fname)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("unlinkSymlink", func(t *testing.T) {
+		fname := "unlink.target"
+		lname := "unlink.link"
+		f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		assert.NoError(t, f.Close())
+		defer mount.Unlink(fname)
+		require.NoError(t, mount.Symlink(fname, lname))
+
+		err = mount.Unlink(lname)
+		assert.NoError(t, err)
+		// the target is left alone
+		_, err = mount.Statx(fname, StatxBasicStats, 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unlinkMissing", func(t *testing.T) {
+		err := mount.Unlink("unlink.missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("unlinkDir", func(t *testing.T) {
+		require.NoError(t, mount.MakeDir("unlink.dir", 0755))
+		defer mount.RemoveDir("unlink.dir")
+		err := mount.Unlink("unlink.dir")
+		assert.Error(t, err)
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "truncate.txt"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("0123456789"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	err = mount.Truncate(fname, 4)
+	assert.NoError(t, err)
+	st, err := mount.Statx(fname, StatxSize, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, st.Size)
+
+	err = mount.Truncate(fname, 1024)
+	assert.NoError(t, err)
+	st, err = mount.Statx(fname, StatxSize, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1024, st.Size)
+
+	err = mount.Truncate("truncate.missing", 0)
+	assert.Error(t, err)
+}
+
+func TestMknod(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	t.Run("fifo", func(t *testing.T) {
+		fname := "mknod.fifo"
+		err := mount.Mknod(fname, syscall.S_IFIFO|0640, 0)
+		assert.NoError(t, err)
+		defer mount.Unlink(fname)
+
+		st, err := mount.Statx(fname, StatxBasicStats, AtSymlinkNofollow)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(syscall.S_IFIFO), st.Mode&syscall.S_IFMT)
+		assert.Equal(t, uint16(0640), st.Mode&0777)
+	})
+
+	t.Run("regular", func(t *testing.T) {
+		fname := "mknod.reg"
+		err := mount.Mknod(fname, syscall.S_IFREG|0600, 0)
+		assert.NoError(t, err)
+		defer mount.Unlink(fname)
+
+		st, err := mount.Statx(fname, StatxBasicStats, 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(syscall.S_IFREG), st.Mode&syscall.S_IFMT)
+		assert.EqualValues(t, 0, st.Size)
+	})
+
+	t.Run("charDevice", func(t *testing.T) {
+		fname := "mknod.chr"
+		// the same device numbers as /dev/null
+		rdev := uint64(1<<8 | 3)
+		err := mount.Mknod(fname, syscall.S_IFCHR|0666, rdev)
+		assert.NoError(t, err)
+		defer mount.Unlink(fname)
+
+		st, err := mount.Statx(fname, StatxBasicStats, 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint16(syscall.S_IFCHR), st.Mode&syscall.S_IFMT)
+		assert.Equal(t, rdev, st.Rdev)
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		fname := "mknod.exists"
+		require.NoError(t, mount.Mknod(fname, syscall.S_IFIFO|0600, 0))
+		defer mount.Unlink(fname)
+
+		err := mount.Mknod(fname, syscall.S_IFIFO|0600, 0)
+		assert.Error(t, err)
+	})
+}
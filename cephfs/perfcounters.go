@@ -0,0 +1,77 @@
+package cephfs
+
+import (
+	"encoding/json"
+)
+
+// PerfAverage is the value of a perf counter that tracks the average of a
+// quantity, typically a latency, over a number of events.
+type PerfAverage struct {
+	// Count is the number of events.
+	Count uint64 `json:"avgcount"`
+	// Sum is the sum of the quantity over all the events. Latencies are
+	// given in seconds.
+	Sum float64 `json:"sum"`
+	// AvgTime is the average time of the events in seconds, if the
+	// counter tracks a latency.
+	AvgTime float64 `json:"avgtime"`
+}
+
+// PerfCounters holds the perf counters of the client, grouped by the
+// subsystem they belong to. Notable subsystems are "client", with the
+// latencies of metadata requests ("reply", "lat") and of reads and writes
+// ("rdlat", "wrlat"), "objecter", with the counts of the operations sent to
+// the OSDs ("op", "op_r", "op_w"), and "objectcacher-libcephfs", with the
+// hits and misses of the object cache ("cache_ops_hit", "cache_ops_miss").
+// The set of counters is defined by Ceph and varies between releases.
+type PerfCounters struct {
+	// Values holds the counters and gauges with a single value.
+	Values map[string]map[string]float64
+	// Averages holds the counters that track an average.
+	Averages map[string]map[string]PerfAverage
+}
+
+// parsePerfDump parses the output of the "perf dump" admin socket command.
+func parsePerfDump(buf []byte) (*PerfCounters, error) {
+	var dump map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &dump); err != nil {
+		return nil, err
+	}
+	pc := &PerfCounters{
+		Values:   map[string]map[string]float64{},
+		Averages: map[string]map[string]PerfAverage{},
+	}
+	for subsys, counters := range dump {
+		for name, raw := range counters {
+			var value float64
+			if err := json.Unmarshal(raw, &value); err == nil {
+				if pc.Values[subsys] == nil {
+					pc.Values[subsys] = map[string]float64{}
+				}
+				pc.Values[subsys][name] = value
+				continue
+			}
+			var avg PerfAverage
+			if err := json.Unmarshal(raw, &avg); err != nil {
+				return nil, err
+			}
+			if pc.Averages[subsys] == nil {
+				pc.Averages[subsys] = map[string]PerfAverage{}
+			}
+			pc.Averages[subsys][name] = avg
+		}
+	}
+	return pc, nil
+}
+
+// GetPerfCounters returns the current values of the perf counters of the
+// client, using the "perf dump" command of the client's admin socket.
+// The counters are cumulative since the client was created, so rates have
+// to be computed by the caller from successive samples.
+func (mount *MountInfo) GetPerfCounters() (*PerfCounters, error) {
+	buf, err := mount.adminSocketPrefixCommand("perf dump")
+	if err != nil {
+		return nil, err
+	}
+	return parsePerfDump(buf)
+}
@@ -0,0 +1,55 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePerfDump(t *testing.T) {
+	pc, err := parsePerfDump([]byte(`{
+		"client": {
+			"reply": {"avgcount": 4, "sum": 0.02, "avgtime": 0.005},
+			"trim": 2
+		},
+		"objecter": {"op": 12, "op_r": 5, "op_w": 7}
+	}`))
+	require.NoError(t, err)
+	require.NotNil(t, pc)
+	assert.Equal(t, map[string]map[string]float64{
+		"client":   {"trim": 2},
+		"objecter": {"op": 12, "op_r": 5, "op_w": 7},
+	}, pc.Values)
+	assert.Equal(t, map[string]map[string]PerfAverage{
+		"client": {"reply": {Count: 4, Sum: 0.02, AvgTime: 0.005}},
+	}, pc.Averages)
+
+	_, err = parsePerfDump([]byte(`{"client": {"bad": "value"}}`))
+	assert.Error(t, err)
+	_, err = parsePerfDump([]byte(`[]`))
+	assert.Error(t, err)
+}
+
+func TestGetPerfCounters(t *testing.T) {
+	mount := fsConnectWithAdminSocket(t)
+	defer mount.Unmount()
+
+	fname := "TestGetPerfCounters.txt"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("perf"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Fsync(SyncAll))
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	pc, err := mount.GetPerfCounters()
+	require.NoError(t, err)
+	require.NotNil(t, pc)
+	assert.Contains(t, pc.Values, "objecter")
+	assert.True(t, pc.Values["objecter"]["op_w"] > 0)
+	require.Contains(t, pc.Averages, "client")
+	assert.True(t, pc.Averages["client"]["reply"].Count > 0)
+}
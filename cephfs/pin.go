@@ -0,0 +1,83 @@
+package cephfs
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	dirPinXattr            = "ceph.dir.pin"
+	dirPinRandomXattr      = "ceph.dir.pin.random"
+	dirPinDistributedXattr = "ceph.dir.pin.distributed"
+)
+
+// NoPin is the rank that removes the export pin of a directory.
+const NoPin = -1
+
+// SetDirPin pins the directory tree at the given path to the MDS rank, so
+// that the metadata of the tree is served by that rank in a file system with
+// multiple active MDS daemons. Setting the rank to NoPin removes the pin and
+// the tree inherits the pin of its parent.
+func (mount *MountInfo) SetDirPin(path string, rank int) error {
+	if rank < NoPin {
+		return ErrInvalid
+	}
+	return mount.SetXattr(path, dirPinXattr,
+		[]byte(strconv.Itoa(rank)), XattrDefault)
+}
+
+// GetDirPin returns the MDS rank the directory at the given path is pinned
+// to, or NoPin if the directory has no pin of its own.
+func (mount *MountInfo) GetDirPin(path string) (int, error) {
+	value, err := mount.GetXattr(path, dirPinXattr)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(value)))
+}
+
+// SetDirRandomPin sets the probability, between 0 and 1, with which each
+// directory below the directory at the given path is pinned to a randomly
+// chosen MDS rank. These ephemeral pins spread a large tree over all the
+// ranks without pinning every directory explicitly. Zero disables random
+// pinning. Ephemeral pins require Ceph Octopus or newer.
+func (mount *MountInfo) SetDirRandomPin(path string, probability float64) error {
+	if probability < 0 || probability > 1 {
+		return ErrInvalid
+	}
+	return mount.SetXattr(path, dirPinRandomXattr,
+		[]byte(strconv.FormatFloat(probability, 'f', -1, 64)), XattrDefault)
+}
+
+// GetDirRandomPin returns the random pin probability set on the directory
+// at the given path.
+func (mount *MountInfo) GetDirRandomPin(path string) (float64, error) {
+	value, err := mount.GetXattr(path, dirPinRandomXattr)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(strings.TrimSpace(string(value)), 64)
+}
+
+// SetDirDistributedPin enables or disables distributed pinning of the
+// directory at the given path. With distributed pinning the immediate
+// subdirectories of the directory are ephemerally pinned to ranks chosen by
+// hashing their inode numbers, spreading them evenly over all the ranks.
+// Ephemeral pins require Ceph Octopus or newer.
+func (mount *MountInfo) SetDirDistributedPin(path string, enable bool) error {
+	value := "0"
+	if enable {
+		value = "1"
+	}
+	return mount.SetXattr(path, dirPinDistributedXattr, []byte(value), XattrDefault)
+}
+
+// GetDirDistributedPin returns true if distributed pinning is enabled on
+// the directory at the given path.
+func (mount *MountInfo) GetDirDistributedPin(path string) (bool, error) {
+	value, err := mount.GetXattr(path, dirPinDistributedXattr)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(strings.TrimSpace(string(value)))
+}
@@ -0,0 +1,46 @@
+// +build !luminous,!mimic,!nautilus
+
+package cephfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirEphemeralPins(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/direphemeralpin"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveDir(dir)
+
+	t.Run("random", func(t *testing.T) {
+		assert.NoError(t, mount.SetDirRandomPin(dir, 0.01))
+		p, err := mount.GetDirRandomPin(dir)
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.01, p, 0.0001)
+
+		assert.NoError(t, mount.SetDirRandomPin(dir, 0))
+		p, err = mount.GetDirRandomPin(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, p)
+
+		assert.Equal(t, ErrInvalid, mount.SetDirRandomPin(dir, 1.5))
+		assert.Equal(t, ErrInvalid, mount.SetDirRandomPin(dir, -0.1))
+	})
+
+	t.Run("distributed", func(t *testing.T) {
+		assert.NoError(t, mount.SetDirDistributedPin(dir, true))
+		enabled, err := mount.GetDirDistributedPin(dir)
+		assert.NoError(t, err)
+		assert.True(t, enabled)
+
+		assert.NoError(t, mount.SetDirDistributedPin(dir, false))
+		enabled, err = mount.GetDirDistributedPin(dir)
+		assert.NoError(t, err)
+		assert.False(t, enabled)
+	})
+}
@@ -0,0 +1,33 @@
+package cephfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirPin(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/dirpin"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveDir(dir)
+
+	rank, err := mount.GetDirPin(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, NoPin, rank)
+
+	assert.NoError(t, mount.SetDirPin(dir, 0))
+	rank, err = mount.GetDirPin(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, rank)
+
+	assert.NoError(t, mount.SetDirPin(dir, NoPin))
+	rank, err = mount.GetDirPin(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, NoPin, rank)
+
+	assert.Equal(t, ErrInvalid, mount.SetDirPin(dir, -2))
+}
@@ -0,0 +1,105 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// getPoolName calls one of the functions that copy a pool name into a
+// buffer. The length of the name is queried first, using an empty buffer,
+// and the call is retried if the name changed size in between.
+func getPoolName(f func(buf *C.char, size C.size_t) C.int) (string, error) {
+	ret := f(nil, 0)
+	for {
+		if ret < 0 {
+			return "", getError(ret)
+		}
+		size := int(ret)
+		if size == 0 {
+			return "", nil
+		}
+		buf := make([]byte, size)
+		ret = f((*C.char)(unsafe.Pointer(&buf[0])), C.size_t(size))
+		if ret == -C.ERANGE {
+			ret = f(nil, 0)
+			continue
+		}
+		if ret < 0 {
+			return "", getError(ret)
+		}
+		return string(buf[:ret]), nil
+	}
+}
+
+// GetPool returns the id of the RADOS pool that stores the data of the
+// open file.
+//
+// Implements:
+//  int ceph_get_file_pool(struct ceph_mount_info *cmount, int fh);
+func (f *File) GetPool() (int, error) {
+	if err := f.rlock(); err != nil {
+		return 0, err
+	}
+	defer f.mu.RUnlock()
+
+	ret := C.ceph_get_file_pool(f.mount.mount, f.fd)
+	if ret < 0 {
+		return 0, getError(ret)
+	}
+	return int(ret), nil
+}
+
+// GetPoolName returns the name of the RADOS pool that stores the data of
+// the open file.
+//
+// Implements:
+//  int ceph_get_file_pool_name(struct ceph_mount_info *cmount, int fh, char *buf, size_t buflen);
+func (f *File) GetPoolName() (string, error) {
+	if err := f.rlock(); err != nil {
+		return "", err
+	}
+	defer f.mu.RUnlock()
+
+	return getPoolName(func(buf *C.char, size C.size_t) C.int {
+		return C.ceph_get_file_pool_name(f.mount.mount, f.fd, buf, size)
+	})
+}
+
+// GetPathPool returns the id of the RADOS pool that stores the data of the
+// file at the given path.
+//
+// Implements:
+//  int ceph_get_path_pool(struct ceph_mount_info *cmount, const char *path);
+func (mount *MountInfo) GetPathPool(path string) (int, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	ret := C.ceph_get_path_pool(mount.mount, cPath)
+	if ret < 0 {
+		return 0, getError(ret)
+	}
+	return int(ret), nil
+}
+
+// GetPathPoolName returns the name of the RADOS pool that stores the data
+// of the file at the given path.
+//
+// Implements:
+//  int ceph_get_path_pool_name(struct ceph_mount_info *cmount, const char *path, char *buf,
+//                              size_t buflen);
+func (mount *MountInfo) GetPathPoolName(path string) (string, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	return getPoolName(func(buf *C.char, size C.size_t) C.int {
+		return C.ceph_get_path_pool_name(mount.mount, cPath, buf, size)
+	})
+}
@@ -0,0 +1,52 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilePool(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestFilePool.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+
+	conn := radosConnect(t)
+	defer conn.Shutdown()
+	poolID, err := conn.GetPoolByName("cephfs_data")
+	require.NoError(t, err)
+
+	t.Run("openFile", func(t *testing.T) {
+		id, err := f.GetPool()
+		assert.NoError(t, err)
+		assert.EqualValues(t, poolID, id)
+
+		name, err := f.GetPoolName()
+		assert.NoError(t, err)
+		assert.Equal(t, "cephfs_data", name)
+	})
+
+	t.Run("path", func(t *testing.T) {
+		id, err := mount.GetPathPool(fname)
+		assert.NoError(t, err)
+		assert.EqualValues(t, poolID, id)
+
+		name, err := mount.GetPathPoolName(fname)
+		assert.NoError(t, err)
+		assert.Equal(t, "cephfs_data", name)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := mount.GetPathPool("TestFilePool.missing")
+		assert.Error(t, err)
+		_, err = mount.GetPathPoolName("TestFilePool.missing")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,149 @@
+package cephfs
+
+/*
+#include <errno.h>
+*/
+import "C"
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	quotaMaxBytesXattr = "ceph.quota.max_bytes"
+	quotaMaxFilesXattr = "ceph.quota.max_files"
+)
+
+var errNoData = CephFSError(-C.ENODATA)
+
+// Quota describes the limits placed on a directory tree. A limit of zero
+// means that the corresponding quantity is not limited.
+type Quota struct {
+	// MaxBytes is the limit on the number of bytes stored below the
+	// directory.
+	MaxBytes uint64
+	// MaxFiles is the limit on the number of files and directories below
+	// the directory.
+	MaxFiles uint64
+}
+
+// SetQuota sets the maximum number of bytes and files allowed below the
+// directory at the given path. Passing zero for either value removes the
+// corresponding limit.
+// Note that CephFS quotas are enforced cooperatively by the clients and
+// may be exceeded briefly before writes are stopped.
+func (mount *MountInfo) SetQuota(path string, maxBytes, maxFiles uint64) error {
+	err := mount.SetXattr(path, quotaMaxBytesXattr,
+		[]byte(strconv.FormatUint(maxBytes, 10)), XattrDefault)
+	if err != nil {
+		return err
+	}
+	return mount.SetXattr(path, quotaMaxFilesXattr,
+		[]byte(strconv.FormatUint(maxFiles, 10)), XattrDefault)
+}
+
+// getQuotaValue returns the numeric value of a quota xattr. Quotas that
+// have never been set are reported as zero (no limit).
+func (mount *MountInfo) getQuotaValue(path, name string) (uint64, error) {
+	value, err := mount.GetXattr(path, name)
+	if err == errNoData {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(value)), 10, 64)
+}
+
+// GetQuota returns the quota limits set on the directory at the given path.
+// Only limits set on the directory itself are returned, limits inherited from
+// parent directories are not taken into account.
+func (mount *MountInfo) GetQuota(path string) (*Quota, error) {
+	maxBytes, err := mount.getQuotaValue(path, quotaMaxBytesXattr)
+	if err != nil {
+		return nil, err
+	}
+	maxFiles, err := mount.getQuotaValue(path, quotaMaxFilesXattr)
+	if err != nil {
+		return nil, err
+	}
+	return &Quota{MaxBytes: maxBytes, MaxFiles: maxFiles}, nil
+}
+
+// quotaBlocks returns the number of blocks of the given size needed to hold
+// n bytes.
+func quotaBlocks(n uint64, blockSize int64) uint64 {
+	if blockSize <= 0 {
+		return n
+	}
+	bs := uint64(blockSize)
+	return (n + bs - 1) / bs
+}
+
+// minUint64 returns the smaller of a and b.
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// StatFSForPath returns file system statistics for the file or directory at
+// the given path that take the quotas of the directory tree containing it
+// into account, in the same way as the kernel client does for df. If a quota
+// on the number of bytes applies to the path, the size of the file system
+// is reported as the limit of the closest quota and the used space as the
+// recursive size of the directory it is set on. A quota on the number of
+// files is reflected in the inode counts in the same way. The free counts
+// never exceed those of the file system itself. Without quotas the result
+// is the same as that of StatFS.
+func (mount *MountInfo) StatFSForPath(p string) (*CephStatVFS, error) {
+	stat, err := mount.StatFS(p)
+	if err != nil {
+		return nil, err
+	}
+	if !path.IsAbs(p) {
+		p = path.Join(mount.CurrentDir(), p)
+	}
+
+	bytesDone, filesDone := false, false
+	for dir := path.Clean(p); !(bytesDone && filesDone); dir = path.Dir(dir) {
+		quota, err := mount.GetQuota(dir)
+		if err != nil {
+			return nil, err
+		}
+		if (!bytesDone && quota.MaxBytes > 0) || (!filesDone && quota.MaxFiles > 0) {
+			stats, err := mount.GetDirStats(dir)
+			if err != nil {
+				return nil, err
+			}
+			if !bytesDone && quota.MaxBytes > 0 {
+				blocks := quotaBlocks(quota.MaxBytes, stat.Frsize)
+				used := quotaBlocks(stats.RBytes, stat.Frsize)
+				free := uint64(0)
+				if used < blocks {
+					free = blocks - used
+				}
+				stat.Blocks = blocks
+				stat.Bfree = minUint64(free, stat.Bfree)
+				stat.Bavail = minUint64(free, stat.Bavail)
+				bytesDone = true
+			}
+			if !filesDone && quota.MaxFiles > 0 {
+				free := uint64(0)
+				if stats.REntries < quota.MaxFiles {
+					free = quota.MaxFiles - stats.REntries
+				}
+				stat.Files = quota.MaxFiles
+				stat.Ffree = minUint64(free, stat.Ffree)
+				stat.Favail = minUint64(free, stat.Favail)
+				filesDone = true
+			}
+		}
+		if dir == "/" {
+			break
+		}
+	}
+	return stat, nil
+}
@@ -0,0 +1,87 @@
+package cephfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuota(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/quota"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveDir(dir)
+
+	q, err := mount.GetQuota(dir)
+	assert.NoError(t, err)
+	require.NotNil(t, q)
+	assert.Equal(t, Quota{}, *q)
+
+	err = mount.SetQuota(dir, 100*1024*1024, 500)
+	assert.NoError(t, err)
+	q, err = mount.GetQuota(dir)
+	assert.NoError(t, err)
+	require.NotNil(t, q)
+	assert.EqualValues(t, 100*1024*1024, q.MaxBytes)
+	assert.EqualValues(t, 500, q.MaxFiles)
+
+	// the raw vxattr reflects the typed value
+	b, err := mount.GetXattr(dir, "ceph.quota.max_files")
+	assert.NoError(t, err)
+	assert.Equal(t, "500", string(b))
+
+	// zero removes the limits
+	err = mount.SetQuota(dir, 0, 0)
+	assert.NoError(t, err)
+	q, err = mount.GetQuota(dir)
+	assert.NoError(t, err)
+	require.NotNil(t, q)
+	assert.Equal(t, Quota{}, *q)
+
+	_, err = mount.GetQuota("/quota.missing")
+	assert.Error(t, err)
+	err = mount.SetQuota("/quota.missing", 1, 1)
+	assert.Error(t, err)
+}
+
+func TestStatFSForPath(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/statfsquota"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveDir(dir)
+	require.NoError(t, mount.MakeDir(dir+"/sub", 0755))
+	defer mount.RemoveDir(dir + "/sub")
+
+	fsStat, err := mount.StatFS("/")
+	require.NoError(t, err)
+
+	// without a quota the file system statistics are returned
+	st, err := mount.StatFSForPath(dir + "/sub")
+	assert.NoError(t, err)
+	require.NotNil(t, st)
+	assert.Equal(t, fsStat.Blocks, st.Blocks)
+	assert.Equal(t, fsStat.Files, st.Files)
+
+	const maxBytes = 64 * 1024 * 1024
+	require.NoError(t, mount.SetQuota(dir, maxBytes, 1000))
+	defer mount.SetQuota(dir, 0, 0)
+
+	for _, p := range []string{dir, dir + "/sub", "statfsquota/sub"} {
+		st, err = mount.StatFSForPath(p)
+		assert.NoError(t, err)
+		require.NotNil(t, st)
+		assert.Equal(t, uint64(maxBytes), st.Blocks*uint64(st.Frsize))
+		assert.True(t, st.Bfree <= st.Blocks)
+		assert.True(t, st.Bavail <= st.Bfree)
+		assert.EqualValues(t, 1000, st.Files)
+		assert.True(t, st.Ffree <= st.Files)
+	}
+
+	_, err = mount.StatFSForPath("/statfsquota.missing")
+	assert.Error(t, err)
+}
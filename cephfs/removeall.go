@@ -0,0 +1,64 @@
+package cephfs
+
+import (
+	"path"
+	"syscall"
+)
+
+// RemoveAll removes path and any children it contains. It removes
+// everything it can but returns the first error it encounters. If the path
+// does not exist, RemoveAll returns nil, as os.RemoveAll does. Symbolic
+// links are removed, not followed.
+func (mount *MountInfo) RemoveAll(p string) error {
+	st, err := mount.Statx(p, StatxMode, AtSymlinkNofollow)
+	if err == ErrNotExist {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if st.Mode&syscall.S_IFMT != syscall.S_IFDIR {
+		err = mount.Unlink(p)
+		if err == ErrNotExist {
+			err = nil
+		}
+		return err
+	}
+
+	for {
+		names, err := mount.listDir(p)
+		if err == ErrNotExist {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		var firstErr error
+		for _, name := range names {
+			if err := mount.RemoveAll(path.Join(p, name)); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		if firstErr != nil {
+			return firstErr
+		}
+
+		err = mount.RemoveDir(p)
+		switch {
+		case err == nil, err == ErrNotExist:
+			return nil
+		case err == ErrNotEmpty && len(names) > 0:
+			// entries were added while removing, try again
+			continue
+		}
+		return err
+	}
+}
+
+// listDir returns the names of the entries of the directory at path.
+func (mount *MountInfo) listDir(p string) ([]string, error) {
+	dir, err := mount.OpenDir(p)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	return dir.List()
+}
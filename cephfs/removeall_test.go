@@ -0,0 +1,57 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveAll(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	t.Run("tree", func(t *testing.T) {
+		root := "TestRemoveAll"
+		require.NoError(t, mount.MakeDirs(root+"/a/b", 0755))
+		require.NoError(t, mount.MakeDir(root+"/c", 0755))
+		for _, fname := range []string{root + "/f1", root + "/a/f2", root + "/a/b/f3"} {
+			f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+			require.NoError(t, err)
+			assert.NoError(t, f.Close())
+		}
+		// the target of the link must survive
+		target := "TestRemoveAll.target"
+		f, err := mount.Open(target, os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		assert.NoError(t, f.Close())
+		defer mount.Unlink(target)
+		require.NoError(t, mount.Symlink("../"+target, root+"/link"))
+
+		err = mount.RemoveAll(root)
+		assert.NoError(t, err)
+
+		_, err = mount.Statx(root, StatxBasicStats, 0)
+		assert.Error(t, err)
+		_, err = mount.Statx(target, StatxBasicStats, 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		fname := "TestRemoveAll.file"
+		f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		assert.NoError(t, f.Close())
+
+		err = mount.RemoveAll(fname)
+		assert.NoError(t, err)
+		_, err = mount.Statx(fname, StatxBasicStats, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		err := mount.RemoveAll("TestRemoveAll.missing")
+		assert.NoError(t, err)
+	})
+}
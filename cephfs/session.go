@@ -0,0 +1,98 @@
+package cephfs
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ClientMetadata is the metadata a client reports to the MDS when it opens
+// a session. Not every client reports every field.
+type ClientMetadata struct {
+	EntityID    string `json:"entity_id"`
+	Hostname    string `json:"hostname"`
+	Root        string `json:"root"`
+	MountPoint  string `json:"mount_point"`
+	CephVersion string `json:"ceph_version"`
+	// Fields holds all the metadata reported by the client, including the
+	// fields above and any custom metadata set with SetClientMetadata.
+	Fields map[string]string `json:"-"`
+}
+
+// UnmarshalJSON decodes the client metadata of a session, keeping all the
+// string valued fields in Fields.
+func (md *ClientMetadata) UnmarshalJSON(buf []byte) error {
+	type knownFields ClientMetadata
+	if err := json.Unmarshal(buf, (*knownFields)(md)); err != nil {
+		return err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(buf, &fields); err != nil {
+		return err
+	}
+	md.Fields = map[string]string{}
+	for k, v := range fields {
+		if s, ok := v.(string); ok {
+			md.Fields[k] = s
+		}
+	}
+	return nil
+}
+
+// ClientSession describes a session between a client and an MDS.
+type ClientSession struct {
+	// ID is the global id of the client, as used by EvictClient.
+	ID int64 `json:"id"`
+	// State of the session, for example "open" or "stale".
+	State string `json:"state"`
+	// NumCaps is the number of capabilities the client holds.
+	NumCaps int64 `json:"num_caps"`
+	// NumLeases is the number of dentry leases the client holds.
+	NumLeases int64 `json:"num_leases"`
+	// Inst is the entity name and address of the client.
+	Inst           string         `json:"inst"`
+	ClientMetadata ClientMetadata `json:"client_metadata"`
+}
+
+// mdsJSONCommand marshals args to JSON and sends the resulting command to
+// the MDS identified by mdsSpec. If the command fails the status message of
+// the MDS, if any, is included in the error.
+func (mount *MountInfo) mdsJSONCommand(mdsSpec string, args interface{}) ([]byte, error) {
+	cmd, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	buf, info, err := mount.MdsCommand(mdsSpec, [][]byte{cmd})
+	if err != nil && info != "" {
+		return nil, fmt.Errorf("%v: %s", err, info)
+	}
+	return buf, err
+}
+
+// ListClientSessions returns the client sessions of the MDS identified by
+// mdsSpec, as reported by the "session ls" command (also known as
+// "client ls").
+func (mount *MountInfo) ListClientSessions(mdsSpec string) ([]ClientSession, error) {
+	buf, err := mount.mdsJSONCommand(mdsSpec, map[string]interface{}{
+		"prefix": "session ls",
+	})
+	if err != nil {
+		return nil, err
+	}
+	sessions := []ClientSession{}
+	if err := json.Unmarshal(buf, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// EvictClient evicts the client with the given global id from the MDS
+// identified by mdsSpec. The client's session is closed and, depending on
+// the cluster configuration, the client is blacklisted so that it can no
+// longer access the file system.
+func (mount *MountInfo) EvictClient(mdsSpec string, id int64) error {
+	_, err := mount.mdsJSONCommand(mdsSpec, map[string]interface{}{
+		"prefix":  "session evict",
+		"filters": []string{fmt.Sprintf("id=%d", id)},
+	})
+	return err
+}
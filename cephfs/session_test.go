@@ -0,0 +1,118 @@
+package cephfs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TODO: fix hard-coded name mds (from ci container script)
+const testMdsName = "Z"
+
+func mountWithID(t *testing.T, id string) *MountInfo {
+	mount, err := CreateMountWithId(id)
+	require.NoError(t, err)
+	require.NoError(t, mount.ReadDefaultConfigFile())
+	require.NoError(t, mount.Mount())
+	return mount
+}
+
+func findSession(sessions []ClientSession, entityID string) *ClientSession {
+	for i := range sessions {
+		if sessions[i].ClientMetadata.EntityID == entityID {
+			return &sessions[i]
+		}
+	}
+	return nil
+}
+
+func TestListClientSessions(t *testing.T) {
+	mount := mountWithID(t, "sessionls")
+	defer mount.Unmount()
+
+	sessions, err := mount.ListClientSessions(testMdsName)
+	assert.NoError(t, err)
+	s := findSession(sessions, "sessionls")
+	require.NotNil(t, s)
+	assert.NotEqual(t, int64(0), s.ID)
+	assert.Equal(t, "open", s.State)
+	assert.Contains(t, s.Inst, "client.")
+	assert.NotEqual(t, "", s.ClientMetadata.Hostname)
+	assert.Equal(t, s.ClientMetadata.Hostname, s.ClientMetadata.Fields["hostname"])
+
+	_, err = mount.ListClientSessions("no-such-mds")
+	assert.Error(t, err)
+}
+
+func TestEvictClient(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	victim := mountWithID(t, "evictme")
+	defer func() {
+		// the evicted client can not unmount cleanly
+		assert.NoError(t, victim.AbortConn())
+		assert.NoError(t, victim.Release())
+	}()
+
+	sessions, err := mount.ListClientSessions(testMdsName)
+	require.NoError(t, err)
+	s := findSession(sessions, "evictme")
+	require.NotNil(t, s)
+
+	err = mount.EvictClient(testMdsName, s.ID)
+	assert.NoError(t, err)
+
+	sessions, err = mount.ListClientSessions(testMdsName)
+	require.NoError(t, err)
+	assert.Nil(t, findSession(sessions, "evictme"))
+}
+
+func TestClientMetadataFields(t *testing.T) {
+	var md ClientMetadata
+	err := json.Unmarshal([]byte(`{
+		"entity_id": "admin",
+		"hostname": "node1",
+		"app": "gateway",
+		"client_features": {"feature_bits": "0x3bff"}
+	}`), &md)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", md.EntityID)
+	assert.Equal(t, "node1", md.Hostname)
+	assert.Equal(t, map[string]string{
+		"entity_id": "admin",
+		"hostname":  "node1",
+		"app":       "gateway",
+	}, md.Fields)
+}
+
+func TestSetClientMetadata(t *testing.T) {
+	mount, err := CreateMountWithId("sessionmd")
+	require.NoError(t, err)
+	require.NoError(t, mount.ReadDefaultConfigFile())
+
+	assert.Equal(t, ErrInvalid, mount.SetClientMetadata(map[string]string{"a=b": "c"}))
+	assert.Equal(t, ErrInvalid, mount.SetClientMetadata(map[string]string{"a": "b,c"}))
+	assert.Equal(t, ErrInvalid, mount.SetClientMetadata(map[string]string{"": "c"}))
+
+	err = mount.SetClientMetadata(map[string]string{
+		"app": "go-ceph-test",
+		"pod": "pod-1",
+	})
+	require.NoError(t, err)
+	val, err := mount.GetConfigOption("client_metadata")
+	assert.NoError(t, err)
+	assert.Equal(t, "app=go-ceph-test,pod=pod-1", val)
+
+	require.NoError(t, mount.Mount())
+	defer mount.Unmount()
+
+	sessions, err := mount.ListClientSessions(testMdsName)
+	require.NoError(t, err)
+	s := findSession(sessions, "sessionmd")
+	require.NotNil(t, s)
+	assert.Equal(t, "go-ceph-test", s.ClientMetadata.Fields["app"])
+	assert.Equal(t, "pod-1", s.ClientMetadata.Fields["pod"])
+}
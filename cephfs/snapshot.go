@@ -0,0 +1,285 @@
+package cephfs
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// snapDirName is the name of the hidden virtual directory through which
+// CephFS exposes the snapshots of a directory. This is the default value of
+// the client_snapdir configuration option.
+const snapDirName = ".snap"
+
+func snapPath(dir, name string) string {
+	return path.Join(dir, snapDirName, name)
+}
+
+func validSnapName(name string) bool {
+	return name != "" && name != "." && name != ".." &&
+		!strings.Contains(name, "/")
+}
+
+// CreateSnapshot creates a snapshot, with the given name, of the directory
+// dir and everything below it. Snapshots are created by making a directory
+// within the special ".snap" directory, this function saves the caller from
+// having to manage that path.
+func (mount *MountInfo) CreateSnapshot(dir, name string) error {
+	if !validSnapName(name) {
+		return ErrInvalid
+	}
+	return mount.MakeDir(snapPath(dir, name), 0755)
+}
+
+// RemoveSnapshot removes the named snapshot of the directory dir.
+func (mount *MountInfo) RemoveSnapshot(dir, name string) error {
+	if !validSnapName(name) {
+		return ErrInvalid
+	}
+	return mount.RemoveDir(snapPath(dir, name))
+}
+
+// ListSnapshots returns the names of the snapshots visible within the
+// directory dir. In addition to the snapshots taken of dir itself this
+// includes snapshots of parent directories, which CephFS names using the
+// "_<snapshot name>_<parent inode>" convention.
+func (mount *MountInfo) ListSnapshots(dir string) ([]string, error) {
+	d, err := mount.OpenDir(path.Join(dir, snapDirName))
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+	return d.List()
+}
+
+// snapBtimeXattr is the virtual xattr holding the creation time of a
+// snapshot.
+const snapBtimeXattr = "ceph.snap.btime"
+
+// getSnapBtime returns the creation time of the snapshot at spath. Versions
+// of Ceph before Octopus do not provide the xattr, in which case the zero
+// Timespec is returned.
+func (mount *MountInfo) getSnapBtime(spath string) (Timespec, error) {
+	value, err := mount.GetXattr(spath, snapBtimeXattr)
+	if err == errNoData {
+		return Timespec{}, nil
+	} else if err != nil {
+		return Timespec{}, err
+	}
+	return parseRctime(string(value))
+}
+
+// SnapshotEntry describes a snapshot listed by ListSnapshotEntries.
+type SnapshotEntry struct {
+	Name string
+	// ID is the snapid of the snapshot.
+	ID uint64
+	// Created is the time the snapshot was taken. It is zero if the
+	// creation time is unknown.
+	Created Timespec
+}
+
+// ListSnapshotEntries returns the snapshots visible within the directory
+// dir, like ListSnapshots, along with their snapids and creation times. The
+// snapshots are ordered by snapid, which is the order in which they were
+// taken. The creation times are only known on Ceph Octopus or newer, with
+// older versions they are left as zero.
+func (mount *MountInfo) ListSnapshotEntries(dir string) ([]SnapshotEntry, error) {
+	sdir := path.Join(dir, snapDirName)
+	d, err := mount.OpenDir(sdir)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+
+	snaps := []SnapshotEntry{}
+	for {
+		entry, err := d.ReadDirPlus(StatxIno, AtNoAttrSync)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			break
+		}
+		if entry.Name() == "." || entry.Name() == ".." {
+			continue
+		}
+		created, err := mount.getSnapBtime(path.Join(sdir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, SnapshotEntry{
+			Name: entry.Name(),
+			// the statx of a file within a snapshot reports the snapid
+			// as its device
+			ID:      entry.Statx().Dev,
+			Created: created,
+		})
+	}
+	sort.Slice(snaps, func(i, j int) bool {
+		return snaps[i].ID < snaps[j].ID
+	})
+	return snaps, nil
+}
+
+// SnapshotPath returns the path at which the file or directory p, relative
+// to the directory dir, appears within the named snapshot of dir.
+func SnapshotPath(dir, name, p string) (string, error) {
+	if !validSnapName(name) {
+		return "", ErrInvalid
+	}
+	return path.Join(snapPath(dir, name), p), nil
+}
+
+// OpenSnapshotFile opens, read only, the file p, relative to the directory
+// dir, as it was when the named snapshot of dir was taken.
+func (mount *MountInfo) OpenSnapshotFile(dir, name, p string) (*File, error) {
+	spath, err := SnapshotPath(dir, name, p)
+	if err != nil {
+		return nil, err
+	}
+	return mount.Open(spath, os.O_RDONLY, 0)
+}
+
+// OpenSnapshotDir opens the directory p, relative to the directory dir, as
+// it was when the named snapshot of dir was taken.
+func (mount *MountInfo) OpenSnapshotDir(dir, name, p string) (*Directory, error) {
+	spath, err := SnapshotPath(dir, name, p)
+	if err != nil {
+		return nil, err
+	}
+	return mount.OpenDir(spath)
+}
+
+// SnapDiffType indicates how an entry differs between two snapshots.
+type SnapDiffType int
+
+const (
+	// SnapDiffAdded indicates the entry only exists in the newer snapshot.
+	SnapDiffAdded = SnapDiffType(iota + 1)
+	// SnapDiffRemoved indicates the entry only exists in the older
+	// snapshot.
+	SnapDiffRemoved
+	// SnapDiffModified indicates the entry exists in both snapshots but its
+	// contents or attributes differ, or it was replaced by another file of
+	// the same name.
+	SnapDiffModified
+)
+
+// SnapDiffEntry describes an entry that differs between two snapshots.
+type SnapDiffEntry struct {
+	// Path of the entry, relative to the snapshotted directory.
+	Path string
+	Type SnapDiffType
+	// IsDir is true if the entry is a directory in the snapshot it exists
+	// in, or in the newer snapshot if it exists in both.
+	IsDir bool
+}
+
+// DiffSnapshots compares two snapshots of the directory dir and returns the
+// entries that were added, removed or modified between the older snapshot
+// and the newer one, ordered by path. The entries below an added, removed
+// or replaced directory are not reported individually. A directory is
+// reported as modified only if its own attributes changed, and not merely
+// because of changes to the entries it contains.
+//
+// Both snapshots are walked in full, so the cost is proportional to the size
+// of the tree.
+func (mount *MountInfo) DiffSnapshots(dir, older, newer string) ([]SnapDiffEntry, error) {
+	oldRoot, err := SnapshotPath(dir, older, "")
+	if err != nil {
+		return nil, err
+	}
+	newRoot, err := SnapshotPath(dir, newer, "")
+	if err != nil {
+		return nil, err
+	}
+	diff := []SnapDiffEntry{}
+	if err := mount.diffDirs(oldRoot, newRoot, "", &diff); err != nil {
+		return nil, err
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Path < diff[j].Path
+	})
+	return diff, nil
+}
+
+// readDirStats returns the stat information of the entries of a directory,
+// excluding "." and "..", keyed by name.
+func (mount *MountInfo) readDirStats(p string) (map[string]*CephStatx, error) {
+	d, err := mount.OpenDir(p)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+
+	entries := map[string]*CephStatx{}
+	for {
+		entry, err := d.ReadDirPlus(StatxBasicStats, AtNoAttrSync)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return entries, nil
+		}
+		if entry.Name() == "." || entry.Name() == ".." {
+			continue
+		}
+		entries[entry.Name()] = entry.Statx()
+	}
+}
+
+func isDirMode(mode uint16) bool {
+	return uint32(mode)&syscall.S_IFMT == syscall.S_IFDIR
+}
+
+func statxModified(a, b *CephStatx) bool {
+	if a.Inode != b.Inode || a.Mode != b.Mode || a.Uid != b.Uid || a.Gid != b.Gid {
+		return true
+	}
+	if isDirMode(b.Mode) {
+		return false
+	}
+	return a.Size != b.Size || a.Mtime != b.Mtime || a.Ctime != b.Ctime
+}
+
+func (mount *MountInfo) diffDirs(oldDir, newDir, rel string, diff *[]SnapDiffEntry) error {
+	oldEntries, err := mount.readDirStats(path.Join(oldDir, rel))
+	if err != nil {
+		return err
+	}
+	newEntries, err := mount.readDirStats(path.Join(newDir, rel))
+	if err != nil {
+		return err
+	}
+
+	for name, ost := range oldEntries {
+		if _, found := newEntries[name]; !found {
+			*diff = append(*diff, SnapDiffEntry{
+				Path:  path.Join(rel, name),
+				Type:  SnapDiffRemoved,
+				IsDir: isDirMode(ost.Mode),
+			})
+		}
+	}
+	for name, nst := range newEntries {
+		p := path.Join(rel, name)
+		isDir := isDirMode(nst.Mode)
+		ost, found := oldEntries[name]
+		switch {
+		case !found:
+			*diff = append(*diff, SnapDiffEntry{Path: p, Type: SnapDiffAdded, IsDir: isDir})
+			continue
+		case statxModified(ost, nst):
+			*diff = append(*diff, SnapDiffEntry{Path: p, Type: SnapDiffModified, IsDir: isDir})
+		}
+		if isDir && isDirMode(ost.Mode) && ost.Inode == nst.Inode {
+			if err := mount.diffDirs(oldDir, newDir, p, diff); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// +build !luminous,!mimic,!nautilus
+
+package cephfs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSnapshotEntriesCreated(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/snapentriescreated"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveDir(dir)
+
+	start := time.Now().Unix()
+	for _, name := range []string{"first", "second"} {
+		require.NoError(t, mount.CreateSnapshot(dir, name))
+		defer mount.RemoveSnapshot(dir, name)
+	}
+
+	snaps, err := mount.ListSnapshotEntries(dir)
+	assert.NoError(t, err)
+	require.Len(t, snaps, 2)
+	for i := range snaps {
+		assert.GreaterOrEqual(t, snaps[i].Created.Sec, start-60)
+	}
+}
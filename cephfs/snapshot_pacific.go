@@ -0,0 +1,70 @@
+// +build !luminous,!mimic,!nautilus,!octopus
+//
+// Ceph Pacific is the first release that includes ceph_get_snap_info().
+
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// SnapInfo describes a snapshot of a directory.
+type SnapInfo struct {
+	// ID is the snapid of the snapshot. It is the id of the RADOS self
+	// managed snapshot of the objects of the files in the snapshot.
+	ID uint64
+	// Metadata holds the key-value pairs attached to the snapshot when it
+	// was created, if any.
+	Metadata map[string]string
+	// Created is the time the snapshot was taken.
+	Created Timespec
+}
+
+// GetSnapInfo returns information about the named snapshot of the
+// directory dir.
+//
+// Implements:
+//  int ceph_get_snap_info(struct ceph_mount_info *cmount, const char *path,
+//                         struct snap_info *snap_info);
+//  void ceph_free_snap_info_buffer(struct snap_info *snap_info);
+func (mount *MountInfo) GetSnapInfo(dir, name string) (*SnapInfo, error) {
+	if !validSnapName(name) {
+		return nil, ErrInvalid
+	}
+	spath := snapPath(dir, name)
+	cPath := C.CString(spath)
+	defer C.free(unsafe.Pointer(cPath))
+
+	var cInfo C.struct_snap_info
+	ret := C.ceph_get_snap_info(mount.mount, cPath, &cInfo)
+	if ret < 0 {
+		return nil, getError(ret)
+	}
+	defer C.ceph_free_snap_info_buffer(&cInfo)
+
+	info := &SnapInfo{
+		ID:       uint64(cInfo.id),
+		Metadata: map[string]string{},
+	}
+	if n := int(cInfo.nr_snap_metadata); n > 0 {
+		md := (*[1 << 20]C.struct_snap_metadata)(unsafe.Pointer(cInfo.snap_metadata))[:n:n]
+		for _, m := range md {
+			info.Metadata[C.GoString(m.key)] = C.GoString(m.value)
+		}
+	}
+
+	created, err := mount.getSnapBtime(spath)
+	if err != nil {
+		return nil, err
+	}
+	info.Created = created
+	return info, nil
+}
@@ -0,0 +1,52 @@
+// +build !luminous,!mimic,!nautilus,!octopus
+
+package cephfs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSnapInfo(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/snapinfo"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveDir(dir)
+
+	start := time.Now().Unix()
+	require.NoError(t, mount.CreateSnapshot(dir, "snap1"))
+	defer mount.RemoveSnapshot(dir, "snap1")
+	require.NoError(t, mount.CreateSnapshot(dir, "snap2"))
+	defer mount.RemoveSnapshot(dir, "snap2")
+
+	info1, err := mount.GetSnapInfo(dir, "snap1")
+	assert.NoError(t, err)
+	require.NotNil(t, info1)
+	assert.NotZero(t, info1.ID)
+	assert.Empty(t, info1.Metadata)
+	assert.GreaterOrEqual(t, info1.Created.Sec, start-60)
+
+	info2, err := mount.GetSnapInfo(dir, "snap2")
+	assert.NoError(t, err)
+	require.NotNil(t, info2)
+	// snap ids are allocated in increasing order
+	assert.Greater(t, info2.ID, info1.ID)
+
+	// the listing reports the same snapids and creation times
+	snaps, err := mount.ListSnapshotEntries(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []SnapshotEntry{
+		{Name: "snap1", ID: info1.ID, Created: info1.Created},
+		{Name: "snap2", ID: info2.ID, Created: info2.Created},
+	}, snaps)
+
+	_, err = mount.GetSnapInfo(dir, "nosuchsnap")
+	assert.Error(t, err)
+	_, err = mount.GetSnapInfo(dir, "")
+	assert.Equal(t, ErrInvalid, err)
+}
@@ -0,0 +1,196 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshots(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/snapshots"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveDir(dir)
+
+	fname := dir + "/data.txt"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("before"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	snaps, err := mount.ListSnapshots(dir)
+	assert.NoError(t, err)
+	assert.Len(t, snaps, 0)
+
+	err = mount.CreateSnapshot(dir, "snap1")
+	require.NoError(t, err)
+	err = mount.CreateSnapshot(dir, "snap2")
+	require.NoError(t, err)
+
+	snaps, err = mount.ListSnapshots(dir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"snap1", "snap2"}, snaps)
+
+	// the snapshot preserves the old contents
+	f, err = mount.Open(fname, os.O_WRONLY|os.O_TRUNC, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("after"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	f, err = mount.Open(dir+"/.snap/snap1/data.txt", os.O_RDONLY, 0)
+	require.NoError(t, err)
+	buf := make([]byte, 16)
+	n, err := f.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "before", string(buf[:n]))
+	assert.NoError(t, f.Close())
+
+	// duplicate names are rejected
+	err = mount.CreateSnapshot(dir, "snap1")
+	assert.Error(t, err)
+
+	assert.NoError(t, mount.RemoveSnapshot(dir, "snap1"))
+	assert.NoError(t, mount.RemoveSnapshot(dir, "snap2"))
+	snaps, err = mount.ListSnapshots(dir)
+	assert.NoError(t, err)
+	assert.Len(t, snaps, 0)
+
+	err = mount.RemoveSnapshot(dir, "snap1")
+	assert.Error(t, err)
+}
+
+func TestSnapshotInvalidNames(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	for _, name := range []string{"", ".", "..", "a/b"} {
+		assert.Error(t, mount.CreateSnapshot("/", name))
+		assert.Error(t, mount.RemoveSnapshot("/", name))
+	}
+
+	_, err := mount.ListSnapshots("/no.such.dir")
+	assert.Error(t, err)
+}
+
+func TestSnapshotFiles(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/snapfiles"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveAll(dir)
+
+	writeFile := func(name, data string) {
+		f, err := mount.Open(dir+"/"+name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(data))
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+	}
+	require.NoError(t, mount.MakeDir(dir+"/sub", 0755))
+	require.NoError(t, mount.MakeDir(dir+"/gone", 0755))
+	writeFile("same.txt", "same")
+	writeFile("changed.txt", "before")
+	writeFile("removed.txt", "removed")
+	writeFile("sub/inner.txt", "inner")
+
+	require.NoError(t, mount.CreateSnapshot(dir, "old"))
+	defer mount.RemoveSnapshot(dir, "old")
+
+	writeFile("changed.txt", "after!")
+	writeFile("added.txt", "added")
+	writeFile("sub/new.txt", "new")
+	assert.NoError(t, mount.Unlink(dir+"/removed.txt"))
+	assert.NoError(t, mount.RemoveDir(dir+"/gone"))
+
+	require.NoError(t, mount.CreateSnapshot(dir, "new"))
+	defer mount.RemoveSnapshot(dir, "new")
+
+	t.Run("path", func(t *testing.T) {
+		p, err := SnapshotPath(dir, "old", "sub/inner.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, dir+"/.snap/old/sub/inner.txt", p)
+		_, err = SnapshotPath(dir, "..", "x")
+		assert.Equal(t, ErrInvalid, err)
+	})
+
+	t.Run("openFile", func(t *testing.T) {
+		f, err := mount.OpenSnapshotFile(dir, "old", "changed.txt")
+		require.NoError(t, err)
+		defer f.Close()
+		buf := make([]byte, 16)
+		n, err := f.Read(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "before", string(buf[:n]))
+
+		_, err = mount.OpenSnapshotFile(dir, "old", "added.txt")
+		assert.Error(t, err)
+	})
+
+	t.Run("openDir", func(t *testing.T) {
+		d, err := mount.OpenSnapshotDir(dir, "old", "sub")
+		require.NoError(t, err)
+		defer d.Close()
+		names, err := d.List()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"inner.txt"}, names)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		diff, err := mount.DiffSnapshots(dir, "old", "new")
+		require.NoError(t, err)
+		assert.Equal(t, []SnapDiffEntry{
+			{Path: "added.txt", Type: SnapDiffAdded},
+			{Path: "changed.txt", Type: SnapDiffModified},
+			{Path: "gone", Type: SnapDiffRemoved, IsDir: true},
+			{Path: "removed.txt", Type: SnapDiffRemoved},
+			{Path: "sub/new.txt", Type: SnapDiffAdded},
+		}, diff)
+
+		diff, err = mount.DiffSnapshots(dir, "old", "old")
+		assert.NoError(t, err)
+		assert.Len(t, diff, 0)
+
+		_, err = mount.DiffSnapshots(dir, "old", "missing")
+		assert.Error(t, err)
+	})
+}
+
+func TestListSnapshotEntries(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/snapentries"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveDir(dir)
+
+	snaps, err := mount.ListSnapshotEntries(dir)
+	assert.NoError(t, err)
+	assert.Len(t, snaps, 0)
+
+	for _, name := range []string{"first", "second", "third"} {
+		require.NoError(t, mount.CreateSnapshot(dir, name))
+		defer mount.RemoveSnapshot(dir, name)
+	}
+
+	snaps, err = mount.ListSnapshotEntries(dir)
+	assert.NoError(t, err)
+	require.Len(t, snaps, 3)
+	for i, name := range []string{"first", "second", "third"} {
+		assert.Equal(t, name, snaps[i].Name)
+		assert.NotZero(t, snaps[i].ID)
+		if i > 0 {
+			assert.Greater(t, snaps[i].ID, snaps[i-1].ID)
+		}
+	}
+
+	_, err = mount.ListSnapshotEntries("/no.such.dir")
+	assert.Error(t, err)
+}
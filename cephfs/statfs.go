@@ -0,0 +1,71 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <sys/statvfs.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// CephStatVFS instances are returned from the StatFS call. It reports
+// file-system wide statistics.
+type CephStatVFS struct {
+	// Bsize reports the file system's block size.
+	Bsize int64
+	// Frsize reports the file system's fragment size.
+	Frsize int64
+	// Blocks reports the number of blocks in the file system.
+	Blocks uint64
+	// Bfree reports the number of free blocks.
+	Bfree uint64
+	// Bavail reports the number of free blocks for unprivileged users.
+	Bavail uint64
+	// Files reports the number of inodes in the file system.
+	Files uint64
+	// Ffree reports the number of free inodes.
+	Ffree uint64
+	// Favail reports the number of free inodes for unprivileged users.
+	Favail uint64
+	// Fsid reports the file system ID number.
+	Fsid int64
+	// Flag reports the file system mount flags.
+	Flag int64
+	// Namemax reports the maximum file name length.
+	Namemax int64
+}
+
+// StatFS returns file system wide statistics.
+// Sizes are reported in units of Frsize, multiply Blocks, Bfree or Bavail
+// by Frsize to obtain a size in bytes.
+//
+// Implements:
+//  int ceph_statfs(struct ceph_mount_info *cmount, const char *path, struct statvfs *stbuf);
+func (mount *MountInfo) StatFS(path string) (*CephStatVFS, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	var stat C.struct_statvfs
+	ret := C.ceph_statfs(mount.mount, cPath, &stat)
+	if err := getError(ret); err != nil {
+		return nil, err
+	}
+	return &CephStatVFS{
+		Bsize:   int64(stat.f_bsize),
+		Frsize:  int64(stat.f_frsize),
+		Blocks:  uint64(stat.f_blocks),
+		Bfree:   uint64(stat.f_bfree),
+		Bavail:  uint64(stat.f_bavail),
+		Files:   uint64(stat.f_files),
+		Ffree:   uint64(stat.f_ffree),
+		Favail:  uint64(stat.f_favail),
+		Fsid:    int64(stat.f_fsid),
+		Flag:    int64(stat.f_flag),
+		Namemax: int64(stat.f_namemax),
+	}, nil
+}
@@ -0,0 +1,30 @@
+package cephfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatFS(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	stat, err := mount.StatFS("/")
+	assert.NoError(t, err)
+	require.NotNil(t, stat)
+	assert.NotEqual(t, int64(0), stat.Bsize)
+	assert.NotEqual(t, int64(0), stat.Frsize)
+	assert.NotEqual(t, uint64(0), stat.Blocks)
+	assert.True(t, stat.Bfree <= stat.Blocks)
+	assert.True(t, stat.Bavail <= stat.Blocks)
+	assert.NotEqual(t, int64(0), stat.Namemax)
+
+	require.NoError(t, mount.MakeDir("/statfs", 0755))
+	defer mount.RemoveDir("/statfs")
+	stat2, err := mount.StatFS("/statfs")
+	assert.NoError(t, err)
+	require.NotNil(t, stat2)
+	assert.Equal(t, stat.Fsid, stat2.Fsid)
+}
@@ -0,0 +1,48 @@
+package cephfs
+
+import (
+	"sync"
+)
+
+// StatResult holds the outcome of the stat of a single path by StatMany.
+type StatResult struct {
+	Statx *CephStatx
+	Err   error
+}
+
+// StatMany returns the extended stat information of each of the given
+// paths, in the order of the paths. Up to workers goroutines issue the
+// statx calls in parallel, so that many calls can be waiting on the MDS at
+// once rather than being made one after another. See Statx for the meaning
+// of the want and flags arguments.
+//
+// A failure to stat one path does not prevent the others from being
+// stat'ed; the error is reported in the result for that path.
+func (mount *MountInfo) StatMany(paths []string, want StatxMask, flags AtFlags, workers int) []StatResult {
+	results := make([]StatResult, len(paths))
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for idx := range next {
+				stx, err := mount.Statx(paths[idx], want, flags)
+				results[idx] = StatResult{Statx: stx, Err: err}
+			}
+		}()
+	}
+	for idx := range paths {
+		next <- idx
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
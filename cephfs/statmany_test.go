@@ -0,0 +1,48 @@
+package cephfs
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatMany(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/statmany"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveAll(dir)
+
+	paths := []string{}
+	for i := 0; i < 20; i++ {
+		p := fmt.Sprintf("%s/file%d", dir, i)
+		f, err := mount.Open(p, os.O_WRONLY|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		_, err = f.Write(make([]byte, i))
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+		paths = append(paths, p)
+	}
+	paths = append(paths, dir+"/missing")
+
+	for _, workers := range []int{0, 1, 8, 100} {
+		t.Run(fmt.Sprintf("workers%d", workers), func(t *testing.T) {
+			results := mount.StatMany(paths, StatxBasicStats, 0, workers)
+			require.Len(t, results, len(paths))
+			for i, res := range results[:20] {
+				assert.NoError(t, res.Err)
+				require.NotNil(t, res.Statx)
+				assert.EqualValues(t, i, res.Statx.Size)
+			}
+			last := results[20]
+			assert.Nil(t, last.Statx)
+			assert.Equal(t, ErrNotExist, last.Err)
+		})
+	}
+
+	assert.Len(t, mount.StatMany(nil, StatxBasicStats, 0, 4), 0)
+}
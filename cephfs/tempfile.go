@@ -0,0 +1,149 @@
+package cephfs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TempFilePrefix is the prefix of the names of the files created by
+// CreateTempFile.
+const TempFilePrefix = ".cephfs-tmp-"
+
+const tempFileAttempts = 10
+
+// TempFile is a file created by CreateTempFile that becomes visible under
+// its final name only once it is published. The embedded File is open for
+// reading and writing.
+//
+// libcephfs does not support O_TMPFILE, so the file exists under a hidden,
+// randomly chosen, name until it is published or discarded. The hidden
+// files of processes that crashed can be removed with RemoveTempFiles.
+type TempFile struct {
+	*File
+	mount *MountInfo
+	dir   string
+	name  string
+
+	mu   sync.Mutex
+	done bool
+}
+
+// CreateTempFile creates a new, empty, file with the given mode in the
+// directory dir. The file is meant to be written in full and then published
+// under its final name with Publish, or removed with Discard.
+func (mount *MountInfo) CreateTempFile(dir string, mode uint32) (*TempFile, error) {
+	var lastErr error
+	for i := 0; i < tempFileAttempts; i++ {
+		suffix := make([]byte, 8)
+		if _, err := rand.Read(suffix); err != nil {
+			return nil, err
+		}
+		name := TempFilePrefix + hex.EncodeToString(suffix)
+		f, err := mount.Open(path.Join(dir, name), O_RDWR|O_CREATE|O_EXCL, mode)
+		if err == ErrExist {
+			lastErr = err
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		return &TempFile{File: f, mount: mount, dir: dir, name: name}, nil
+	}
+	return nil, lastErr
+}
+
+// Path returns the current, hidden, path of the temporary file.
+func (tf *TempFile) Path() string {
+	return path.Join(tf.dir, tf.name)
+}
+
+// Publish makes the file visible under the given name, in the directory
+// the file was created in, after syncing its data to stable storage. If
+// replace is false Publish behaves like linkat and fails with ErrExist if
+// the name is already in use; otherwise an existing file of that name is
+// atomically replaced, as with rename. The File remains open and must still
+// be closed.
+func (tf *TempFile) Publish(name string, replace bool) error {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	if tf.done {
+		return ErrInvalid
+	}
+	if err := tf.Fsync(SyncAll); err != nil {
+		return err
+	}
+
+	target := path.Join(tf.dir, name)
+	if replace {
+		if err := tf.mount.Rename(tf.Path(), target); err != nil {
+			return err
+		}
+	} else {
+		if err := tf.mount.Link(tf.Path(), target); err != nil {
+			return err
+		}
+		if err := tf.mount.Unlink(tf.Path()); err != nil {
+			return err
+		}
+	}
+	tf.done = true
+	return nil
+}
+
+// Discard closes and removes the temporary file. Discarding a file that was
+// already published or discarded only closes it, which makes it convenient
+// to defer Discard right after CreateTempFile.
+func (tf *TempFile) Discard() error {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+	if err := tf.Close(); err != nil {
+		return err
+	}
+	if tf.done {
+		return nil
+	}
+	if err := tf.mount.Unlink(tf.Path()); err != nil {
+		return err
+	}
+	tf.done = true
+	return nil
+}
+
+// RemoveTempFiles removes the temporary files, created by CreateTempFile in
+// the directory dir, that have not been modified for longer than olderThan.
+// It is meant to clean up after processes that crashed before publishing or
+// discarding their temporary files. The number of files removed is
+// returned.
+func (mount *MountInfo) RemoveTempFiles(dir string, olderThan time.Duration) (int, error) {
+	d, err := mount.OpenDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	defer d.Close()
+
+	cutoff := time.Now().Add(-olderThan).Unix()
+	removed := 0
+	for {
+		entry, err := d.ReadDirPlus(StatxMtime, 0)
+		if err != nil {
+			return removed, err
+		}
+		if entry == nil {
+			return removed, nil
+		}
+		if !strings.HasPrefix(entry.Name(), TempFilePrefix) ||
+			entry.Statx().Mtime.Sec > cutoff {
+			continue
+		}
+		err = mount.Unlink(path.Join(dir, entry.Name()))
+		if err != nil && err != ErrNotExist {
+			return removed, err
+		}
+		if err == nil {
+			removed++
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package cephfs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTempFile(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	dir := "/tempfile"
+	require.NoError(t, mount.MakeDir(dir, 0755))
+	defer mount.RemoveAll(dir)
+
+	listDir := func() []string {
+		d, err := mount.OpenDir(dir)
+		require.NoError(t, err)
+		defer d.Close()
+		names, err := d.List()
+		require.NoError(t, err)
+		return names
+	}
+
+	t.Run("publish", func(t *testing.T) {
+		tf, err := mount.CreateTempFile(dir, 0640)
+		require.NoError(t, err)
+		defer tf.Discard()
+		assert.True(t, strings.HasPrefix(tf.Path(), dir+"/"+TempFilePrefix))
+
+		_, err = tf.Write([]byte("published"))
+		assert.NoError(t, err)
+		assert.NoError(t, tf.Publish("data.txt", false))
+		assert.Equal(t, []string{"data.txt"}, listDir())
+		// the file can not be published twice
+		assert.Equal(t, ErrInvalid, tf.Publish("other.txt", false))
+		assert.NoError(t, tf.Discard())
+		assert.Equal(t, []string{"data.txt"}, listDir())
+
+		f, err := mount.Open(dir+"/data.txt", O_RDONLY, 0)
+		require.NoError(t, err)
+		defer f.Close()
+		buf := make([]byte, 32)
+		n, err := f.Read(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "published", string(buf[:n]))
+	})
+
+	t.Run("noReplace", func(t *testing.T) {
+		tf, err := mount.CreateTempFile(dir, 0640)
+		require.NoError(t, err)
+		defer tf.Discard()
+		assert.Equal(t, ErrExist, tf.Publish("data.txt", false))
+		assert.NoError(t, tf.Publish("data.txt", true))
+		assert.Equal(t, []string{"data.txt"}, listDir())
+		st, err := mount.Statx(dir+"/data.txt", StatxSize, 0)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, st.Size)
+	})
+
+	t.Run("discard", func(t *testing.T) {
+		tf, err := mount.CreateTempFile(dir, 0640)
+		require.NoError(t, err)
+		assert.Len(t, listDir(), 2)
+		assert.NoError(t, tf.Discard())
+		assert.NoError(t, tf.Discard())
+		assert.Equal(t, []string{"data.txt"}, listDir())
+	})
+
+	t.Run("removeStale", func(t *testing.T) {
+		tf, err := mount.CreateTempFile(dir, 0640)
+		require.NoError(t, err)
+		require.NoError(t, tf.Close())
+
+		n, err := mount.RemoveTempFiles(dir, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		assert.Len(t, listDir(), 2)
+
+		n, err = mount.RemoveTempFiles(dir, -time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []string{"data.txt"}, listDir())
+	})
+}
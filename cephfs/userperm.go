@@ -0,0 +1,59 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// UserPerm types may be used to get or change the credentials used by the
+// mount or by individual low-level calls.
+type UserPerm struct {
+	userPerm *C.UserPerm
+	// libcephfs does not copy the supplementary group list, so it is
+	// kept in C memory owned by this object for the object's lifetime
+	gidList *C.gid_t
+}
+
+// NewUserPerm creates a UserPerm pointer and the underlying ceph resources.
+// The caller is responsible for calling Destroy on the UserPerm when it is
+// no longer needed.
+//
+// Implements:
+//  UserPerm *ceph_userperm_new(uid_t uid, gid_t gid, int ngids, gid_t *gidlist);
+func NewUserPerm(uid, gid int, gidlist []int) *UserPerm {
+	p := &UserPerm{}
+	if len(gidlist) > 0 {
+		size := C.size_t(len(gidlist)) * C.size_t(unsafe.Sizeof(C.gid_t(0)))
+		p.gidList = (*C.gid_t)(C.malloc(size))
+		gids := (*[1 << 28]C.gid_t)(unsafe.Pointer(p.gidList))[:len(gidlist):len(gidlist)]
+		for i, g := range gidlist {
+			gids[i] = C.gid_t(g)
+		}
+	}
+	p.userPerm = C.ceph_userperm_new(
+		C.uid_t(uid), C.gid_t(gid), C.int(len(gidlist)), p.gidList)
+	return p
+}
+
+// Destroy will explicitly free ceph resources associated with the UserPerm.
+//
+// Implements:
+//  void ceph_userperm_destroy(UserPerm *perm);
+func (p *UserPerm) Destroy() {
+	if p.userPerm == nil {
+		return
+	}
+	C.ceph_userperm_destroy(p.userPerm)
+	p.userPerm = nil
+	if p.gidList != nil {
+		C.free(unsafe.Pointer(p.gidList))
+		p.gidList = nil
+	}
+}
@@ -0,0 +1,23 @@
+package cephfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserPerm(t *testing.T) {
+	t.Run("simple", func(t *testing.T) {
+		uperm := NewUserPerm(0, 500, []int{0, 500, 501})
+		assert.NotNil(t, uperm)
+		uperm.Destroy()
+	})
+
+	t.Run("noGroups", func(t *testing.T) {
+		uperm := NewUserPerm(1010, 1010, nil)
+		assert.NotNil(t, uperm)
+		uperm.Destroy()
+		// destroying a second time is harmless
+		uperm.Destroy()
+	})
+}
@@ -0,0 +1,81 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <fcntl.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// The ceph_utimes, ceph_lutimes and ceph_futimens calls only exist in newer
+// versions of libcephfs. The same result can be had with the setattrx
+// family of calls, which are available in all supported versions and keep
+// the full nanosecond precision of the timestamps.
+
+const utimeMask = C.CEPH_SETATTR_ATIME | C.CEPH_SETATTR_MTIME
+
+func timespecToCStruct(ts Timespec) C.struct_timespec {
+	var t C.struct_timespec
+	t.tv_sec = C.time_t(ts.Sec)
+	t.tv_nsec = C.long(ts.Nsec)
+	return t
+}
+
+func utimeStatx(atime, mtime Timespec) C.struct_ceph_statx {
+	var stx C.struct_ceph_statx
+	stx.stx_atime = timespecToCStruct(atime)
+	stx.stx_mtime = timespecToCStruct(mtime)
+	return stx
+}
+
+func (mount *MountInfo) utimes(path string, atime, mtime Timespec, flags AtFlags) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	stx := utimeStatx(atime, mtime)
+	ret := C.ceph_setattrx(mount.mount, cPath, &stx, utimeMask, C.int(flags))
+	return getError(ret)
+}
+
+// Utimes sets the access and modification times of a file/directory.
+// If the path refers to a symbolic link the times of the file it points to
+// are changed.
+//
+// Implements:
+//  int ceph_setattrx(struct ceph_mount_info *cmount, const char *relpath,
+//                    struct ceph_statx *stx, int mask, int flags);
+func (mount *MountInfo) Utimes(path string, atime, mtime Timespec) error {
+	return mount.utimes(path, atime, mtime, 0)
+}
+
+// Lutimes sets the access and modification times of a file/directory.
+// If the path refers to a symbolic link the times of the link itself are
+// changed, rather than those of the file it points to.
+//
+// Implements:
+//  int ceph_setattrx(struct ceph_mount_info *cmount, const char *relpath,
+//                    struct ceph_statx *stx, int mask, int flags);
+func (mount *MountInfo) Lutimes(path string, atime, mtime Timespec) error {
+	return mount.utimes(path, atime, mtime, AtSymlinkNofollow)
+}
+
+// Futimens sets the access and modification times of the open file.
+//
+// Implements:
+//  int ceph_fsetattrx(struct ceph_mount_info *cmount, int fd, struct ceph_statx *stx, int mask);
+func (f *File) Futimens(atime, mtime Timespec) error {
+	if err := f.rlock(); err != nil {
+		return err
+	}
+	defer f.mu.RUnlock()
+
+	stx := utimeStatx(atime, mtime)
+	ret := C.ceph_fsetattrx(f.mount.mount, f.fd, &stx, utimeMask)
+	return getError(ret)
+}
@@ -0,0 +1,89 @@
+package cephfs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	utimeAtime = Timespec{Sec: 1500000000, Nsec: 1234}
+	utimeMtime = Timespec{Sec: 1400000000, Nsec: 5678}
+)
+
+func TestUtimes(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "utimes.txt"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	err = mount.Utimes(fname, utimeAtime, utimeMtime)
+	assert.NoError(t, err)
+
+	st, err := mount.Statx(fname, StatxAtime|StatxMtime, 0)
+	require.NoError(t, err)
+	assert.Equal(t, utimeAtime, st.Atime)
+	assert.Equal(t, utimeMtime, st.Mtime)
+
+	// the change is visible through the regular file system too
+	fi, err := os.Stat(CephMountTest + fname)
+	require.NoError(t, err)
+	assert.Equal(t, utimeMtime.Sec, fi.ModTime().Unix())
+
+	err = mount.Utimes("utimes.missing", utimeAtime, utimeMtime)
+	assert.Error(t, err)
+}
+
+func TestLutimes(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "lutimes.target"
+	lname := "lutimes.link"
+	f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+	require.NoError(t, mount.Symlink(fname, lname))
+	defer mount.Unlink(lname)
+
+	before, err := mount.Statx(fname, StatxMtime, 0)
+	require.NoError(t, err)
+
+	err = mount.Lutimes(lname, utimeAtime, utimeMtime)
+	assert.NoError(t, err)
+
+	st, err := mount.Statx(lname, StatxMtime, AtSymlinkNofollow)
+	require.NoError(t, err)
+	assert.Equal(t, utimeMtime, st.Mtime)
+
+	// the target of the link is not changed
+	st, err = mount.Statx(fname, StatxMtime, 0)
+	require.NoError(t, err)
+	assert.Equal(t, before.Mtime, st.Mtime)
+}
+
+func TestFutimens(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "futimens.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0644)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+
+	err = f.Futimens(utimeAtime, utimeMtime)
+	assert.NoError(t, err)
+
+	st, err := mount.Statx(fname, StatxAtime|StatxMtime, 0)
+	require.NoError(t, err)
+	assert.Equal(t, utimeAtime, st.Atime)
+	assert.Equal(t, utimeMtime, st.Mtime)
+}
@@ -0,0 +1,181 @@
+package cephfs
+
+import (
+	"path"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// WalkDirFunc is the type of the function called by WalkDir for each file
+// or directory visited. The path argument is the path of the entry, joined
+// with the root passed to WalkDir, and entry holds the directory entry along
+// with its stat information.
+//
+// If reading a directory fails the function is called a second time for
+// that directory with the error that occurred. If the function returns
+// filepath.SkipDir for a directory the directory is not descended into;
+// returned for any other entry the remaining entries of the containing
+// directory are skipped. Any other non-nil error stops the walk and is
+// returned by WalkDir.
+type WalkDirFunc func(path string, entry *DirEntryPlus, err error) error
+
+type walkItem struct {
+	path  string
+	entry *DirEntryPlus
+}
+
+// walker holds the state of a WalkDir call shared by the worker goroutines.
+type walker struct {
+	mount *MountInfo
+	fn    WalkDirFunc
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []walkItem
+	active int
+	err    error
+}
+
+// WalkDir walks the file tree rooted at root, calling fn for each file or
+// directory in the tree, including root. Directories are read by up to
+// workers goroutines in parallel, which makes walking large trees much
+// faster than a serial walk, but means fn is called concurrently and must
+// be safe for that. The order in which entries are visited is not defined,
+// except that a directory is always visited before its contents.
+//
+// Symbolic links are not followed, not even if root is one.
+func (mount *MountInfo) WalkDir(root string, workers int, fn WalkDirFunc) error {
+	stx, err := mount.Statx(root, StatxBasicStats, AtSymlinkNofollow)
+	if err != nil {
+		err = fn(root, nil, err)
+		if err == filepath.SkipDir {
+			err = nil
+		}
+		return err
+	}
+	entry := &DirEntryPlus{
+		DirEntry: DirEntry{
+			inode: stx.Inode,
+			name:  path.Base(root),
+			dtype: DTypeUnknown,
+		},
+		statx: stx,
+	}
+	if uint32(stx.Mode)&syscall.S_IFMT == syscall.S_IFDIR {
+		entry.dtype = DTypeDir
+	}
+	err = fn(root, entry, nil)
+	if err != nil || entry.dtype != DTypeDir {
+		if err == filepath.SkipDir {
+			err = nil
+		}
+		return err
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	w := &walker{
+		mount: mount,
+		fn:    fn,
+		queue: []walkItem{{path: root, entry: entry}},
+	}
+	w.cond = sync.NewCond(&w.mu)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			w.work()
+		}()
+	}
+	wg.Wait()
+	return w.err
+}
+
+// work reads directories from the queue until the walk is complete or
+// has failed.
+func (w *walker) work() {
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && w.active > 0 && w.err == nil {
+			w.cond.Wait()
+		}
+		if len(w.queue) == 0 || w.err != nil {
+			// either all the work is done or the walk failed
+			w.mu.Unlock()
+			w.cond.Broadcast()
+			return
+		}
+		item := w.queue[len(w.queue)-1]
+		w.queue = w.queue[:len(w.queue)-1]
+		w.active++
+		w.mu.Unlock()
+
+		subdirs, err := w.readDir(item)
+
+		w.mu.Lock()
+		w.active--
+		w.queue = append(w.queue, subdirs...)
+		if err != nil && w.err == nil {
+			w.err = err
+		}
+		w.mu.Unlock()
+		w.cond.Broadcast()
+	}
+}
+
+// failed reports if the walk has been stopped by an error.
+func (w *walker) failed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err != nil
+}
+
+// readDir calls the walk function for each entry of a directory and
+// returns the subdirectories that should be walked.
+func (w *walker) readDir(item walkItem) ([]walkItem, error) {
+	dir, err := w.mount.OpenDir(item.path)
+	if err != nil {
+		return nil, w.dirError(item, err)
+	}
+	defer dir.Close()
+
+	subdirs := []walkItem{}
+	for !w.failed() {
+		entry, err := dir.ReadDirPlus(StatxBasicStats, 0)
+		if err != nil {
+			return subdirs, w.dirError(item, err)
+		}
+		if entry == nil {
+			break
+		}
+		if entry.name == "." || entry.name == ".." {
+			continue
+		}
+		p := path.Join(item.path, entry.name)
+		err = w.fn(p, entry, nil)
+		switch {
+		case err == filepath.SkipDir && entry.dtype == DTypeDir:
+			continue
+		case err == filepath.SkipDir:
+			return subdirs, nil
+		case err != nil:
+			return nil, err
+		}
+		if entry.dtype == DTypeDir {
+			subdirs = append(subdirs, walkItem{path: p, entry: entry})
+		}
+	}
+	return subdirs, nil
+}
+
+// dirError reports an error reading a directory to the walk function.
+func (w *walker) dirError(item walkItem, err error) error {
+	err = w.fn(item.path, item.entry, err)
+	if err == filepath.SkipDir {
+		err = nil
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package cephfs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeWalkTree creates a small tree for walk tests and returns the list of
+// paths created along with a function that removes them.
+func makeWalkTree(t *testing.T, mount *MountInfo, root string) ([]string, func()) {
+	paths := []string{root}
+	require.NoError(t, mount.MakeDir(root, 0755))
+	for i := 0; i < 3; i++ {
+		dir := fmt.Sprintf("%s/dir%d", root, i)
+		require.NoError(t, mount.MakeDir(dir, 0755))
+		paths = append(paths, dir)
+		for j := 0; j < 4; j++ {
+			fname := fmt.Sprintf("%s/file%d", dir, j)
+			f, err := mount.Open(fname, os.O_WRONLY|os.O_CREATE, 0644)
+			require.NoError(t, err)
+			assert.NoError(t, f.Close())
+			paths = append(paths, fname)
+		}
+	}
+	require.NoError(t, mount.Symlink("dir0", root+"/link"))
+	paths = append(paths, root+"/link")
+
+	cleanup := func() {
+		for i := len(paths) - 1; i >= 0; i-- {
+			if err := mount.Unlink(paths[i]); err != nil {
+				mount.RemoveDir(paths[i])
+			}
+		}
+	}
+	return paths, cleanup
+}
+
+func TestWalkDir(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	root := "/TestWalkDir"
+	paths, cleanup := makeWalkTree(t, mount, root)
+	defer cleanup()
+
+	for _, workers := range []int{0, 1, 4} {
+		t.Run(fmt.Sprintf("workers%d", workers), func(t *testing.T) {
+			var mu sync.Mutex
+			seen := []string{}
+			err := mount.WalkDir(root, workers,
+				func(p string, entry *DirEntryPlus, err error) error {
+					// require must not be used outside the test goroutine
+					assert.NoError(t, err)
+					if assert.NotNil(t, entry) {
+						assert.NotNil(t, entry.Statx())
+					}
+					mu.Lock()
+					seen = append(seen, p)
+					mu.Unlock()
+					return nil
+				})
+			assert.NoError(t, err)
+			sort.Strings(seen)
+			expected := append([]string{}, paths...)
+			sort.Strings(expected)
+			assert.Equal(t, expected, seen)
+		})
+	}
+
+	t.Run("skipDir", func(t *testing.T) {
+		var mu sync.Mutex
+		seen := map[string]bool{}
+		err := mount.WalkDir(root, 4,
+			func(p string, entry *DirEntryPlus, err error) error {
+				mu.Lock()
+				seen[p] = true
+				mu.Unlock()
+				if p == root+"/dir1" {
+					return filepath.SkipDir
+				}
+				return nil
+			})
+		assert.NoError(t, err)
+		assert.True(t, seen[root+"/dir1"])
+		assert.False(t, seen[root+"/dir1/file0"])
+		assert.True(t, seen[root+"/dir2/file0"])
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		errStop := errors.New("stop")
+		err := mount.WalkDir(root, 4,
+			func(p string, entry *DirEntryPlus, err error) error {
+				if p == root+"/dir0/file2" {
+					return errStop
+				}
+				return nil
+			})
+		assert.Equal(t, errStop, err)
+	})
+
+	t.Run("missingRoot", func(t *testing.T) {
+		called := 0
+		err := mount.WalkDir("/TestWalkDir.missing", 4,
+			func(p string, entry *DirEntryPlus, err error) error {
+				called++
+				assert.Nil(t, entry)
+				return err
+			})
+		assert.Error(t, err)
+		assert.Equal(t, 1, called)
+	})
+
+	t.Run("fileRoot", func(t *testing.T) {
+		seen := []string{}
+		err := mount.WalkDir(root+"/dir0/file0", 4,
+			func(p string, entry *DirEntryPlus, err error) error {
+				seen = append(seen, p)
+				return nil
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{root + "/dir0/file0"}, seen)
+	})
+}
@@ -0,0 +1,144 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <sys/xattr.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"unsafe"
+)
+
+// XattrFlags are used to control the behavior of set-xattr calls.
+type XattrFlags int
+
+const (
+	// XattrDefault specifies that set-xattr calls use the default behavior of
+	// creating or updating an xattr.
+	XattrDefault = XattrFlags(0)
+	// XattrCreate specifies that set-xattr calls only set new xattrs.
+	XattrCreate = XattrFlags(C.XATTR_CREATE)
+	// XattrReplace specifies that set-xattr calls only replace existing xattr
+	// values.
+	XattrReplace = XattrFlags(C.XATTR_REPLACE)
+)
+
+// bufPointer returns a pointer suitable for passing a Go byte slice to a C
+// function, including nil for an empty slice.
+func bufPointer(buf []byte) unsafe.Pointer {
+	if len(buf) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(&buf[0])
+}
+
+// xattrBuffer calls one of the functions that copy an xattr value or list
+// into a buffer. The size of the data is queried first, using an empty
+// buffer, and the call is retried if the data grew in between, so that data
+// of any size is returned in full.
+func xattrBuffer(f func(buf unsafe.Pointer, size C.size_t) C.int) ([]byte, error) {
+	ret := f(nil, 0)
+	for {
+		if ret < 0 {
+			return nil, getError(ret)
+		}
+		buf := make([]byte, ret)
+		ret = f(bufPointer(buf), C.size_t(len(buf)))
+		if ret == -C.ERANGE {
+			ret = f(nil, 0)
+			continue
+		}
+		if ret < 0 {
+			return nil, getError(ret)
+		}
+		return buf[:ret], nil
+	}
+}
+
+// parseXattrList splits the NUL separated list of names returned by
+// the listxattr family of calls.
+func parseXattrList(buf []byte) []string {
+	names := []string{}
+	for _, name := range bytes.Split(buf, []byte{0}) {
+		if len(name) > 0 {
+			names = append(names, string(name))
+		}
+	}
+	return names
+}
+
+// SetXattr sets an extended attribute on the file at the supplied path.
+//
+// Implements:
+//  int ceph_setxattr(struct ceph_mount_info *cmount, const char *path, const char *name,
+//                    const void *value, size_t size, int flags);
+func (mount *MountInfo) SetXattr(path, name string, value []byte, flags XattrFlags) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	ret := C.ceph_setxattr(
+		mount.mount,
+		cPath,
+		cName,
+		bufPointer(value),
+		C.size_t(len(value)),
+		C.int(flags))
+	return getError(ret)
+}
+
+// GetXattr gets an extended attribute from the file at the supplied path.
+//
+// Implements:
+//  int ceph_getxattr(struct ceph_mount_info *cmount, const char *path, const char *name,
+//                    void *value, size_t size);
+func (mount *MountInfo) GetXattr(path, name string) ([]byte, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	return xattrBuffer(func(buf unsafe.Pointer, size C.size_t) C.int {
+		return C.ceph_getxattr(mount.mount, cPath, cName, buf, size)
+	})
+}
+
+// ListXattr returns a slice containing strings for the name of each xattr set
+// on the file at the supplied path. There is no limit on the number of names
+// returned.
+//
+// Implements:
+//  int ceph_listxattr(struct ceph_mount_info *cmount, const char *path, char *list, size_t size);
+func (mount *MountInfo) ListXattr(path string) ([]string, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	buf, err := xattrBuffer(func(buf unsafe.Pointer, size C.size_t) C.int {
+		return C.ceph_listxattr(mount.mount, cPath, (*C.char)(buf), size)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseXattrList(buf), nil
+}
+
+// RemoveXattr removes the named xattr from the file at the supplied path.
+//
+// Implements:
+//  int ceph_removexattr(struct ceph_mount_info *cmount, const char *path, const char *name);
+func (mount *MountInfo) RemoveXattr(path, name string) error {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	ret := C.ceph_removexattr(mount.mount, cPath, cName)
+	return getError(ret)
+}
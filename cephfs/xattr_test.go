@@ -0,0 +1,147 @@
+package cephfs
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var xattrSamples = []struct {
+	name  string
+	value []byte
+}{
+	{
+		name:  "user.xPhrase",
+		value: []byte("june and july"),
+	},
+	{
+		name:  "user.xHasNulls",
+		value: []byte("\x00got\x00null?\x00"),
+	},
+	{
+		name:  "user.x2kZeros",
+		value: make([]byte, 2048),
+	},
+	{
+		name:  "user.xEmpty",
+		value: []byte(""),
+	},
+}
+
+func TestParseXattrList(t *testing.T) {
+	assert.Equal(t, []string{}, parseXattrList(nil))
+	assert.Equal(t, []string{"user.a"}, parseXattrList([]byte("user.a\x00")))
+	assert.Equal(t,
+		[]string{"user.a", "user.bb", "security.c"},
+		parseXattrList([]byte("user.a\x00user.bb\x00security.c\x00")))
+}
+
+func TestGetSetXattr(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestGetSetXattr.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	for _, s := range xattrSamples {
+		t.Run("roundTrip-"+s.name, func(t *testing.T) {
+			err := mount.SetXattr(fname, s.name, s.value, XattrDefault)
+			assert.NoError(t, err)
+			b, err := mount.GetXattr(fname, s.name)
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.value, b)
+		})
+	}
+
+	t.Run("createFlag", func(t *testing.T) {
+		err := mount.SetXattr(fname, xattrSamples[0].name, []byte("x"), XattrCreate)
+		assert.Error(t, err)
+	})
+
+	t.Run("replaceFlag", func(t *testing.T) {
+		err := mount.SetXattr(fname, "user.xNotThere", []byte("x"), XattrReplace)
+		assert.Error(t, err)
+		err = mount.SetXattr(fname, xattrSamples[0].name, []byte("x"), XattrReplace)
+		assert.NoError(t, err)
+	})
+
+	t.Run("missingXattr", func(t *testing.T) {
+		_, err := mount.GetXattr(fname, "user.xNotThere")
+		assert.Error(t, err)
+	})
+
+	t.Run("missingFile", func(t *testing.T) {
+		_, err := mount.GetXattr("TestGetSetXattr.missing", xattrSamples[0].name)
+		assert.Error(t, err)
+		err = mount.SetXattr("TestGetSetXattr.missing", "user.x", nil, XattrDefault)
+		assert.Error(t, err)
+	})
+}
+
+func TestListRemoveXattr(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestListRemoveXattr.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+	defer mount.Unlink(fname)
+
+	names, err := mount.ListXattr(fname)
+	assert.NoError(t, err)
+	assert.Len(t, names, 0)
+
+	expected := []string{}
+	for _, s := range xattrSamples[:3] {
+		require.NoError(t, mount.SetXattr(fname, s.name, s.value, XattrDefault))
+		expected = append(expected, s.name)
+	}
+
+	names, err = mount.ListXattr(fname)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, expected, names)
+
+	err = mount.RemoveXattr(fname, xattrSamples[0].name)
+	assert.NoError(t, err)
+	names, err = mount.ListXattr(fname)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, expected[1:], names)
+
+	err = mount.RemoveXattr(fname, xattrSamples[0].name)
+	assert.Error(t, err)
+
+	_, err = mount.ListXattr("TestListRemoveXattr.missing")
+	assert.Error(t, err)
+}
+
+func TestListManyXattrs(t *testing.T) {
+	mount := fsConnect(t)
+	defer mount.Unmount()
+
+	fname := "TestListManyXattrs.txt"
+	f, err := mount.Open(fname, os.O_RDWR|os.O_CREATE, 0666)
+	require.NoError(t, err)
+	defer mount.Unlink(fname)
+	defer f.Close()
+
+	expected := []string{}
+	for i := 0; i < 500; i++ {
+		name := fmt.Sprintf("user.application.attribute.number.%04d", i)
+		require.NoError(t, f.SetXattr(name, []byte("v"), XattrDefault))
+		expected = append(expected, name)
+	}
+
+	names, err := mount.ListXattr(fname)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, expected, names)
+	names, err = f.ListXattr()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, expected, names)
+}
@@ -147,6 +147,7 @@ test_go_ceph() {
     P=github.com/ceph/go-ceph
     pkgs=(\
         "cephfs" \
+        "cephfs/admin" \
         "errutil" \
         "rados" \
         "rbd" \
@@ -95,6 +95,16 @@ ceph-fuse ${MOUNTPT}
 
 # start a manager
 ceph-mgr --id ${MGR_NAME}
+# wait for the manager, and for the volumes module serving the "fs volume"
+# and "fs subvolume" commands on releases that have it
+while ! ceph mgr dump -f json | grep -q '"available":true'; do sleep 1; done
+case "${CEPH_VERSION:-}" in
+    luminous|mimic)
+    ;;
+    *)
+        while ! ceph fs volume ls >/dev/null 2>&1; do sleep 1; done
+    ;;
+esac
 
 # test the setup
 ceph --version
@@ -336,6 +336,74 @@ func (c *Conn) monCommand(args, inputBuffer []byte) (buffer []byte, info string,
 	return
 }
 
+// MgrCommand sends a command to a ceph-mgr.
+//
+// Implements:
+//  int rados_mgr_command(rados_t cluster, const char **cmd,
+//                        size_t cmdlen, const char *inbuf,
+//                        size_t inbuflen, char **outbuf,
+//                        size_t *outbuflen, char **outs,
+//                        size_t *outslen);
+func (c *Conn) MgrCommand(args [][]byte) (buffer []byte, info string, err error) {
+	return c.mgrCommand(args, nil)
+}
+
+// MgrCommandWithInputBuffer sends a command, with an input buffer, to a
+// ceph-mgr.
+//
+// Implements:
+//  int rados_mgr_command(rados_t cluster, const char **cmd,
+//                        size_t cmdlen, const char *inbuf,
+//                        size_t inbuflen, char **outbuf,
+//                        size_t *outbuflen, char **outs,
+//                        size_t *outslen);
+func (c *Conn) MgrCommandWithInputBuffer(args [][]byte, inputBuffer []byte) (buffer []byte, info string, err error) {
+	return c.mgrCommand(args, inputBuffer)
+}
+
+func (c *Conn) mgrCommand(args [][]byte, inputBuffer []byte) (buffer []byte, info string, err error) {
+	argc := len(args)
+	argv := make([]*C.char, argc)
+
+	for i, arg := range args {
+		argv[i] = C.CString(string(arg))
+		defer C.free(unsafe.Pointer(argv[i]))
+	}
+
+	var (
+		outs, outbuf       *C.char
+		outslen, outbuflen C.size_t
+	)
+	inbuf := C.CString(string(inputBuffer))
+	inbufLen := len(inputBuffer)
+	defer C.free(unsafe.Pointer(inbuf))
+
+	ret := C.rados_mgr_command(c.cluster,
+		&argv[0],
+		C.size_t(argc),
+		inbuf,              // bulk input
+		C.size_t(inbufLen), // length inbuf
+		&outbuf,            // buffer
+		&outbuflen,         // buffer length
+		&outs,              // status string
+		&outslen)
+
+	if outslen > 0 {
+		info = C.GoStringN(outs, C.int(outslen))
+		C.free(unsafe.Pointer(outs))
+	}
+	if outbuflen > 0 {
+		buffer = C.GoBytes(unsafe.Pointer(outbuf), C.int(outbuflen))
+		C.free(unsafe.Pointer(outbuf))
+	}
+	if ret != 0 {
+		err = RadosError(int(ret))
+		return nil, info, err
+	}
+
+	return
+}
+
 // PGCommand sends a command to one of the PGs
 //
 // Implements:
@@ -75,6 +75,12 @@ type ObjectStat struct {
 	ModTime time.Time
 }
 
+// LockFlagRenew may be passed as flags to IOContext.LockExclusive() and
+// IOContext.LockShared() to renew a lock already held by the same client and
+// cookie, instead of failing. Taking a lock with a duration and renewing it
+// periodically keeps the lock held only as long as its owner is alive.
+const LockFlagRenew = C.LIBRADOS_LOCK_FLAG_RENEW
+
 // LockInfo represents information on a current Ceph lock
 type LockInfo struct {
 	NumLockers int
@@ -106,6 +112,45 @@ func (ioctx *IOContext) SetNamespace(namespace string) {
 	C.rados_ioctx_set_namespace(ioctx.ioctx, c_ns)
 }
 
+// GetNamespace returns the namespace of the objects within this IO context.
+// An empty string is returned for the default namespace, and AllNamespaces
+// if the IO context is set to list objects across all namespaces.
+//
+// Implements:
+//  int rados_ioctx_get_namespace(rados_ioctx_t io, char *buf,
+//                                unsigned maxlen);
+func (ioctx *IOContext) GetNamespace() (string, error) {
+	buf := make([]byte, 128)
+	for {
+		ret := C.rados_ioctx_get_namespace(ioctx.ioctx,
+			(*C.char)(unsafe.Pointer(&buf[0])), C.unsigned(len(buf)))
+		if ret == -C.ERANGE {
+			buf = make([]byte, len(buf)*2)
+			continue
+		} else if ret < 0 {
+			return "", getRadosError(int(ret))
+		}
+		return C.GoStringN((*C.char)(unsafe.Pointer(&buf[0])), ret), nil
+	}
+}
+
+// SetLocatorKey sets the key used in place of the object name to determine
+// the placement of the objects written through this IO context. Objects
+// sharing a locator key are stored in the same placement group. The same key
+// must be set to access such objects again. Setting key to an empty string
+// reverts to placing objects by name.
+//
+// Implements:
+//  void rados_ioctx_locator_set_key(rados_ioctx_t io, const char *key);
+func (ioctx *IOContext) SetLocatorKey(key string) {
+	var c_key *C.char
+	if len(key) > 0 {
+		c_key = C.CString(key)
+		defer C.free(unsafe.Pointer(c_key))
+	}
+	C.rados_ioctx_locator_set_key(ioctx.ioctx, c_key)
+}
+
 // Create a new object with key oid.
 //
 // Implements:
@@ -149,12 +194,47 @@ func (ioctx *IOContext) WriteFull(oid string, data []byte) error {
 	c_oid := C.CString(oid)
 	defer C.free(unsafe.Pointer(c_oid))
 
-	ret := C.rados_write_full(ioctx.ioctx, c_oid,
-		(*C.char)(unsafe.Pointer(&data[0])),
+	var buf *C.char
+	if len(data) > 0 {
+		buf = (*C.char)(unsafe.Pointer(&data[0]))
+	}
+
+	ret := C.rados_write_full(ioctx.ioctx, c_oid, buf,
 		(C.size_t)(len(data)))
 	return getRadosError(int(ret))
 }
 
+// WriteSame writes the contents of data repeatedly to the object with key oid,
+// starting at byte offset offset, until writeLen bytes have been written.
+// writeLen must be a multiple of len(data). This allows large ranges of an
+// object to be filled with a pattern, or with zeroes, without sending writeLen
+// bytes to the OSDs. It returns an error, if any.
+//
+// Implements:
+//  int rados_writesame(rados_ioctx_t io, const char *oid, const char *buf,
+//                      size_t data_len, size_t write_len, uint64_t off);
+func (ioctx *IOContext) WriteSame(oid string, data []byte, writeLen, offset uint64) error {
+	if len(data) == 0 {
+		if writeLen == 0 {
+			return nil
+		}
+		return RadosError(-C.EINVAL)
+	}
+	if writeLen%uint64(len(data)) != 0 {
+		return RadosError(-C.EINVAL)
+	}
+
+	c_oid := C.CString(oid)
+	defer C.free(unsafe.Pointer(c_oid))
+
+	ret := C.rados_writesame(ioctx.ioctx, c_oid,
+		(*C.char)(unsafe.Pointer(&data[0])),
+		(C.size_t)(len(data)),
+		(C.size_t)(writeLen),
+		(C.uint64_t)(offset))
+	return getRadosError(int(ret))
+}
+
 // Append appends len(data) bytes to the object with key oid.
 // The object is appended with the provided data. If the object exists,
 // it is atomically appended to. It returns an error, if any.
@@ -162,8 +242,12 @@ func (ioctx *IOContext) Append(oid string, data []byte) error {
 	c_oid := C.CString(oid)
 	defer C.free(unsafe.Pointer(c_oid))
 
-	ret := C.rados_append(ioctx.ioctx, c_oid,
-		(*C.char)(unsafe.Pointer(&data[0])),
+	var buf *C.char
+	if len(data) > 0 {
+		buf = (*C.char)(unsafe.Pointer(&data[0]))
+	}
+
+	ret := C.rados_append(ioctx.ioctx, c_oid, buf,
 		(C.size_t)(len(data)))
 	return getRadosError(int(ret))
 }
@@ -211,6 +295,26 @@ func (ioctx *IOContext) Truncate(oid string, size uint64) error {
 	return getRadosError(int(C.rados_trunc(ioctx.ioctx, c_oid, (C.uint64_t)(size))))
 }
 
+// Zero sets the length bytes of the object with key oid, starting at byte
+// offset offset, to zero. Where the storage backend supports it the range is
+// deallocated rather than overwritten. Zeroing a range that extends past the
+// end of the object does not change its size. It returns an error, if any.
+//
+// Implements:
+//  void rados_write_op_zero(rados_write_op_t write_op, uint64_t offset,
+//                           uint64_t len);
+func (ioctx *IOContext) Zero(oid string, offset, length uint64) error {
+	c_oid := C.CString(oid)
+	defer C.free(unsafe.Pointer(c_oid))
+
+	op := C.rados_create_write_op()
+	C.rados_write_op_zero(op, C.uint64_t(offset), C.uint64_t(length))
+	ret := C.rados_write_op_operate(op, ioctx.ioctx, c_oid, nil, 0)
+	C.rados_release_write_op(op)
+
+	return getRadosError(int(ret))
+}
+
 // Destroy informs librados that the I/O context is no longer in use.
 // Resources associated with the context may not be freed immediately, and the
 // context should not be used again after calling this method.
@@ -322,11 +426,16 @@ func (ioctx *IOContext) GetXattr(object string, name string, data []byte) (int,
 	defer C.free(unsafe.Pointer(c_object))
 	defer C.free(unsafe.Pointer(c_name))
 
+	var buf *C.char
+	if len(data) > 0 {
+		buf = (*C.char)(unsafe.Pointer(&data[0]))
+	}
+
 	ret := C.rados_getxattr(
 		ioctx.ioctx,
 		c_object,
 		c_name,
-		(*C.char)(unsafe.Pointer(&data[0])),
+		buf,
 		(C.size_t)(len(data)))
 
 	if ret >= 0 {
@@ -342,11 +451,16 @@ func (ioctx *IOContext) SetXattr(object string, name string, data []byte) error
 	defer C.free(unsafe.Pointer(c_object))
 	defer C.free(unsafe.Pointer(c_name))
 
+	var buf *C.char
+	if len(data) > 0 {
+		buf = (*C.char)(unsafe.Pointer(&data[0]))
+	}
+
 	ret := C.rados_setxattr(
 		ioctx.ioctx,
 		c_object,
 		c_name,
-		(*C.char)(unsafe.Pointer(&data[0])),
+		buf,
 		(C.size_t)(len(data)))
 
 	return getRadosError(int(ret))
@@ -367,10 +481,9 @@ func (ioctx *IOContext) ListXattrs(oid string) (map[string][]byte, error) {
 	defer func() { C.rados_getxattrs_end(it) }()
 	m := make(map[string][]byte)
 	for {
+		// the name and value are owned by the iterator
 		var c_name, c_val *C.char
 		var c_len C.size_t
-		defer C.free(unsafe.Pointer(c_name))
-		defer C.free(unsafe.Pointer(c_val))
 
 		ret := C.rados_getxattrs_next(it, &c_name, &c_val, &c_len)
 		if ret < 0 {
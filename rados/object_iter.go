@@ -10,6 +10,7 @@ type Iter struct {
 	ctx       C.rados_list_ctx_t
 	err       error
 	entry     string
+	key       string
 	namespace string
 }
 
@@ -51,12 +52,14 @@ func (iter *Iter) Seek(token IterToken) {
 //
 func (iter *Iter) Next() bool {
 	var c_entry *C.char
+	var c_key *C.char
 	var c_namespace *C.char
-	if cerr := C.rados_nobjects_list_next(iter.ctx, &c_entry, nil, &c_namespace); cerr < 0 {
+	if cerr := C.rados_nobjects_list_next(iter.ctx, &c_entry, &c_key, &c_namespace); cerr < 0 {
 		iter.err = getRadosError(int(cerr))
 		return false
 	}
 	iter.entry = C.GoString(c_entry)
+	iter.key = C.GoString(c_key)
 	iter.namespace = C.GoString(c_namespace)
 	return true
 }
@@ -69,6 +72,16 @@ func (iter *Iter) Value() string {
 	return iter.entry
 }
 
+// Key returns the locator key associated with the current value of the
+// iterator (object name), after a successful call to Next. The key is empty
+// unless the object was written with a locator key set on the IOContext.
+func (iter *Iter) Key() string {
+	if iter.err != nil {
+		return ""
+	}
+	return iter.key
+}
+
 // Namespace returns the namespace associated with the current value of the iterator (object name), after a successful call to Next.
 func (iter *Iter) Namespace() string {
 	if iter.err != nil {
@@ -0,0 +1,97 @@
+package rados
+
+// #cgo LDFLAGS: -lrados
+// #include <errno.h>
+// #include <rados/librados.h>
+//
+import "C"
+
+// objectSliceBatchSize is the number of objects requested from the OSDs at
+// a time by ListObjectsSlice.
+const objectSliceBatchSize = 1000
+
+// ObjectItem describes an object visited by ListObjectsSlice.
+type ObjectItem struct {
+	// Oid is the name of the object.
+	Oid string
+	// Namespace is the namespace of the object.
+	Namespace string
+	// Key is the locator key of the object, if any.
+	Key string
+}
+
+// ObjectItemFunc is the type of the function called for each object visited
+// by ListObjectsSlice.
+type ObjectItemFunc func(item ObjectItem)
+
+// ListObjectsSlice lists the objects of slice index out of count slices of
+// the pool associated with the I/O context, calling the provided listFn
+// function for each object. The objects of the pool are divided in count
+// slices by ranges of the hashes of their names, without listing them, so
+// each slice can be listed independently by a separate goroutine or process.
+// Listing all the slices, from 0 to count-1, visits every object exactly
+// once. Objects created or removed while the pool is listed may or may not
+// be visited.
+//
+// As with ListObjects, only the objects in the namespace of the I/O context
+// are listed unless it is set to AllNamespaces.
+//
+// Implements:
+//  void rados_object_list_slice(rados_ioctx_t io,
+//                               const rados_object_list_cursor start,
+//                               const rados_object_list_cursor finish,
+//                               const size_t n, const size_t m,
+//                               rados_object_list_cursor *split_start,
+//                               rados_object_list_cursor *split_finish);
+//  int rados_object_list(rados_ioctx_t io,
+//                        const rados_object_list_cursor start,
+//                        const rados_object_list_cursor finish,
+//                        const size_t result_size, const char *filter_buf,
+//                        const size_t filter_buf_len,
+//                        rados_object_list_item *results,
+//                        rados_object_list_cursor *next);
+func (ioctx *IOContext) ListObjectsSlice(index, count int, listFn ObjectItemFunc) error {
+	if count < 1 || index < 0 || index >= count {
+		return RadosError(-C.EINVAL)
+	}
+
+	begin := C.rados_object_list_begin(ioctx.ioctx)
+	defer C.rados_object_list_cursor_free(ioctx.ioctx, begin)
+	end := C.rados_object_list_end(ioctx.ioctx)
+	defer C.rados_object_list_cursor_free(ioctx.ioctx, end)
+
+	// the slice bounds are written to existing cursors
+	cursor := C.rados_object_list_begin(ioctx.ioctx)
+	defer C.rados_object_list_cursor_free(ioctx.ioctx, cursor)
+	finish := C.rados_object_list_end(ioctx.ioctx)
+	defer C.rados_object_list_cursor_free(ioctx.ioctx, finish)
+	C.rados_object_list_slice(ioctx.ioctx, begin, end,
+		C.size_t(index), C.size_t(count), &cursor, &finish)
+
+	items := make([]C.rados_object_list_item, objectSliceBatchSize)
+	for C.rados_object_list_cursor_cmp(ioctx.ioctx, cursor, finish) < 0 {
+		// the cursor is advanced in place
+		ret := C.rados_object_list(ioctx.ioctx, cursor, finish,
+			C.size_t(len(items)), nil, 0, &items[0], &cursor)
+		if ret < 0 {
+			return getRadosError(int(ret))
+		}
+		n := int(ret)
+		for i := 0; i < n; i++ {
+			listFn(ObjectItem{
+				Oid:       goStringN(items[i].oid, items[i].oid_length),
+				Namespace: goStringN(items[i].nspace, items[i].nspace_length),
+				Key:       goStringN(items[i].locator, items[i].locator_length),
+			})
+		}
+		C.rados_object_list_free(C.size_t(n), &items[0])
+	}
+	return nil
+}
+
+func goStringN(s *C.char, n C.size_t) string {
+	if s == nil {
+		return ""
+	}
+	return C.GoStringN(s, C.int(n))
+}
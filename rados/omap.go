@@ -78,6 +78,13 @@ type OmapListFunc func(key string, value []byte)
 // `maxReturn`: iterate no more than `maxReturn` key/value pairs
 // `listFn`: the function called at each iteration
 func (ioctx *IOContext) ListOmapValues(oid string, startAfter string, filterPrefix string, maxReturn int64, listFn OmapListFunc) error {
+	_, err := ioctx.listOmapValues(oid, startAfter, filterPrefix, maxReturn, listFn)
+	return err
+}
+
+// listOmapValues works like ListOmapValues and also reports whether the
+// omap holds more matching keys than were listed.
+func (ioctx *IOContext) listOmapValues(oid string, startAfter string, filterPrefix string, maxReturn int64, listFn OmapListFunc) (bool, error) {
 	c_oid := C.CString(oid)
 	c_start_after := C.CString(startAfter)
 	c_filter_prefix := C.CString(filterPrefix)
@@ -88,8 +95,10 @@ func (ioctx *IOContext) ListOmapValues(oid string, startAfter string, filterPref
 	defer C.free(unsafe.Pointer(c_filter_prefix))
 
 	op := C.rados_create_read_op()
+	defer C.rados_release_read_op(op)
 
 	var c_iter C.rados_omap_iter_t
+	var c_more C.uchar
 	var c_prval C.int
 	C.rados_read_op_omap_get_vals2(
 		op,
@@ -97,16 +106,18 @@ func (ioctx *IOContext) ListOmapValues(oid string, startAfter string, filterPref
 		c_filter_prefix,
 		c_max_return,
 		&c_iter,
-		nil,
+		&c_more,
 		&c_prval,
 	)
+	// the iterator is allocated with the op, even if the op fails
+	defer C.rados_omap_get_end(c_iter)
 
 	ret := C.rados_read_op_operate(op, ioctx.ioctx, c_oid, 0)
 
 	if int(ret) != 0 {
-		return getRadosError(int(ret))
+		return false, getRadosError(int(ret))
 	} else if int(c_prval) != 0 {
-		return RadosError(int(c_prval))
+		return false, RadosError(int(c_prval))
 	}
 
 	for {
@@ -117,7 +128,7 @@ func (ioctx *IOContext) ListOmapValues(oid string, startAfter string, filterPref
 		ret = C.rados_omap_get_next(c_iter, &c_key, &c_val, &c_len)
 
 		if int(ret) != 0 {
-			return getRadosError(int(ret))
+			return false, getRadosError(int(ret))
 		}
 
 		if c_key == nil {
@@ -127,10 +138,7 @@ func (ioctx *IOContext) ListOmapValues(oid string, startAfter string, filterPref
 		listFn(C.GoString(c_key), C.GoBytes(unsafe.Pointer(c_val), C.int(c_len)))
 	}
 
-	C.rados_omap_get_end(c_iter)
-	C.rados_release_read_op(op)
-
-	return nil
+	return c_more != 0, nil
 }
 
 // GetOmapValues fetches a set of keys and their values from an omap and returns then as a map
@@ -182,6 +190,64 @@ func (ioctx *IOContext) GetAllOmapValues(oid string, startAfter string, filterPr
 	return omap, nil
 }
 
+// GetOmapValuesByKeys fetches the values of the given `keys` from the omap
+// `oid` and returns them as a map. Keys that are not set in the omap are
+// omitted from the returned map.
+func (ioctx *IOContext) GetOmapValuesByKeys(oid string, keys []string) (map[string][]byte, error) {
+	c_oid := C.CString(oid)
+	defer C.free(unsafe.Pointer(c_oid))
+
+	var c *C.char
+	ptrSize := unsafe.Sizeof(c)
+
+	c_keys := C.malloc(C.size_t(len(keys)) * C.size_t(ptrSize))
+	defer C.free(unsafe.Pointer(c_keys))
+
+	for i, key := range keys {
+		c_key_ptr := (**C.char)(unsafe.Pointer(uintptr(c_keys) + uintptr(i)*ptrSize))
+		*c_key_ptr = C.CString(key)
+		defer C.free(unsafe.Pointer(*c_key_ptr))
+	}
+
+	op := C.rados_create_read_op()
+	defer C.rados_release_read_op(op)
+
+	var c_iter C.rados_omap_iter_t
+	var c_prval C.int
+	C.rados_read_op_omap_get_vals_by_keys(
+		op,
+		(**C.char)(c_keys),
+		C.size_t(len(keys)),
+		&c_iter,
+		&c_prval,
+	)
+	// the iterator is allocated with the op, even if the op fails
+	defer C.rados_omap_get_end(c_iter)
+
+	ret := C.rados_read_op_operate(op, ioctx.ioctx, c_oid, 0)
+	if int(ret) != 0 {
+		return nil, getRadosError(int(ret))
+	} else if int(c_prval) != 0 {
+		return nil, RadosError(int(c_prval))
+	}
+
+	omap := map[string][]byte{}
+	for {
+		var c_key *C.char
+		var c_val *C.char
+		var c_len C.size_t
+
+		ret = C.rados_omap_get_next(c_iter, &c_key, &c_val, &c_len)
+		if int(ret) != 0 {
+			return nil, getRadosError(int(ret))
+		}
+		if c_key == nil {
+			return omap, nil
+		}
+		omap[C.GoString(c_key)] = C.GoBytes(unsafe.Pointer(c_val), C.int(c_len))
+	}
+}
+
 // RmOmapKeys removes the specified `keys` from the omap `oid`
 func (ioctx *IOContext) RmOmapKeys(oid string, keys []string) error {
 	c_oid := C.CString(oid)
@@ -0,0 +1,112 @@
+package rados
+
+// #include <errno.h>
+import "C"
+
+// omapEntry is a key and value fetched by an OmapIter.
+type omapEntry struct {
+	key   string
+	value []byte
+}
+
+// OmapIter supports iterating over the keys and values of an omap. The
+// keys are fetched from the OSD in batches, so omaps of any size can be
+// scanned without holding all of their keys in memory.
+type OmapIter struct {
+	ioctx        *IOContext
+	oid          string
+	startAfter   string
+	filterPrefix string
+	batchSize    int64
+
+	entries []omapEntry
+	index   int
+	more    bool
+	err     error
+}
+
+// IterOmap returns an OmapIter over the keys and values of the omap `oid`,
+// in key order.
+//
+// `startAfter`: iterate only on the keys after this specified one
+// `filterPrefix`: iterate only on the keys beginning with this prefix
+// `batchSize`: number of keys to fetch from the OSD at a time
+//
+// Example:
+//	iter := ioctx.IterOmap(oid, "", "prefix-", 1000)
+//	for iter.Next() {
+//		fmt.Printf("%v: %v\n", iter.Key(), iter.Value())
+//	}
+//	return iter.Err()
+//
+func (ioctx *IOContext) IterOmap(oid, startAfter, filterPrefix string, batchSize int64) *OmapIter {
+	return &OmapIter{
+		ioctx:        ioctx,
+		oid:          oid,
+		startAfter:   startAfter,
+		filterPrefix: filterPrefix,
+		batchSize:    batchSize,
+		// the first batch has not been fetched yet
+		more: true,
+	}
+}
+
+// Next advances the iterator to the next key of the omap, fetching the next
+// batch of keys when needed. Upon a successful invocation (return value of
+// true), the Key and Value methods return the current key and its value.
+// When the iterator is exhausted, or fails, Next returns false. The Err
+// method should be used to verify whether the iterator received an error.
+func (iter *OmapIter) Next() bool {
+	if iter.err != nil {
+		return false
+	}
+	iter.index++
+	if iter.index < len(iter.entries) {
+		return true
+	}
+	if !iter.more {
+		return false
+	}
+	if iter.batchSize < 1 {
+		iter.err = RadosError(-C.EINVAL)
+		return false
+	}
+
+	iter.entries = iter.entries[:0]
+	iter.index = 0
+	iter.more, iter.err = iter.ioctx.listOmapValues(
+		iter.oid, iter.startAfter, iter.filterPrefix, iter.batchSize,
+		func(key string, value []byte) {
+			iter.entries = append(iter.entries, omapEntry{key, value})
+		})
+	if iter.err != nil || len(iter.entries) == 0 {
+		iter.more = false
+		return false
+	}
+	// the next batch starts after the last key of this one
+	iter.startAfter = iter.entries[len(iter.entries)-1].key
+	return true
+}
+
+// Key returns the current key of the iterator, after a successful call to
+// Next.
+func (iter *OmapIter) Key() string {
+	if iter.index >= len(iter.entries) {
+		return ""
+	}
+	return iter.entries[iter.index].key
+}
+
+// Value returns the value of the current key of the iterator, after a
+// successful call to Next.
+func (iter *OmapIter) Value() []byte {
+	if iter.index >= len(iter.entries) {
+		return nil
+	}
+	return iter.entries[iter.index].value
+}
+
+// Err returns the error, if any, encountered by the iterator.
+func (iter *OmapIter) Err() error {
+	return iter.err
+}
@@ -0,0 +1,155 @@
+package rados
+
+// #include <errno.h>
+import "C"
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// PoolType is the data protection scheme of a pool.
+type PoolType string
+
+const (
+	// PoolTypeReplicated pools store full copies of each object.
+	PoolTypeReplicated = PoolType("replicated")
+	// PoolTypeErasure pools store objects as erasure coded chunks.
+	PoolTypeErasure = PoolType("erasure")
+)
+
+// PoolAutoscaleMode controls whether the number of placement groups of a
+// pool is adjusted automatically.
+type PoolAutoscaleMode string
+
+const (
+	// PoolAutoscaleOn adjusts the number of placement groups automatically.
+	PoolAutoscaleOn = PoolAutoscaleMode("on")
+	// PoolAutoscaleOff disables the placement group autoscaler.
+	PoolAutoscaleOff = PoolAutoscaleMode("off")
+	// PoolAutoscaleWarn raises a health warning when the number of
+	// placement groups should be adjusted.
+	PoolAutoscaleWarn = PoolAutoscaleMode("warn")
+)
+
+// PoolOptions are the settings of a pool created by MakePoolWithOptions.
+// Fields left to their zero value use the defaults of the cluster.
+type PoolOptions struct {
+	// Type of the pool, PoolTypeReplicated if empty.
+	Type PoolType
+	// CrushRule is the name of the CRUSH rule placing the data of the
+	// pool. For erasure coded pools a rule is created from the erasure
+	// code profile if empty.
+	CrushRule string
+	// ErasureCodeProfile is the name of the erasure code profile of an
+	// erasure coded pool.
+	ErasureCodeProfile string
+	// PgNum is the initial number of placement groups. It must be set for
+	// clusters older than Ceph Nautilus.
+	PgNum int
+	// Size is the number of replicas of a replicated pool.
+	Size int
+	// AutoscaleMode is the placement group autoscaler mode of the pool.
+	// It requires Ceph Nautilus or later.
+	AutoscaleMode PoolAutoscaleMode
+}
+
+// MakePoolWithOptions creates a new pool with the given settings. The pool
+// is created and configured with monitor commands, and the call returns
+// once the OSD map of the connection includes the pool, so that an
+// IOContext can be opened on it right away. An error is returned if a pool
+// of that name exists. If the pool was created, but could not be
+// configured, it is not removed.
+//
+// Similar To:
+//  ceph osd pool create <name> [<pg_num>] [replicated|erasure]
+//       [<erasure_code_profile>] [<rule>]
+//  ceph osd pool set <name> size <size>
+//  ceph osd pool set <name> pg_autoscale_mode <mode>
+func (c *Conn) MakePoolWithOptions(name string, opts PoolOptions) error {
+	if err := c.ensure_connected(); err != nil {
+		return err
+	}
+	errInvalid := RadosError(-C.EINVAL)
+	if name == "" || opts.PgNum < 0 || opts.Size < 0 {
+		return errInvalid
+	}
+	poolType := opts.Type
+	if poolType == "" {
+		poolType = PoolTypeReplicated
+	}
+	switch poolType {
+	case PoolTypeReplicated:
+		if opts.ErasureCodeProfile != "" {
+			return errInvalid
+		}
+	case PoolTypeErasure:
+		if opts.Size != 0 {
+			return errInvalid
+		}
+	default:
+		return errInvalid
+	}
+	switch opts.AutoscaleMode {
+	case "", PoolAutoscaleOn, PoolAutoscaleOff, PoolAutoscaleWarn:
+	default:
+		return errInvalid
+	}
+
+	// the monitors report success when creating an existing pool
+	if _, err := c.GetPoolByName(name); err == nil {
+		return RadosError(-C.EEXIST)
+	} else if err != ErrNotFound {
+		return err
+	}
+
+	cmd := map[string]interface{}{
+		"prefix":    "osd pool create",
+		"pool":      name,
+		"pool_type": string(poolType),
+	}
+	if opts.PgNum > 0 {
+		cmd["pg_num"] = opts.PgNum
+	}
+	if opts.CrushRule != "" {
+		cmd["rule"] = opts.CrushRule
+	}
+	if opts.ErasureCodeProfile != "" {
+		cmd["erasure_code_profile"] = opts.ErasureCodeProfile
+	}
+	if err := c.monCommandJSON(cmd); err != nil {
+		return err
+	}
+
+	if opts.Size > 0 {
+		err := c.setPoolValue(name, "size", strconv.Itoa(opts.Size))
+		if err != nil {
+			return err
+		}
+	}
+	if opts.AutoscaleMode != "" {
+		err := c.setPoolValue(name, "pg_autoscale_mode", string(opts.AutoscaleMode))
+		if err != nil {
+			return err
+		}
+	}
+	return c.WaitForLatestOSDMap()
+}
+
+func (c *Conn) setPoolValue(name, key, value string) error {
+	return c.monCommandJSON(map[string]interface{}{
+		"prefix": "osd pool set",
+		"pool":   name,
+		"var":    key,
+		"val":    value,
+	})
+}
+
+func (c *Conn) monCommandJSON(cmd map[string]interface{}) error {
+	args, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.monCommand(args, nil)
+	return err
+}
@@ -9,6 +9,8 @@ import (
 	"os"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -291,6 +293,59 @@ func (suite *RadosTestSuite) TestMakeDeletePool() {
 	}
 }
 
+func (suite *RadosTestSuite) TestMakePoolWithOptions() {
+	suite.SetupConnection()
+
+	new_name := uuid.Must(uuid.NewV4()).String()
+	err := suite.conn.MakePoolWithOptions(new_name, PoolOptions{
+		Type:  PoolTypeReplicated,
+		PgNum: 8,
+		Size:  2,
+	})
+	require.NoError(suite.T(), err)
+	defer suite.conn.DeletePool(new_name)
+
+	getValue := func(key string) int {
+		command, err := json.Marshal(map[string]string{
+			"prefix": "osd pool get",
+			"pool":   new_name,
+			"var":    key,
+			"format": "json",
+		})
+		require.NoError(suite.T(), err)
+		buf, _, err := suite.conn.MonCommand(command)
+		require.NoError(suite.T(), err)
+		values := map[string]interface{}{}
+		require.NoError(suite.T(), json.Unmarshal(buf, &values))
+		v, ok := values[key].(float64)
+		require.True(suite.T(), ok)
+		return int(v)
+	}
+	assert.Equal(suite.T(), 8, getValue("pg_num"))
+	assert.Equal(suite.T(), 2, getValue("size"))
+
+	// the pool can be used right away
+	ioctx, err := suite.conn.OpenIOContext(new_name)
+	require.NoError(suite.T(), err)
+	defer ioctx.Destroy()
+	assert.NoError(suite.T(), ioctx.Write("obj", []byte("input data"), 0))
+
+	err = suite.conn.MakePoolWithOptions(new_name, PoolOptions{PgNum: 8})
+	assert.Equal(suite.T(), RadosError(-17), err) // -EEXIST
+
+	invalid := []PoolOptions{
+		{PgNum: -1},
+		{Type: "mirrored"},
+		{Type: PoolTypeErasure, Size: 3},
+		{Type: PoolTypeReplicated, ErasureCodeProfile: "default"},
+		{AutoscaleMode: "sometimes"},
+	}
+	for _, opts := range invalid {
+		err = suite.conn.MakePoolWithOptions(uuid.Must(uuid.NewV4()).String(), opts)
+		assert.Equal(suite.T(), RadosError(-22), err)
+	}
+}
+
 func (suite *RadosTestSuite) TestGetPoolByName() {
 	suite.SetupConnection()
 
@@ -508,6 +563,97 @@ func (suite *RadosTestSuite) TestAppend() {
 	}
 }
 
+func (suite *RadosTestSuite) TestWriteEmpty() {
+	suite.SetupConnection()
+
+	oid := suite.GenObjectName()
+	err := suite.ioctx.WriteFull(oid, suite.RandomBytes(64))
+	assert.NoError(suite.T(), err)
+
+	err = suite.ioctx.Append(oid, []byte{})
+	assert.NoError(suite.T(), err)
+	stat, err := suite.ioctx.Stat(oid)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint64(64), stat.Size)
+
+	// writing an empty object truncates it
+	err = suite.ioctx.WriteFull(oid, nil)
+	assert.NoError(suite.T(), err)
+	stat, err = suite.ioctx.Stat(oid)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint64(0), stat.Size)
+}
+
+func (suite *RadosTestSuite) TestWriteSame() {
+	suite.SetupConnection()
+
+	oid := suite.GenObjectName()
+	pattern := []byte("0123456789abcdef")
+	err := suite.ioctx.WriteSame(oid, pattern, 64*uint64(len(pattern)), 8)
+	assert.NoError(suite.T(), err)
+
+	stat, err := suite.ioctx.Stat(oid)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint64(8+64*len(pattern)), stat.Size)
+
+	buf := make([]byte, stat.Size)
+	n, err := suite.ioctx.Read(oid, buf, 0)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), len(buf), n)
+	assert.Equal(suite.T(), make([]byte, 8), buf[:8])
+	for i := 8; i < len(buf); i += len(pattern) {
+		assert.Equal(suite.T(), pattern, buf[i:i+len(pattern)])
+	}
+
+	// the write length must be a multiple of the pattern length
+	err = suite.ioctx.WriteSame(oid, pattern, 20, 0)
+	assert.Equal(suite.T(), RadosError(-22), err)
+	err = suite.ioctx.WriteSame(oid, nil, 20, 0)
+	assert.Equal(suite.T(), RadosError(-22), err)
+	err = suite.ioctx.WriteSame(oid, nil, 0, 0)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *RadosTestSuite) TestTruncateAndZero() {
+	suite.SetupConnection()
+
+	oid := suite.GenObjectName()
+	bytes := suite.RandomBytes(4096)
+	err := suite.ioctx.Write(oid, bytes, 0)
+	assert.NoError(suite.T(), err)
+
+	err = suite.ioctx.Zero(oid, 1024, 1024)
+	assert.NoError(suite.T(), err)
+	copy(bytes[1024:2048], make([]byte, 1024))
+
+	// zeroing beyond the end does not grow the object
+	err = suite.ioctx.Zero(oid, 3072, 4096)
+	assert.NoError(suite.T(), err)
+	copy(bytes[3072:], make([]byte, 1024))
+
+	stat, err := suite.ioctx.Stat(oid)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint64(4096), stat.Size)
+	buf := make([]byte, 4096)
+	n, err := suite.ioctx.Read(oid, buf, 0)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4096, n)
+	assert.Equal(suite.T(), bytes, buf)
+
+	err = suite.ioctx.Truncate(oid, 512)
+	assert.NoError(suite.T(), err)
+	stat, err = suite.ioctx.Stat(oid)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint64(512), stat.Size)
+
+	err = suite.ioctx.Truncate(oid, 8192)
+	assert.NoError(suite.T(), err)
+	n, err = suite.ioctx.Read(oid, buf, 4096)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4096, n)
+	assert.Equal(suite.T(), make([]byte, 4096), buf)
+}
+
 func (suite *RadosTestSuite) TestReadNotFound() {
 	suite.SetupConnection()
 
@@ -647,6 +793,34 @@ func (suite *RadosTestSuite) TestMonCommandWithInputBuffer() {
 		string(buf[:]))
 }
 
+func (suite *RadosTestSuite) TestMgrCommand() {
+	suite.SetupConnection()
+
+	command, err := json.Marshal(
+		map[string]string{"prefix": "pg stat", "format": "json"})
+	assert.NoError(suite.T(), err)
+
+	buf, info, err := suite.conn.MgrCommand([][]byte{command})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "", info)
+
+	var message map[string]interface{}
+	err = json.Unmarshal(buf, &message)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *RadosTestSuite) TestMgrCommandInvalid() {
+	suite.SetupConnection()
+
+	command, err := json.Marshal(
+		map[string]string{"prefix": "no such command", "format": "json"})
+	assert.NoError(suite.T(), err)
+
+	_, info, err := suite.conn.MgrCommand([][]byte{command})
+	assert.Error(suite.T(), err)
+	assert.NotEqual(suite.T(), "", info)
+}
+
 func (suite *RadosTestSuite) TestPGCommand() {
 	suite.SetupConnection()
 
@@ -752,6 +926,37 @@ func (suite *RadosTestSuite) TestObjectIterator() {
 	assert.Equal(suite.T(), currObjectList, expectedObjectList)
 }
 
+func (suite *RadosTestSuite) TestObjectIteratorLocatorKey() {
+	suite.SetupConnection()
+
+	// use a namespace of its own to only list the objects created here
+	suite.ioctx.SetNamespace("nsLocator")
+	defer suite.ioctx.SetNamespace("")
+
+	plain := suite.GenObjectName()
+	err := suite.ioctx.Write(plain, []byte("input data"), 0)
+	assert.NoError(suite.T(), err)
+
+	located := suite.GenObjectName()
+	suite.ioctx.SetLocatorKey("locator")
+	err = suite.ioctx.Write(located, []byte("input data"), 0)
+	assert.NoError(suite.T(), err)
+	_, err = suite.ioctx.Stat(located)
+	assert.NoError(suite.T(), err)
+	suite.ioctx.SetLocatorKey("")
+
+	keys := map[string]string{}
+	iter, err := suite.ioctx.Iter()
+	assert.NoError(suite.T(), err)
+	for iter.Next() {
+		keys[iter.Value()] = iter.Key()
+		assert.Equal(suite.T(), "nsLocator", iter.Namespace())
+	}
+	iter.Close()
+	assert.NoError(suite.T(), iter.Err())
+	assert.Equal(suite.T(), map[string]string{plain: "", located: "locator"}, keys)
+}
+
 func (suite *RadosTestSuite) TestObjectIteratorAcrossNamespaces() {
 	suite.SetupConnection()
 
@@ -829,6 +1034,63 @@ func (suite *RadosTestSuite) TestObjectIteratorAcrossNamespaces() {
 	assert.Equal(suite.T(), rogueList, existingList)
 }
 
+func (suite *RadosTestSuite) TestListObjectsSlice() {
+	suite.SetupConnection()
+
+	// use a namespace of its own to only list the objects created here
+	suite.ioctx.SetNamespace("nsSlice")
+	defer suite.ioctx.SetNamespace("")
+
+	expected := []string{}
+	for i := 0; i < 30; i++ {
+		oid := suite.GenObjectName()
+		err := suite.ioctx.Write(oid, []byte("input data"), 0)
+		assert.NoError(suite.T(), err)
+		expected = append(expected, oid)
+	}
+
+	const count = 4
+	slices := make([][]string, count)
+	errs := make([]error, count)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = suite.ioctx.ListObjectsSlice(i, count, func(item ObjectItem) {
+				slices[i] = append(slices[i], item.Oid)
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	listed := []string{}
+	for i := 0; i < count; i++ {
+		assert.NoError(suite.T(), errs[i])
+		listed = append(listed, slices[i]...)
+	}
+	sort.Strings(expected)
+	sort.Strings(listed)
+	assert.Equal(suite.T(), expected, listed)
+
+	suite.ioctx.SetNamespace(AllNamespaces)
+	found := 0
+	for i := 0; i < count; i++ {
+		err := suite.ioctx.ListObjectsSlice(i, count, func(item ObjectItem) {
+			if item.Namespace == "nsSlice" {
+				found++
+			}
+		})
+		assert.NoError(suite.T(), err)
+	}
+	assert.Equal(suite.T(), len(expected), found)
+
+	err := suite.ioctx.ListObjectsSlice(count, count, func(ObjectItem) {})
+	assert.Equal(suite.T(), RadosError(-22), err)
+	err = suite.ioctx.ListObjectsSlice(0, 0, func(ObjectItem) {})
+	assert.Equal(suite.T(), RadosError(-22), err)
+}
+
 func (suite *RadosTestSuite) TestNewConnWithUser() {
 	_, err := NewConnWithUser("admin")
 	assert.Equal(suite.T(), err, nil)
@@ -855,6 +1117,25 @@ func (suite *RadosTestSuite) TestReadWriteXattr() {
 	assert.Equal(suite.T(), out, val)
 }
 
+func (suite *RadosTestSuite) TestEmptyXattr() {
+	suite.SetupConnection()
+
+	oid := suite.GenObjectName()
+	err := suite.ioctx.SetXattr(oid, "empty", []byte{})
+	assert.NoError(suite.T(), err)
+
+	n, err := suite.ioctx.GetXattr(oid, "empty", nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, n)
+
+	out, err := suite.ioctx.ListXattrs(oid)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[string][]byte{"empty": {}}, out)
+
+	_, err = suite.ioctx.GetXattr(oid, "missing", nil)
+	assert.Equal(suite.T(), RadosError(-61), err) // -ENODATA
+}
+
 func (suite *RadosTestSuite) TestListXattrs() {
 	suite.SetupConnection()
 
@@ -961,6 +1242,14 @@ func (suite *RadosTestSuite) TestReadWriteOmap() {
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), orig, fetched)
 
+	// Get by keys (missing keys are left out)
+	fetched, err = suite.ioctx.GetOmapValuesByKeys(oid, []string{"key2", "empty", "missing"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[string][]byte{
+		"key2":  []byte("value2"),
+		"empty": []byte(""),
+	}, fetched)
+
 	// Remove
 	err = suite.ioctx.RmOmapKeys(oid, []string{"key1", "prefixed-key3"})
 	assert.NoError(suite.T(), err)
@@ -1017,6 +1306,61 @@ func (suite *RadosTestSuite) TestReadFilterOmap() {
 	}, fetched)
 }
 
+func (suite *RadosTestSuite) TestIterOmap() {
+	suite.SetupConnection()
+
+	oid := suite.GenObjectName()
+	orig := map[string][]byte{}
+	for i := 0; i < 50; i++ {
+		orig[fmt.Sprintf("a-%03d", i)] = []byte(fmt.Sprintf("value-a-%d", i))
+		orig[fmt.Sprintf("b-%03d", i)] = []byte(fmt.Sprintf("value-b-%d", i))
+	}
+	err := suite.ioctx.SetOmap(oid, orig)
+	assert.NoError(suite.T(), err)
+
+	collect := func(startAfter, filterPrefix string, batchSize int64) ([]string, map[string][]byte, error) {
+		keys := []string{}
+		values := map[string][]byte{}
+		iter := suite.ioctx.IterOmap(oid, startAfter, filterPrefix, batchSize)
+		for iter.Next() {
+			keys = append(keys, iter.Key())
+			values[iter.Key()] = iter.Value()
+		}
+		return keys, values, iter.Err()
+	}
+
+	for _, batchSize := range []int64{1, 7, 100, 1000} {
+		keys, values, err := collect("", "", batchSize)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), orig, values)
+		assert.True(suite.T(), sort.StringsAreSorted(keys))
+	}
+
+	keys, values, err := collect("", "b-", 7)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), keys, 50)
+	for _, k := range keys {
+		assert.True(suite.T(), strings.HasPrefix(k, "b-"))
+		assert.Equal(suite.T(), orig[k], values[k])
+	}
+
+	keys, _, err = collect("a-039", "a-", 3)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 10, len(keys))
+	assert.Equal(suite.T(), "a-040", keys[0])
+
+	keys, _, err = collect("", "c-", 3)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), keys, 0)
+
+	_, _, err = collect("", "", 0)
+	assert.Equal(suite.T(), RadosError(-22), err)
+
+	iter := suite.ioctx.IterOmap(suite.GenObjectName(), "", "", 10)
+	assert.False(suite.T(), iter.Next())
+	assert.Equal(suite.T(), ErrNotFound, iter.Err())
+}
+
 func (suite *RadosTestSuite) TestSetNamespace() {
 	suite.SetupConnection()
 
@@ -1041,15 +1385,39 @@ func (suite *RadosTestSuite) TestSetNamespace() {
 	err = suite.ioctx.Write(oid2, bytes_in, 0)
 	assert.NoError(suite.T(), err)
 
+	ns, err := suite.ioctx.GetNamespace()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "space1", ns)
+
 	suite.ioctx.SetNamespace("")
 	stat, err = suite.ioctx.Stat(oid2)
 	assert.Equal(suite.T(), err, ErrNotFound)
 
+	ns, err = suite.ioctx.GetNamespace()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "", ns)
+
 	stat, err = suite.ioctx.Stat(oid)
 	assert.Equal(suite.T(), uint64(len(bytes_in)), stat.Size)
 	assert.NotNil(suite.T(), stat.ModTime)
 }
 
+func (suite *RadosTestSuite) TestGetLongNamespace() {
+	suite.SetupConnection()
+
+	long := strings.Repeat("namespace", 64)
+	suite.ioctx.SetNamespace(long)
+	defer suite.ioctx.SetNamespace("")
+	ns, err := suite.ioctx.GetNamespace()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), long, ns)
+
+	suite.ioctx.SetNamespace(AllNamespaces)
+	ns, err = suite.ioctx.GetNamespace()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), AllNamespaces, ns)
+}
+
 func (suite *RadosTestSuite) TestListAcrossNamespaces() {
 	suite.SetupConnection()
 
@@ -1178,6 +1546,29 @@ func (suite *RadosTestSuite) TestLocking() {
 	info, err = suite.ioctx.ListLockers(oid, "myLock")
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 0, len(info.Clients))
+
+	// lock ex with duration and renew it before it expires
+	res, err = suite.ioctx.LockExclusive(oid, "myLock", "myCookie", "a description", 2*time.Second, nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, res)
+	var renew byte = LockFlagRenew
+	for i := 0; i < 3; i++ {
+		time.Sleep(time.Second)
+		res, err = suite.ioctx.LockExclusive(oid, "myLock", "myCookie", "a description", 2*time.Second, &renew)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), 0, res)
+	}
+
+	// verify lock ex is still held
+	info, err = suite.ioctx.ListLockers(oid, "myLock")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, len(info.Clients))
+
+	// verify lock ex expired once no longer renewed
+	time.Sleep(3 * time.Second)
+	info, err = suite.ioctx.ListLockers(oid, "myLock")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, len(info.Clients))
 }
 
 func (suite *RadosTestSuite) TestOmapOnNonexistentObjectError() {
@@ -1185,6 +1576,8 @@ func (suite *RadosTestSuite) TestOmapOnNonexistentObjectError() {
 	oid := suite.GenObjectName()
 	_, err := suite.ioctx.GetAllOmapValues(oid, "", "", 100)
 	assert.Equal(suite.T(), err, ErrNotFound)
+	_, err = suite.ioctx.GetOmapValuesByKeys(oid, []string{"key"})
+	assert.Equal(suite.T(), err, ErrNotFound)
 }
 
 func (suite *RadosTestSuite) TestOpenIOContextInvalidPool() {
@@ -1193,6 +1586,92 @@ func (suite *RadosTestSuite) TestOpenIOContextInvalidPool() {
 	require.Nil(suite.T(), ioctx)
 }
 
+func (suite *RadosTestSuite) TestWatchNotify() {
+	suite.SetupConnection()
+
+	oid := suite.GenObjectName()
+	_, err := suite.ioctx.Watch(oid)
+	assert.Equal(suite.T(), ErrNotFound, err)
+
+	err = suite.ioctx.Create(oid, CreateExclusive)
+	require.NoError(suite.T(), err)
+	w, err := suite.ioctx.Watch(oid)
+	require.NoError(suite.T(), err)
+	_, err = w.Check()
+	assert.NoError(suite.T(), err)
+
+	received := make(chan NotifyEvent, 1)
+	go func() {
+		for ev := range w.Events() {
+			assert.NoError(suite.T(), ev.Ack(append([]byte("ack-"), ev.Data...)))
+			received <- ev
+		}
+	}()
+
+	acks, err := suite.ioctx.Notify(oid, []byte("hello"))
+	assert.NoError(suite.T(), err)
+	ev := <-received
+	assert.Equal(suite.T(), []byte("hello"), ev.Data)
+	assert.Equal(suite.T(), w.ID(), ev.WatcherID)
+	assert.Equal(suite.T(), suite.conn.GetInstanceID(), ev.NotifierID)
+	if assert.Len(suite.T(), acks, 1) {
+		assert.Equal(suite.T(), w.ID(), acks[0].WatcherID)
+		assert.Equal(suite.T(), suite.conn.GetInstanceID(), acks[0].NotifierID)
+		assert.Equal(suite.T(), []byte("ack-hello"), acks[0].Response)
+	}
+
+	assert.NoError(suite.T(), w.Delete())
+	_, open := <-w.Events()
+	assert.False(suite.T(), open)
+	_, open = <-w.Errors()
+	assert.False(suite.T(), open)
+	// deleting twice is harmless
+	assert.NoError(suite.T(), w.Delete())
+
+	acks, err = suite.ioctx.Notify(oid, nil)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), acks, 0)
+}
+
+func (suite *RadosTestSuite) TestNotifyWithTimeout() {
+	suite.SetupConnection()
+
+	oid := suite.GenObjectName()
+	err := suite.ioctx.Create(oid, CreateExclusive)
+	require.NoError(suite.T(), err)
+
+	acking, err := suite.ioctx.Watch(oid)
+	require.NoError(suite.T(), err)
+	defer acking.Delete()
+	go func() {
+		for ev := range acking.Events() {
+			assert.NoError(suite.T(), ev.Ack([]byte("pong")))
+		}
+	}()
+	silent, err := suite.ioctx.Watch(oid)
+	require.NoError(suite.T(), err)
+	defer silent.Delete()
+	go func() {
+		// drain the notifications without acknowledging them
+		for range silent.Events() {
+		}
+	}()
+
+	acks, timeouts, err := suite.ioctx.NotifyWithTimeout(oid, []byte("ping"), time.Second)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), acks, 1) {
+		assert.Equal(suite.T(), acking.ID(), acks[0].WatcherID)
+		assert.Equal(suite.T(), []byte("pong"), acks[0].Response)
+	}
+	if assert.Len(suite.T(), timeouts, 1) {
+		assert.Equal(suite.T(), silent.ID(), timeouts[0].WatcherID)
+		assert.Equal(suite.T(), suite.conn.GetInstanceID(), timeouts[0].NotifierID)
+	}
+
+	_, _, err = suite.ioctx.NotifyWithTimeout(oid, nil, -time.Second)
+	assert.Equal(suite.T(), RadosError(-22), err)
+}
+
 func TestRadosTestSuite(t *testing.T) {
 	suite.Run(t, new(RadosTestSuite))
 }
@@ -1209,3 +1688,37 @@ func TestRadosError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, err.Error(), "rados: ret=345")
 }
+
+func TestDecodeNotifyReply(t *testing.T) {
+	acks, timeouts, err := decodeNotifyReply(nil)
+	assert.NoError(t, err)
+	assert.Len(t, acks, 0)
+	assert.Len(t, timeouts, 0)
+
+	reply := []byte{
+		2, 0, 0, 0, // num_acks
+		0x10, 0x27, 0, 0, 0, 0, 0, 0, // gid
+		1, 0, 0, 0, 0, 0, 0, 0, // cookie
+		2, 0, 0, 0, 'o', 'k', // payload
+		0x11, 0x27, 0, 0, 0, 0, 0, 0, // gid
+		2, 0, 0, 0, 0, 0, 0, 0, // cookie
+		0, 0, 0, 0, // empty payload
+		1, 0, 0, 0, // num_timeouts
+		0x12, 0x27, 0, 0, 0, 0, 0, 0, // gid
+		3, 0, 0, 0, 0, 0, 0, 0, // cookie
+	}
+	acks, timeouts, err = decodeNotifyReply(reply)
+	assert.NoError(t, err)
+	assert.Equal(t, []NotifyAck{
+		{WatcherID: 1, NotifierID: 10000, Response: []byte("ok")},
+		{WatcherID: 2, NotifierID: 10001},
+	}, acks)
+	assert.Equal(t, []NotifyTimeout{
+		{WatcherID: 3, NotifierID: 10002},
+	}, timeouts)
+
+	for _, l := range []int{3, 30, 50, len(reply) - 1} {
+		_, _, err = decodeNotifyReply(reply[:l])
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,362 @@
+package rados
+
+// #cgo LDFLAGS: -lrados
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <rados/librados.h>
+//
+// extern void watchNotifyCallback(void *arg, uint64_t notify_id, uint64_t handle,
+//                                 uint64_t notifier_id, void *data, size_t data_len);
+// extern void watchErrorCallback(void *pre, uint64_t cookie, int err);
+import "C"
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// NotifyEvent is a notification received by a Watcher.
+type NotifyEvent struct {
+	// ID identifies the notification.
+	ID uint64
+	// WatcherID identifies the watch that received the notification.
+	WatcherID uint64
+	// NotifierID is the instance ID of the client that sent the
+	// notification.
+	NotifierID uint64
+	// Data is the payload of the notification.
+	Data []byte
+
+	watcher *Watcher
+}
+
+// NotifyAck is the acknowledgement of a notification by a watcher.
+type NotifyAck struct {
+	// WatcherID identifies the watch that acknowledged the notification.
+	WatcherID uint64
+	// NotifierID is the instance ID of the client owning the watch.
+	NotifierID uint64
+	// Response is the payload passed to NotifyEvent.Ack, if any.
+	Response []byte
+}
+
+// Watcher receives the notifications sent to an object watched with
+// IOContext.Watch.
+type Watcher struct {
+	ioctx  *IOContext
+	oid    string
+	id     C.uint64_t
+	arg    unsafe.Pointer
+	events chan NotifyEvent
+	errors chan error
+	done   chan struct{}
+	once   sync.Once
+}
+
+// watchers maps the address of the C memory passed as argument of the watch
+// callbacks to the Watcher they deliver to. C memory can not refer to Go
+// memory, so the address is used to find the Go side.
+var watchers = struct {
+	sync.RWMutex
+	m map[uintptr]*Watcher
+}{m: map[uintptr]*Watcher{}}
+
+// Watch starts watching the object with key oid for notifications, which
+// are sent to all the watchers of an object with Notify. The notifications
+// are delivered on the channel returned by the Events method of the
+// Watcher, and each of them must be acknowledged with NotifyEvent.Ack,
+// otherwise the notifier waits until it times out. Errors affecting the
+// watch, such as the loss of the connection to the OSD, are delivered on
+// the channel returned by the Errors method. The watch may have been lost
+// at that point, in which case the Watcher must be deleted and the object
+// watched again.
+//
+// The channels must be drained: the notifications and errors of all the
+// watches of a connection are delivered by the same librados thread, which
+// waits for each of them to be received. Delete must be called to stop
+// watching.
+//
+// Implements:
+//  int rados_watch2(rados_ioctx_t io, const char *o, uint64_t *cookie,
+//                   rados_watchcb2_t watchcb, rados_watcherrcb_t watcherrcb,
+//                   void *arg);
+func (ioctx *IOContext) Watch(oid string) (*Watcher, error) {
+	c_oid := C.CString(oid)
+	defer C.free(unsafe.Pointer(c_oid))
+
+	w := &Watcher{
+		ioctx:  ioctx,
+		oid:    oid,
+		arg:    C.malloc(1),
+		events: make(chan NotifyEvent),
+		errors: make(chan error),
+		done:   make(chan struct{}),
+	}
+	// a notification may arrive before rados_watch2 returns, so the watcher
+	// must be registered first
+	key := uintptr(w.arg)
+	watchers.Lock()
+	watchers.m[key] = w
+	watchers.Unlock()
+
+	ret := C.rados_watch2(ioctx.ioctx, c_oid, &w.id,
+		C.rados_watchcb2_t(C.watchNotifyCallback),
+		C.rados_watcherrcb_t(C.watchErrorCallback),
+		w.arg)
+	if ret < 0 {
+		watchers.Lock()
+		delete(watchers.m, key)
+		watchers.Unlock()
+		C.free(w.arg)
+		return nil, getRadosError(int(ret))
+	}
+	return w, nil
+}
+
+// ID returns the identifier of the watch, which is the WatcherID of the
+// notifications it receives and of its acknowledgements.
+func (w *Watcher) ID() uint64 {
+	return uint64(w.id)
+}
+
+// Events returns the channel on which the notifications are delivered. It
+// is closed once the Watcher is deleted.
+func (w *Watcher) Events() <-chan NotifyEvent {
+	return w.events
+}
+
+// Errors returns the channel on which the errors affecting the watch are
+// delivered. It is closed once the Watcher is deleted.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Check returns the time since the watch was last confirmed by the OSD, or
+// an error if the watch is not established.
+//
+// Implements:
+//  int rados_watch_check(rados_ioctx_t io, uint64_t cookie);
+func (w *Watcher) Check() (time.Duration, error) {
+	ret := C.rados_watch_check(w.ioctx.ioctx, w.id)
+	if ret < 0 {
+		return 0, getRadosError(int(ret))
+	}
+	return time.Duration(ret) * time.Millisecond, nil
+}
+
+// Delete stops watching the object. Once Delete returns no more
+// notifications or errors are delivered and the channels of the Watcher are
+// closed.
+//
+// Implements:
+//  int rados_unwatch2(rados_ioctx_t io, uint64_t cookie);
+//  int rados_watch_flush(rados_t cluster);
+func (w *Watcher) Delete() error {
+	var err error
+	w.once.Do(func() {
+		// unblock callbacks waiting for the channels to be read
+		close(w.done)
+		err = getRadosError(int(C.rados_unwatch2(w.ioctx.ioctx, w.id)))
+		// wait for the callbacks in flight
+		flushErr := getRadosError(int(C.rados_watch_flush(
+			C.rados_ioctx_get_cluster(w.ioctx.ioctx))))
+		if err == nil {
+			err = flushErr
+		}
+
+		watchers.Lock()
+		delete(watchers.m, uintptr(w.arg))
+		watchers.Unlock()
+		C.free(w.arg)
+		close(w.events)
+		close(w.errors)
+	})
+	return err
+}
+
+// Ack acknowledges the notification, passing the given response, which may
+// be nil, back to the notifier.
+//
+// Implements:
+//  int rados_notify_ack(rados_ioctx_t io, const char *o, uint64_t notify_id,
+//                       uint64_t cookie, const char *buf, int buf_len);
+func (ev NotifyEvent) Ack(response []byte) error {
+	c_oid := C.CString(ev.watcher.oid)
+	defer C.free(unsafe.Pointer(c_oid))
+
+	var buf *C.char
+	if len(response) > 0 {
+		buf = (*C.char)(unsafe.Pointer(&response[0]))
+	}
+	ret := C.rados_notify_ack(ev.watcher.ioctx.ioctx, c_oid,
+		C.uint64_t(ev.ID), ev.watcher.id, buf, C.int(len(response)))
+	return getRadosError(int(ret))
+}
+
+// NotifyTimeout identifies a watcher that did not acknowledge a notification
+// in time.
+type NotifyTimeout struct {
+	// WatcherID identifies the watch that did not acknowledge the
+	// notification.
+	WatcherID uint64
+	// NotifierID is the instance ID of the client owning the watch.
+	NotifierID uint64
+}
+
+// Notify sends a notification, with the given data as payload, to all the
+// watchers of the object with key oid and waits for them to acknowledge it,
+// or for the default timeout of the OSD to expire. The acknowledgements are
+// returned. If some of the watchers did not acknowledge the notification in
+// time the acknowledgements received are returned along with an error. Use
+// NotifyWithTimeout to find out which watchers timed out.
+func (ioctx *IOContext) Notify(oid string, data []byte) ([]NotifyAck, error) {
+	acks, timeouts, err := ioctx.NotifyWithTimeout(oid, data, 0)
+	if err == nil && len(timeouts) > 0 {
+		err = RadosError(-C.ETIMEDOUT)
+	}
+	return acks, err
+}
+
+// NotifyWithTimeout sends a notification, with the given data as payload,
+// to all the watchers of the object with key oid and waits for them to
+// acknowledge it, for at most the given timeout. A timeout of zero uses the
+// default timeout of the OSD. The acknowledgements, including the response
+// payloads of the watchers, are returned along with the watchers that did
+// not acknowledge the notification in time. Watchers timing out is not
+// reported as an error.
+//
+// Implements:
+//  int rados_notify2(rados_ioctx_t io, const char *o, const char *buf,
+//                    int buf_len, uint64_t timeout_ms, char **reply_buffer,
+//                    size_t *reply_buffer_len);
+func (ioctx *IOContext) NotifyWithTimeout(oid string, data []byte, timeout time.Duration) ([]NotifyAck, []NotifyTimeout, error) {
+	if timeout < 0 {
+		return nil, nil, RadosError(-C.EINVAL)
+	}
+	// round up so that short timeouts do not select the default one
+	timeoutMs := (timeout + time.Millisecond - 1) / time.Millisecond
+
+	c_oid := C.CString(oid)
+	defer C.free(unsafe.Pointer(c_oid))
+
+	var buf *C.char
+	if len(data) > 0 {
+		buf = (*C.char)(unsafe.Pointer(&data[0]))
+	}
+	var c_reply *C.char
+	var c_reply_len C.size_t
+	ret := C.rados_notify2(ioctx.ioctx, c_oid, buf, C.int(len(data)),
+		C.uint64_t(timeoutMs), &c_reply, &c_reply_len)
+	if c_reply != nil {
+		defer C.rados_buffer_free(c_reply)
+	}
+	// the reply lists the watchers that timed out
+	if ret < 0 && !(ret == -C.ETIMEDOUT && c_reply != nil) {
+		return nil, nil, getRadosError(int(ret))
+	}
+	return decodeNotifyReply(C.GoBytes(unsafe.Pointer(c_reply), C.int(c_reply_len)))
+}
+
+// decodeNotifyReply decodes the reply buffer of rados_notify2:
+//  le32 num_acks
+//  {
+//    le64 gid     global id for the client (for client.1234 that's 1234)
+//    le64 cookie  cookie for the client
+//    le32 buflen  length of reply message buffer
+//    u8 * buflen  payload
+//  } * num_acks
+//  le32 num_timeouts
+//  {
+//    le64 gid     global id for the client
+//    le64 cookie  cookie for the client
+//  } * num_timeouts
+func decodeNotifyReply(reply []byte) ([]NotifyAck, []NotifyTimeout, error) {
+	acks := []NotifyAck{}
+	timeouts := []NotifyTimeout{}
+	if len(reply) == 0 {
+		return acks, timeouts, nil
+	}
+	errBadReply := RadosError(-C.EINVAL)
+
+	if len(reply) < 4 {
+		return nil, nil, errBadReply
+	}
+	n := binary.LittleEndian.Uint32(reply)
+	reply = reply[4:]
+	for i := uint32(0); i < n; i++ {
+		if len(reply) < 20 {
+			return nil, nil, errBadReply
+		}
+		ack := NotifyAck{
+			NotifierID: binary.LittleEndian.Uint64(reply),
+			WatcherID:  binary.LittleEndian.Uint64(reply[8:]),
+		}
+		size := binary.LittleEndian.Uint32(reply[16:])
+		reply = reply[20:]
+		if uint64(len(reply)) < uint64(size) {
+			return nil, nil, errBadReply
+		}
+		if size > 0 {
+			ack.Response = append([]byte(nil), reply[:size]...)
+		}
+		reply = reply[size:]
+		acks = append(acks, ack)
+	}
+
+	if len(reply) < 4 {
+		return nil, nil, errBadReply
+	}
+	n = binary.LittleEndian.Uint32(reply)
+	reply = reply[4:]
+	if uint64(len(reply)) < 16*uint64(n) {
+		return nil, nil, errBadReply
+	}
+	for i := uint32(0); i < n; i++ {
+		timeouts = append(timeouts, NotifyTimeout{
+			NotifierID: binary.LittleEndian.Uint64(reply),
+			WatcherID:  binary.LittleEndian.Uint64(reply[8:]),
+		})
+		reply = reply[16:]
+	}
+	return acks, timeouts, nil
+}
+
+func lookupWatcher(arg unsafe.Pointer) *Watcher {
+	watchers.RLock()
+	defer watchers.RUnlock()
+	return watchers.m[uintptr(arg)]
+}
+
+//export watchNotifyCallback
+func watchNotifyCallback(arg unsafe.Pointer, notifyID, cookie, notifierID C.uint64_t,
+	data unsafe.Pointer, dataLen C.size_t) {
+	w := lookupWatcher(arg)
+	if w == nil {
+		return
+	}
+	ev := NotifyEvent{
+		ID:         uint64(notifyID),
+		WatcherID:  uint64(cookie),
+		NotifierID: uint64(notifierID),
+		Data:       C.GoBytes(data, C.int(dataLen)),
+		watcher:    w,
+	}
+	select {
+	case w.events <- ev:
+	case <-w.done:
+	}
+}
+
+//export watchErrorCallback
+func watchErrorCallback(pre unsafe.Pointer, cookie C.uint64_t, err C.int) {
+	w := lookupWatcher(pre)
+	if w == nil {
+		return
+	}
+	select {
+	case w.errors <- getRadosError(int(err)):
+	case <-w.done:
+	}
+}